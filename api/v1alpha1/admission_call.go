@@ -72,6 +72,23 @@ func (in *Call) ValidateCreate() (admission.Warnings, error) {
 		}
 	}
 
+	// Capture field
+	captured := make(map[string]struct{}, len(in.Spec.Capture))
+
+	for i := range in.Spec.Capture {
+		capture := &in.Spec.Capture[i]
+
+		if err := capture.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "capture error")
+		}
+
+		if _, exists := captured[capture.Name]; exists {
+			return nil, errors.Errorf("duplicate capture '%s'", capture.Name)
+		}
+
+		captured[capture.Name] = struct{}{}
+	}
+
 	// Tolerate field
 	if err := ValidateTolerate(in.Spec.Tolerate); err != nil {
 		return nil, errors.Wrapf(err, "tolerate error")
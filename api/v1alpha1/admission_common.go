@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/robfig/cron/v3"
@@ -35,8 +37,26 @@ func ValidateExpr(expr *ConditionalExpr) error {
 		return nil
 	}
 
-	if expr.HasStateExpr() {
-		if _, err := expr.State.GoValuate(DefaultClassifier{}); err != nil {
+	if expr.IsCompound() {
+		return validateCompoundExpr(expr)
+	}
+
+	if err := expr.Syntax.Validate(); err != nil {
+		return err
+	}
+
+	switch {
+	case expr.HasStateExpr() && expr.Syntax == ExprSyntaxCEL:
+		// variables are declared as a map, so the expression is type-checked without them.
+		if _, err := expr.State.CompileCEL(); err != nil {
+			return errors.Wrapf(err, "wrong state expr")
+		}
+
+	case expr.HasStateExpr():
+		// variables are captured at runtime. use dummy values just for the validation.
+		state := ExprState(MaskVariables(string(expr.State)))
+
+		if _, err := state.GoValuate(DefaultClassifier{}); err != nil {
 			return errors.Wrapf(err, "wrong state expr")
 		}
 	}
@@ -47,9 +67,89 @@ func ValidateExpr(expr *ConditionalExpr) error {
 		}
 	}
 
+	if expr.HasPromQLExpr() {
+		if expr.HasStateExpr() || expr.HasMetricsExpr() {
+			return errors.Errorf("promQL expr cannot be combined with state or metrics expr")
+		}
+
+		if _, err := expr.PromQL.Parse(); err != nil {
+			return errors.Wrapf(err, "wrong promQL expr")
+		}
+	}
+
+	return nil
+}
+
+// validateCompoundExpr validates the sub-expressions of the allOf, anyOf, and not compositions.
+// Metrics sub-expressions are set as alerts on the same object, which tracks a single alert. Therefore, a composition
+// may have at most one metrics sub-expression.
+func validateCompoundExpr(expr *ConditionalExpr) error {
+	compositions := 0
+
+	if len(expr.AllOf) > 0 {
+		compositions++
+	}
+
+	if len(expr.AnyOf) > 0 {
+		compositions++
+	}
+
+	if expr.Not != nil {
+		compositions++
+	}
+
+	if compositions > 1 || expr.Metrics != "" || expr.PromQL != "" || expr.State != "" || expr.Syntax != "" {
+		return errors.New("allOf, anyOf, and not cannot be combined with other expressions at the same level")
+	}
+
+	validateSubExpr := func(field string, sub *ConditionalExpr) error {
+		if sub.IsZero() {
+			return errors.Errorf("empty sub-expression in %s", field)
+		}
+
+		return errors.Wrapf(ValidateExpr(sub), "invalid sub-expression in %s", field)
+	}
+
+	for i := range expr.AllOf {
+		if err := validateSubExpr(fmt.Sprintf("allOf[%d]", i), &expr.AllOf[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range expr.AnyOf {
+		if err := validateSubExpr(fmt.Sprintf("anyOf[%d]", i), &expr.AnyOf[i]); err != nil {
+			return err
+		}
+	}
+
+	if expr.Not != nil {
+		if err := validateSubExpr("not", expr.Not); err != nil {
+			return err
+		}
+	}
+
+	if countMetricsExprs(expr) > 1 {
+		return errors.New("a composition may have at most one metrics sub-expression")
+	}
+
 	return nil
 }
 
+// countMetricsExprs counts the metrics expressions, including those of the sub-expressions.
+func countMetricsExprs(exprs ...*ConditionalExpr) int {
+	metrics := 0
+
+	for _, expr := range exprs {
+		for _, leaf := range expr.Leaves() {
+			if leaf.HasMetricsExpr() {
+				metrics++
+			}
+		}
+	}
+
+	return metrics
+}
+
 func ValidateTaskScheduler(sch *TaskSchedulerSpec) error {
 	var merr *multierror.Error
 
@@ -0,0 +1,324 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// shortLivedActions are actions whose jobs complete soon after they are created.
+// Their Running phase may never be observed, and therefore they cannot be Running dependencies.
+var shortLivedActions = map[ActionType]struct{}{
+	ActionCall:   {},
+	ActionDelete: {},
+}
+
+// dependencyKind is the state that an action waits for another action to reach.
+type dependencyKind string
+
+const (
+	dependsOnRunning   dependencyKind = "running"
+	dependsOnSuccess   dependencyKind = "success"
+	dependsOnFailed    dependencyKind = "failed"
+	dependsOnCompleted dependencyKind = "completed"
+)
+
+// dependency is an edge of the dependency graph.
+type dependency struct {
+	Kind   dependencyKind
+	Action string
+}
+
+// dependenciesOf returns the dependencies of the action, in the order they are declared.
+func dependenciesOf(action *Action) []dependency {
+	deps := action.DependsOn
+	if deps == nil {
+		return nil
+	}
+
+	var edges []dependency
+
+	for _, list := range []struct {
+		kind  dependencyKind
+		names []string
+	}{
+		{dependsOnRunning, deps.Running},
+		{dependsOnSuccess, deps.Success},
+		{dependsOnFailed, deps.Failed},
+		{dependsOnCompleted, deps.Completed},
+	} {
+		for _, name := range list.names {
+			edges = append(edges, dependency{Kind: list.kind, Action: name})
+		}
+	}
+
+	return edges
+}
+
+// upstreamOf returns the actions that must make progress before the given action can be scheduled.
+// That is the dependencies, the time anchor, and the guard of the action, if it belongs to an else branch.
+func upstreamOf(action *Action, guards map[string]string) []string {
+	var upstream []string
+
+	for _, dep := range dependenciesOf(action) {
+		upstream = append(upstream, dep.Action)
+	}
+
+	if deps := action.DependsOn; deps != nil && deps.Since != nil {
+		upstream = append(upstream, deps.Since.Action)
+	}
+
+	if guard, exists := guards[action.Name]; exists {
+		upstream = append(upstream, guard)
+	}
+
+	return upstream
+}
+
+// checkReferences ensures that the dependencies and the time anchor of the action point to other actions
+// of the scenario, and that Running dependencies do not point to short-lived actions.
+func checkReferences(action *Action, callIndex map[string]*Action) error {
+	for _, dep := range dependenciesOf(action) {
+		if dep.Action == action.Name {
+			return errors.Errorf("action [%s] has a %s dependency on itself", action.Name, dep.Kind)
+		}
+
+		target, exists := callIndex[dep.Action]
+		if !exists {
+			return errors.Errorf("action [%s] has a %s dependency on undefined action [%s]", action.Name, dep.Kind, dep.Action)
+		}
+
+		if _, shortLived := shortLivedActions[target.ActionType]; shortLived && dep.Kind == dependsOnRunning {
+			return errors.Errorf("action [%s] has a running dependency on [%s], but %s actions are short-lived. "+
+				"Use a success or completed dependency instead", action.Name, dep.Action, target.ActionType)
+		}
+	}
+
+	if deps := action.DependsOn; deps != nil && deps.Since != nil {
+		if deps.Since.Action == action.Name {
+			return errors.Errorf("action [%s] has a time anchor on itself", action.Name)
+		}
+
+		if _, exists := callIndex[deps.Since.Action]; !exists {
+			return errors.Errorf("action [%s] has a time anchor on undefined action [%s]", action.Name, deps.Since.Action)
+		}
+
+		if deps.After == nil {
+			return errors.Errorf("action [%s] has a time anchor without an after offset", action.Name)
+		}
+	}
+
+	return nil
+}
+
+// checkCycles ensures that no action waits, directly or transitively, for itself.
+// If a cycle exists, the returned error lists the actions that form the cycle.
+func checkCycles(actions []Action, guards map[string]string, callIndex map[string]*Action) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(actions))
+
+	var path []string
+
+	var visit func(name string) error
+
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			// the path contains the cycle, starting from the first appearance of the action.
+			for i, step := range path {
+				if step == name {
+					cycle := append(append([]string{}, path[i:]...), name)
+
+					return errors.Errorf("dependency cycle [%s]", strings.Join(cycle, "]->["))
+				}
+			}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, upstream := range upstreamOf(callIndex[name], guards) {
+			if err := visit(upstream); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, action := range actions {
+		if err := visit(action.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkReachability ensures that every action can be scheduled. An action is unreachable if it waits for
+// another action to reach conflicting states (e.g, both Success and Failed), or if it belongs to an else branch
+// and waits for its guard to be scheduled, although it only runs if the guard is skipped.
+func checkReachability(actions []Action, guards map[string]string) error {
+	for i := range actions {
+		action := &actions[i]
+
+		required := make(map[string]map[dependencyKind]struct{})
+
+		require := func(name string, kind dependencyKind) {
+			if required[name] == nil {
+				required[name] = make(map[dependencyKind]struct{})
+			}
+
+			required[name][kind] = struct{}{}
+		}
+
+		for _, dep := range dependenciesOf(action) {
+			require(dep.Action, dep.Kind)
+		}
+
+		// the anchor transition implies the state of the referenced action.
+		if deps := action.DependsOn; deps != nil && deps.Since != nil {
+			switch deps.Since.Phase {
+			case PhaseSuccess:
+				require(deps.Since.Action, dependsOnSuccess)
+			case PhaseFailed:
+				require(deps.Since.Action, dependsOnFailed)
+			}
+		}
+
+		for _, name := range orderedKeys(required) {
+			kinds := required[name]
+
+			if has(kinds, dependsOnSuccess) && has(kinds, dependsOnFailed) {
+				return errors.Errorf("action [%s] is unreachable. It waits for [%s] to be both successful and failed",
+					action.Name, name)
+			}
+
+			if has(kinds, dependsOnRunning) && (has(kinds, dependsOnSuccess) || has(kinds, dependsOnFailed) || has(kinds, dependsOnCompleted)) {
+				return errors.Errorf("action [%s] is unreachable. It waits for [%s] to be both running and completed",
+					action.Name, name)
+			}
+		}
+
+		// alternative branches run only if their guard is skipped. Skipped actions are never running or failed,
+		// and they have no transitions.
+		if guard, exists := guards[action.Name]; exists {
+			if kinds, waits := required[guard]; waits && (has(kinds, dependsOnRunning) || has(kinds, dependsOnFailed)) {
+				return errors.Errorf("action [%s] is unreachable. It waits for its guard [%s] to be scheduled, "+
+					"but it only runs if the guard is skipped", action.Name, guard)
+			}
+
+			if deps := action.DependsOn; deps != nil && deps.Since != nil && deps.Since.Action == guard {
+				return errors.Errorf("action [%s] is unreachable. It is anchored to a transition of its guard [%s], "+
+					"but it only runs if the guard is skipped", action.Name, guard)
+			}
+		}
+	}
+
+	return nil
+}
+
+// orderedKeys returns the keys of the map in a deterministic order.
+func orderedKeys(m map[string]map[dependencyKind]struct{}) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func has(kinds map[dependencyKind]struct{}, kind dependencyKind) bool {
+	_, exists := kinds[kind]
+
+	return exists
+}
+
+// ReferencedActions returns the actions that are referenced by the spec of the given action, in the order
+// they appear. These are the jobs of deletions, the services of calls, the cluster of scales, and the template
+// inputs that name an action (e.g, the targets of Chaos and Cascade actions). Unlike the inclusion of scenario
+// fragments, plain input values that match the name of an action are regarded as references, since the
+// references are only informative.
+func ReferencedActions(action *Action, callIndex map[string]*Action) []string {
+	if action.EmbedActions == nil {
+		return nil
+	}
+
+	var referenced []string
+
+	seen := make(map[string]struct{})
+
+	record := func(name string) string {
+		if _, exists := callIndex[name]; exists && name != action.Name {
+			if _, duplicate := seen[name]; !duplicate {
+				seen[name] = struct{}{}
+				referenced = append(referenced, name)
+			}
+		}
+
+		return name
+	}
+
+	// the instances of a cluster are named after the cluster, e.g, 'masters-1'.
+	recordService := func(name string) string {
+		if sep := strings.LastIndex(name, "-"); sep > 0 {
+			if _, exists := callIndex[name]; !exists {
+				if _, err := strconv.Atoi(name[sep+1:]); err == nil {
+					return record(name[:sep])
+				}
+			}
+		}
+
+		return record(name)
+	}
+
+	// the renaming is applied on a copy, and only records the names of the referenced actions.
+	// malformed inputs are reported by the admission webhook, and are ignored here.
+	spec := action.EmbedActions.DeepCopy()
+
+	_ = renameReferences(spec, record, recordService)
+
+	for _, input := range inputsOf(spec) {
+		for _, value := range input {
+			var str string
+
+			if err := json.Unmarshal(value.Raw, &str); err == nil {
+				recordService(str)
+			}
+		}
+	}
+
+	return referenced
+}
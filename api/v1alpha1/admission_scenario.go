@@ -17,12 +17,22 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -42,44 +52,168 @@ var _ webhook.Validator = &Scenario{}
 var scenariolog = logf.Log.WithName("scenario-hook")
 
 func (in *Scenario) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// The webhooks are registered before the builder, which then skips its own defaulting and validating webhooks.
+	mgr.GetWebhookServer().Register("/mutate-frisbee-dev-v1alpha1-scenario",
+		&webhook.Admission{Handler: &scenarioDefaulter{}})
+
+	mgr.GetWebhookServer().Register("/validate-frisbee-dev-v1alpha1-scenario",
+		&webhook.Admission{Handler: NewScenarioValidator(mgr.GetScheme(), mgr.GetClient())})
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(in).
 		Complete()
 }
 
+// scenarioDefaulter serves the mutating webhook of scenarios. Unlike the defaulting webhook of the builder,
+// it expands the parameters of the raw scenario before decoding it, and then calls Default() on the expanded scenario.
+type scenarioDefaulter struct{}
+
+func (d *scenarioDefaulter) Handle(_ context.Context, req admission.Request) admission.Response {
+	expanded, err := ExpandScenario(req.Object.Raw)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var scenario Scenario
+
+	if err := json.Unmarshal(expanded, &scenario); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	scenario.Default()
+
+	defaulted, err := json.Marshal(&scenario)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, defaulted)
+}
+
+// NewScenarioValidator returns the handler of the validating webhook of scenarios. In addition to ValidateCreate(),
+// it checks that the requester is allowed to impersonate the ServiceAccounts of the Helm actions, because the
+// controller impersonates them on behalf of the requester.
+func NewScenarioValidator(scheme *runtime.Scheme, c client.Client) admission.Handler {
+	return &scenarioValidator{
+		client:    c,
+		decoder:   admission.NewDecoder(scheme),
+		validator: admission.ValidatingWebhookFor(scheme, &Scenario{}),
+	}
+}
+
+type scenarioValidator struct {
+	client    client.Client
+	decoder   *admission.Decoder
+	validator *admission.Webhook
+}
+
+func (v *scenarioValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	resp := v.validator.Handle(ctx, req)
+	if !resp.Allowed || req.Operation != admissionv1.Create {
+		return resp
+	}
+
+	var scenario Scenario
+
+	if err := v.decoder.Decode(req, &scenario); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	for _, action := range append(scenario.Spec.Actions, scenario.Spec.Finally...) {
+		if action.ActionType != ActionHelm || action.Helm == nil || action.Helm.ServiceAccountName == "" {
+			continue
+		}
+
+		allowed, err := v.canImpersonate(ctx, req.UserInfo, req.Namespace, action.Helm.ServiceAccountName)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+
+		if !allowed {
+			return admission.Denied(fmt.Sprintf("action '%s': user '%s' is not allowed to impersonate serviceaccount '%s'",
+				action.Name, req.UserInfo.Username, action.Helm.ServiceAccountName))
+		}
+	}
+
+	return resp
+}
+
+// canImpersonate asks the API server whether the user is allowed to impersonate the ServiceAccount.
+func (v *scenarioValidator) canImpersonate(ctx context.Context, user authenticationv1.UserInfo, namespace, name string) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+
+	for key, values := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(values)
+	}
+
+	review := authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "impersonate",
+				Resource:  "serviceaccounts",
+				Name:      name,
+			},
+		},
+	}
+
+	if err := v.client.Create(ctx, &review); err != nil {
+		return false, errors.Wrapf(err, "cannot review access to serviceaccount '%s'", name)
+	}
+
+	return review.Status.Allowed, nil
+}
+
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
+// The parameters of the scenario are expanded before decoding, by the mutating webhook.
 func (in *Scenario) Default() {
 	scenariolog.Info("default", "name", in.Name)
 
 	// Align Inputs with MaxInstances
 	for i := 0; i < len(in.Spec.Actions); i++ {
-		action := &in.Spec.Actions[i]
+		prepareAction(&in.Spec.Actions[i])
+	}
 
-		switch action.ActionType {
-		case ActionService:
-			if err := action.Service.Prepare(false); err != nil {
-				scenariolog.Error(err, "definition error", "action", action.Name)
-			}
+	for i := 0; i < len(in.Spec.Finally); i++ {
+		prepareAction(&in.Spec.Finally[i])
+	}
+}
 
-		case ActionCluster:
-			if err := action.Cluster.GenerateObjectFromTemplate.Prepare(true); err != nil {
-				scenariolog.Error(err, "definition error", "action", action.Name)
-			}
+// prepareAction aligns the inputs of the action's template with the expected instances.
+func prepareAction(action *Action) {
+	switch action.ActionType {
+	case ActionService:
+		if err := action.Service.Prepare(false); err != nil {
+			scenariolog.Error(err, "definition error", "action", action.Name)
+		}
 
-		case ActionChaos:
-			if err := action.Chaos.Prepare(false); err != nil {
-				scenariolog.Error(err, "definition error", "action", action.Name)
-			}
+	case ActionCluster:
+		if err := action.Cluster.GenerateObjectFromTemplate.Prepare(true); err != nil {
+			scenariolog.Error(err, "definition error", "action", action.Name)
+		}
 
-		case ActionCascade:
-			if err := action.Cascade.GenerateObjectFromTemplate.Prepare(true); err != nil {
-				scenariolog.Error(err, "definition error", "action", action.Name)
-			}
+	case ActionChaos:
+		if err := action.Chaos.Prepare(false); err != nil {
+			scenariolog.Error(err, "definition error", "action", action.Name)
+		}
 
-		case ActionCall, ActionDelete:
-			// calls and deletes do not involve templates.
-			continue
+	case ActionCascade:
+		if err := action.Cascade.GenerateObjectFromTemplate.Prepare(true); err != nil {
+			scenariolog.Error(err, "definition error", "action", action.Name)
 		}
+
+	case ActionApply:
+		if err := action.Apply.Prepare(false); err != nil {
+			scenariolog.Error(err, "definition error", "action", action.Name)
+		}
+
+	case ActionCall, ActionDelete, ActionScenario, ActionWait, ActionScale, ActionHelm:
+		// calls, deletes, includes, waits, scales, and helm releases do not involve templates.
+		return
 	}
 }
 
@@ -90,37 +224,110 @@ func (in *Scenario) ValidateCreate() (admission.Warnings, error) {
 		return nil, errors.Wrapf(err, "invalid scenario [%s]", in.GetName())
 	}
 
-	for i, action := range in.Spec.Actions {
+	if deadline := in.Spec.ActiveDeadline; deadline != nil && deadline.Duration <= 0 {
+		return nil, errors.Errorf("invalid deadline '%s' for scenario [%s]", deadline.Duration, in.GetName())
+	}
+
+	if err := validateActions(in.Spec.Actions, legitReferences); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateVerdict(in); err != nil {
+		return nil, errors.Wrapf(err, "verdict error")
+	}
+
+	if err := ValidateAlerts(in); err != nil {
+		return nil, errors.Wrapf(err, "alerts error")
+	}
+
+	if err := ValidateRestarts(in, legitReferences); err != nil {
+		return nil, errors.Wrapf(err, "restarts error")
+	}
+
+	// the remaining jobs are removed once SuccessWhen is met. Therefore, long-lived jobs are allowed.
+	if in.Spec.SuccessWhen.IsZero() {
+		if err := CheckForBoundedExecution(legitReferences); err != nil {
+			return nil, errors.Wrapf(err, "infinity error")
+		}
+	}
+
+	if err := ValidateFinally(in, legitReferences); err != nil {
+		return nil, errors.Wrapf(err, "finally error")
+	}
+
+	if err := ValidatePostConditions(in.Spec.PostConditions); err != nil {
+		return nil, errors.Wrapf(err, "post-conditions error")
+	}
+
+	if err := ValidateRepeat(in.Spec.Repeat); err != nil {
+		return nil, errors.Wrapf(err, "repeat error")
+	}
+
+	return nil, nil
+}
+
+// validateActions validates the expressions, the deadlines, and the type-specific spec of every action.
+func validateActions(actions []Action, references map[string]*Action) error {
+	for i, action := range actions {
 		// Check that expressions used in the assertions are ok
 		if !action.Assert.IsZero() {
 			if err := ValidateExpr(action.Assert); err != nil {
-				return nil, errors.Wrapf(err, "Invalid expr in assertion")
+				return errors.Wrapf(err, "Invalid expr in assertion")
 			}
 		}
 
+		// Check that expressions used in the conditions are ok
+		if !action.When.IsZero() {
+			if err := ValidateExpr(action.When); err != nil {
+				return errors.Wrapf(err, "Invalid expr in condition")
+			}
+		}
+
+		// Check that the deadline is meaningful
+		if deadline := action.ActiveDeadline; deadline != nil && deadline.Duration <= 0 {
+			return errors.Errorf("invalid deadline '%s' for action [%s]", deadline.Duration, action.Name)
+		}
+
 		// Ensure that the type of action is supported and is correctly set
-		if err := CheckAction(&in.Spec.Actions[i], legitReferences); err != nil {
-			return nil, errors.Wrapf(err, "incorrent spec for type [%s] of action [%s]", action.ActionType, action.Name)
+		if err := CheckAction(&actions[i], references); err != nil {
+			return errors.Wrapf(err, "incorrent spec for type [%s] of action [%s]", action.ActionType, action.Name)
 		}
 	}
 
-	if err := CheckForBoundedExecution(legitReferences); err != nil {
-		return nil, errors.Wrapf(err, "infinity error")
+	return nil
+}
+
+// ValidateFragment validates a scenario fragment that is included by the actions of other scenarios.
+// Unlike scenarios, fragments may leave actions running, since their termination is left to the parent scenario.
+func ValidateFragment(fragment *ScenarioSpec) error {
+	if len(fragment.Finally) > 0 || fragment.ActiveDeadline != nil || fragment.Repeat != nil || fragment.TestData != nil ||
+		fragment.SuccessWhen != nil || fragment.FailWhen != nil || len(fragment.PostConditions) > 0 {
+		return errors.New("fragments do not support finally, activeDeadline, repeat, testData, successWhen, failWhen, or postConditions")
 	}
 
-	return nil, nil
+	scenario := Scenario{Spec: *fragment.DeepCopy()}
+	scenario.Default()
+
+	references, err := BuildDependencyGraph(&scenario)
+	if err != nil {
+		return err
+	}
+
+	return validateActions(scenario.Spec.Actions, references)
 }
 
 // BuildDependencyGraph validates the execution workflow.
 // 1. Ensures that action names are qualified (since they are used as generators to jobs)
 // 2. Ensures that there are no two actions with the same name.
-// 3. Ensure that dependencies point to a valid action.
-// 4. Ensure that macros point to a valid action.
+// 3. Ensure that dependencies (and time anchors) point to a valid action.
+// 4. Ensure that Running dependencies do not point to short-lived actions (e.g, Call, Delete).
+// 5. Ensure that conditional branches point to a valid action.
+// 6. Ensure that there are no cycles among dependencies, time anchors, and conditional branches.
+// 7. Ensure that every action is reachable, i.e, it does not wait for conflicting states.
 func BuildDependencyGraph(scenario *Scenario) (map[string]*Action, error) {
 	// callIndex maintains a map of all the action in the scenario
 	callIndex := make(map[string]*Action, len(scenario.Spec.Actions))
 
-	// prepare a dependency graph
 	for i, action := range scenario.Spec.Actions {
 		// Because the action name will be the "matrix" for generating addressable jobs,
 		// it must adhere to certain properties.
@@ -130,21 +337,6 @@ func BuildDependencyGraph(scenario *Scenario) (map[string]*Action, error) {
 			return nil, errors.Wrapf(err, "invalid actioname %s", action.Name)
 		}
 
-		// validate references dependencies
-		if deps := action.DependsOn; deps != nil {
-			for _, dep := range deps.Running {
-				if _, exists := callIndex[dep]; !exists {
-					return nil, errors.Errorf("invalid running dependency: [%s]<-[%s]", action.Name, dep)
-				}
-			}
-
-			for _, dep := range deps.Success {
-				if _, exists := callIndex[dep]; !exists {
-					return nil, errors.Errorf("invalid success dependency: [%s]<-[%s]", action.Name, dep)
-				}
-			}
-		}
-
 		// update calling map
 		if _, exists := callIndex[action.Name]; !exists {
 			callIndex[action.Name] = &scenario.Spec.Actions[i]
@@ -153,9 +345,263 @@ func BuildDependencyGraph(scenario *Scenario) (map[string]*Action, error) {
 		}
 	}
 
+	// validate references dependencies. Dependencies may point to subsequent actions.
+	for i := range scenario.Spec.Actions {
+		if err := checkReferences(&scenario.Spec.Actions[i], callIndex); err != nil {
+			return nil, err
+		}
+	}
+
+	// validate conditional branches.
+	guards := make(map[string]string)
+
+	for _, action := range scenario.Spec.Actions {
+		if len(action.Else) > 0 && action.When.IsZero() {
+			return nil, errors.Errorf("action [%s] has an else branch without a when condition", action.Name)
+		}
+
+		for _, alt := range action.Else {
+			if _, exists := callIndex[alt]; !exists {
+				return nil, errors.Errorf("invalid else branch: [%s]->[%s]", action.Name, alt)
+			}
+
+			if alt == action.Name {
+				return nil, errors.Errorf("action [%s] cannot be an alternative of itself", action.Name)
+			}
+
+			if guard, exists := guards[alt]; exists {
+				return nil, errors.Errorf("action [%s] is an alternative of both [%s] and [%s]", alt, guard, action.Name)
+			}
+
+			guards[alt] = action.Name
+		}
+	}
+
+	// an action cannot wait (transitively) for itself, neither through dependencies nor through guards.
+	if err := checkCycles(scenario.Spec.Actions, guards, callIndex); err != nil {
+		return nil, err
+	}
+
+	if err := checkReachability(scenario.Spec.Actions, guards); err != nil {
+		return nil, err
+	}
+
 	return callIndex, nil
 }
 
+// ValidateFinally validates the finally actions.
+// 1. Ensures that action names are qualified and unique across the scenario.
+// 2. Ensures that finally actions do not depend on other actions, since they run in parallel.
+// 3. Ensures that references point to actions of the scenario.
+func ValidateFinally(scenario *Scenario, references map[string]*Action) error {
+	finallyIndex := make(map[string]*Action, len(scenario.Spec.Finally))
+
+	for i, action := range scenario.Spec.Finally {
+		if errs := validation.IsDNS1123Subdomain(action.Name); errs != nil {
+			err := errors.New(strings.Join(errs, "; "))
+
+			return errors.Wrapf(err, "invalid actioname %s", action.Name)
+		}
+
+		if _, exists := references[action.Name]; exists {
+			return errors.Errorf("Duplicate action '%s'", action.Name)
+		}
+
+		if _, exists := finallyIndex[action.Name]; exists {
+			return errors.Errorf("Duplicate action '%s'", action.Name)
+		}
+
+		if action.DependsOn != nil || !action.Assert.IsZero() || len(action.Else) > 0 || action.ActiveDeadline != nil {
+			return errors.Errorf("finally action [%s] does not support depends, assert, else, or activeDeadline", action.Name)
+		}
+
+		// these actions are resolved by the scenario controller, which does not track the finally actions.
+		if action.ActionType == ActionScenario || action.ActionType == ActionWait || action.ActionType == ActionApply {
+			return errors.Errorf("finally action [%s] cannot include a scenario, wait, or apply", action.Name)
+		}
+
+		if !action.When.IsZero() {
+			if err := ValidateExpr(action.When); err != nil {
+				return errors.Wrapf(err, "Invalid expr in condition")
+			}
+
+			// the conditions of the finally actions are not set as alerts, and would read the alerts of other expressions.
+			if countMetricsExprs(action.When) > 0 {
+				return errors.Errorf("finally action [%s] does not support metrics conditions", action.Name)
+			}
+		}
+
+		if err := CheckAction(&scenario.Spec.Finally[i], references); err != nil {
+			return errors.Wrapf(err, "incorrent spec for type [%s] of action [%s]", action.ActionType, action.Name)
+		}
+
+		finallyIndex[action.Name] = &scenario.Spec.Finally[i]
+	}
+
+	return nil
+}
+
+// ValidateVerdict validates the expressions that declare the outcome of the scenario (SuccessWhen, FailWhen).
+func ValidateVerdict(scenario *Scenario) error {
+	for rule, expr := range map[string]*ConditionalExpr{
+		VerdictSuccessWhen: scenario.Spec.SuccessWhen,
+		VerdictFailWhen:    scenario.Spec.FailWhen,
+	} {
+		if err := ValidateExpr(expr); err != nil {
+			return errors.Wrapf(err, "invalid expr in %s", rule)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAlerts ensures that the scenario has at most one metrics expression. The metrics expressions of the
+// verdict, and of the When and Assert of the actions, are set as alerts on the scenario, and the scenario tracks
+// a single alert. Therefore, a fired alert would be mistaken for the alert of every other metrics expression
+// (e.g, a guard that is met would fail the metrics assertions).
+func ValidateAlerts(scenario *Scenario) error {
+	metrics := countMetricsExprs(scenario.Spec.SuccessWhen, scenario.Spec.FailWhen)
+
+	for _, action := range scenario.Spec.Actions {
+		metrics += countMetricsExprs(action.Assert, action.When)
+	}
+
+	if metrics > 1 {
+		return errors.Errorf("found %d metrics expressions in successWhen, failWhen, when, and assert. "+
+			"The scenario tracks a single alert, and therefore supports at most one", metrics)
+	}
+
+	return nil
+}
+
+// restartsFunctionCall matches the calls of the restarts function, along with their arguments,
+// in both the template (e.g, '.NumRestarts "a"') and the CEL syntax (e.g, 'numRestarts("a")').
+var restartsFunctionCall = regexp.MustCompile(`\.NumRestarts((?:\s+"[^"]*")+)|\bnumRestarts\(([^)]*)\)`)
+
+// ValidateRestarts ensures that the restarts are asserted only on the actions that keep track of them.
+// Restarts are counted on the containers of services, and are aggregated by clusters. Other kinds of jobs
+// have no containers, and therefore their restarts would always be zero.
+func ValidateRestarts(scenario *Scenario, references map[string]*Action) error {
+	exprs := []*ConditionalExpr{scenario.Spec.SuccessWhen, scenario.Spec.FailWhen}
+
+	for _, action := range scenario.Spec.Actions {
+		exprs = append(exprs, action.Assert, action.When)
+
+		if action.ActionType == ActionWait && action.EmbedActions != nil && action.Wait != nil {
+			exprs = append(exprs, action.Wait.Until)
+		}
+	}
+
+	for _, expr := range exprs {
+		for _, leaf := range expr.Leaves() {
+			for _, call := range restartsFunctionCall.FindAllString(string(leaf.State), -1) {
+				for _, literal := range stringLiteral.FindAllString(call, -1) {
+					jobName := literal[1 : len(literal)-1]
+
+					// unknown names may refer to the jobs of an included fragment.
+					action, exists := references[jobName]
+					if !exists || action.ActionType == ActionService || action.ActionType == ActionCluster {
+						continue
+					}
+
+					return errors.Errorf("restarts of action '%s' are not tracked, since it is of type [%s]. "+
+						"Only [%s, %s] are supported", jobName, action.ActionType, ActionService, ActionCluster)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidatePostConditions validates the post-conditions of the scenario.
+// 1. Ensures that post-condition names are unique.
+// 2. Ensures that every post-condition defines either a state expression, or a value expression with a range.
+// 3. Ensures that the expressions are valid.
+func ValidatePostConditions(postConditions []PostCondition) error {
+	names := make(map[string]struct{}, len(postConditions))
+
+	for _, postCondition := range postConditions {
+		if postCondition.Name == "" {
+			return errors.Errorf("empty post-condition name")
+		}
+
+		if _, exists := names[postCondition.Name]; exists {
+			return errors.Errorf("duplicate post-condition '%s'", postCondition.Name)
+		}
+
+		names[postCondition.Name] = struct{}{}
+
+		switch {
+		case postCondition.State != "" && (postCondition.Value != "" || postCondition.Is != ""):
+			return errors.Errorf("post-condition '%s' must define either state, or value, but not both", postCondition.Name)
+
+		case postCondition.Syntax != "" && postCondition.State == "":
+			return errors.Errorf("post-condition '%s' defines a syntax without a state expression", postCondition.Name)
+
+		case postCondition.State != "":
+			if err := ValidateExpr(&ConditionalExpr{State: postCondition.State, Syntax: postCondition.Syntax}); err != nil {
+				return errors.Wrapf(err, "post-condition '%s'", postCondition.Name)
+			}
+
+		case postCondition.Value != "":
+			if _, err := postCondition.Value.Parse(); err != nil {
+				return errors.Wrapf(err, "invalid value for post-condition '%s'", postCondition.Name)
+			}
+
+			if postCondition.Is == "" {
+				return errors.Errorf("post-condition '%s' must define the range of the value", postCondition.Name)
+			}
+
+			if _, _, err := postCondition.Is.Parse(); err != nil {
+				return errors.Wrapf(err, "post-condition '%s'", postCondition.Name)
+			}
+
+		default:
+			return errors.Errorf("post-condition '%s' must define either state, or value", postCondition.Name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRepeat validates the repetition of the scenario.
+// 1. Ensures that there is at least one trial, and that the cooldown is not negative.
+// 2. Ensures that metric names are unique, and that their value expressions are valid.
+func ValidateRepeat(repeat *RepeatSpec) error {
+	if repeat == nil {
+		return nil
+	}
+
+	if repeat.Trials < 1 {
+		return errors.Errorf("trials must be at least 1, but got '%d'", repeat.Trials)
+	}
+
+	if repeat.Cooldown != nil && repeat.Cooldown.Duration < 0 {
+		return errors.Errorf("invalid cooldown '%s'", repeat.Cooldown.Duration)
+	}
+
+	metrics := make(map[string]struct{}, len(repeat.Metrics))
+
+	for _, metric := range repeat.Metrics {
+		if metric.Name == "" {
+			return errors.Errorf("empty metric name")
+		}
+
+		if _, exists := metrics[metric.Name]; exists {
+			return errors.Errorf("duplicate metric '%s'", metric.Name)
+		}
+
+		if _, err := metric.Value.Parse(); err != nil {
+			return errors.Wrapf(err, "invalid value for metric '%s'", metric.Name)
+		}
+
+		metrics[metric.Name] = struct{}{}
+	}
+
+	return nil
+}
+
 func CheckForBoundedExecution(callIndex map[string]*Action) error {
 	// Use transactions as a means to detect looping containers that never terminate within
 	// the lifespan of the scenario. If so, the experiment never ends and waste resources.
@@ -170,7 +616,7 @@ func CheckForBoundedExecution(callIndex map[string]*Action) error {
 
 	// Do a mockup "run" and mark completed jobs
 	for _, action := range callIndex {
-		// Successful actions are regarded as completed.
+		// Successful, Failed, and Completed actions are regarded as completed.
 		if deps := action.DependsOn; deps != nil {
 			for _, dep := range deps.Success {
 				if _, exists := callIndex[dep]; !exists {
@@ -179,6 +625,22 @@ func CheckForBoundedExecution(callIndex map[string]*Action) error {
 
 				jobCompletionIndex[dep] = true
 			}
+
+			for _, dep := range deps.Failed {
+				if _, exists := callIndex[dep]; !exists {
+					return errors.Errorf("invalid failed dependency [%s]<-[%s]", action.Name, dep)
+				}
+
+				jobCompletionIndex[dep] = true
+			}
+
+			for _, dep := range deps.Completed {
+				if _, exists := callIndex[dep]; !exists {
+					return errors.Errorf("invalid completed dependency [%s]<-[%s]", action.Name, dep)
+				}
+
+				jobCompletionIndex[dep] = true
+			}
 		}
 
 		// Deleted actions are regarded as completed.
@@ -310,6 +772,83 @@ func CheckAction(action *Action, references map[string]*Action) error {
 		_, err := call.ValidateCreate()
 		return err
 
+	case ActionScenario:
+		if action.EmbedActions.Scenario == nil {
+			return errors.Errorf("empty scenario definition")
+		}
+
+		if action.EmbedActions.Scenario.TemplateRef == "" {
+			return errors.Errorf("empty templateRef")
+		}
+
+		return nil
+
+	case ActionApply:
+		if action.EmbedActions.Apply == nil {
+			return errors.Errorf("empty apply definition")
+		}
+
+		if action.EmbedActions.Apply.TemplateRef == "" {
+			return errors.Errorf("empty templateRef")
+		}
+
+		return nil
+
+	case ActionHelm:
+		if action.EmbedActions.Helm == nil {
+			return errors.Errorf("empty helm definition")
+		}
+
+		return errors.Wrapf(action.EmbedActions.Helm.Validate(), "invalid helm")
+
+	case ActionScale:
+		if action.EmbedActions.Scale == nil {
+			return errors.Errorf("empty scale definition")
+		}
+
+		scale := action.EmbedActions.Scale
+
+		target, exists := references[scale.Cluster]
+		if !exists || target.ActionType != ActionCluster {
+			return errors.Errorf("referenced cluster '%s' does not exist", scale.Cluster)
+		}
+
+		// clusters whose instances are bound to a timeline, or to a condition, cannot be scaled.
+		if !target.Cluster.SuspendWhen.IsZero() ||
+			(target.Cluster.Schedule != nil && target.Cluster.Schedule.Timeline != nil) {
+			return errors.Errorf("cluster '%s' with suspendWhen or timeline cannot be scaled", scale.Cluster)
+		}
+
+		// the instances are known at runtime. use a dummy value just for the validation.
+		if _, err := scale.Instances(math.MaxInt16); err != nil {
+			return errors.Wrapf(err, "invalid scale")
+		}
+
+		return nil
+
+	case ActionWait:
+		if action.EmbedActions.Wait == nil {
+			return errors.Errorf("empty wait definition")
+		}
+
+		wait := action.EmbedActions.Wait
+
+		if wait.Duration == nil && wait.Until.IsZero() {
+			return errors.Errorf("wait requires a duration, or a condition")
+		}
+
+		if wait.Duration != nil && wait.Duration.Duration <= 0 {
+			return errors.Errorf("invalid duration '%s'", wait.Duration.Duration)
+		}
+
+		if !wait.Until.IsZero() {
+			if err := ValidateExpr(wait.Until); err != nil {
+				return errors.Wrapf(err, "Invalid expr in wait")
+			}
+		}
+
+		return nil
+
 	default:
 		return errors.Errorf("Unknown action")
 	}
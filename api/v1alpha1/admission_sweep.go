@@ -0,0 +1,145 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate-frisbee-dev-v1alpha1-sweep,mutating=true,failurePolicy=fail,sideEffects=None,groups=frisbee.dev,resources=sweeps,verbs=create;update,versions=v1alpha1,name=msweep.kb.io,admissionReviewVersions={v1,v1alpha1}
+
+var _ webhook.Defaulter = &Sweep{}
+
+// +kubebuilder:webhook:path=/validate-frisbee-dev-v1alpha1-sweep,mutating=false,failurePolicy=fail,sideEffects=None,groups=frisbee.dev,resources=sweeps,verbs=create,versions=v1alpha1,name=vsweep.kb.io,admissionReviewVersions={v1,v1alpha1}
+
+var _ webhook.Validator = &Sweep{}
+
+// log is for logging in this package.
+var sweeplog = logf.Log.WithName("sweep-hook")
+
+// axisName matches the names that can be referenced as {{.inputs.parameters.<name>}}.
+var axisName = regexp.MustCompile(`^\w+$`)
+
+func (in *Sweep) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		Complete()
+}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (in *Sweep) Default() {
+	sweeplog.Info("default", "name", in.Name)
+
+	if in.Spec.Repetitions == 0 {
+		in.Spec.Repetitions = 1
+	}
+
+	if in.Spec.Parallelism == 0 {
+		in.Spec.Parallelism = 1
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (in *Sweep) ValidateCreate() (admission.Warnings, error) {
+	sweeplog.Info("validate create", "name", in.Name)
+
+	if len(in.GetName()) > MaxSweepNameLength {
+		return nil, errors.Errorf("name '%s' exceeds %d characters", in.GetName(), MaxSweepNameLength)
+	}
+
+	if in.Spec.Repetitions < 1 {
+		return nil, errors.Errorf("invalid repetitions '%d'", in.Spec.Repetitions)
+	}
+
+	if in.Spec.Parallelism < 1 {
+		return nil, errors.Errorf("invalid parallelism '%d'", in.Spec.Parallelism)
+	}
+
+	if err := ValidateTolerate(in.Spec.Tolerate); err != nil {
+		return nil, errors.Wrapf(err, "tolerate error")
+	}
+
+	// Validate the axes
+	axes := make(map[string]struct{}, len(in.Spec.Axes))
+
+	for _, axis := range in.Spec.Axes {
+		if !axisName.MatchString(axis.Name) {
+			return nil, errors.Errorf("invalid axis name '%s'", axis.Name)
+		}
+
+		if _, exists := axes[axis.Name]; exists {
+			return nil, errors.Errorf("duplicate axis '%s'", axis.Name)
+		}
+
+		if len(axis.Values) == 0 {
+			return nil, errors.Errorf("axis '%s' has no values", axis.Name)
+		}
+
+		axes[axis.Name] = struct{}{}
+	}
+
+	if len(axes) == 0 {
+		return nil, errors.Errorf("at least one axis is required")
+	}
+
+	// Validate the generated scenarios. Repetitions of the same combination yield the same scenario,
+	// so it suffices to validate the first repetition.
+	for _, run := range in.GenerateRuns() {
+		// Because the run name is used for the namespace of the scenario, it must be a valid label.
+		if errs := validation.IsDNS1123Label(run.Name); errs != nil {
+			err := errors.New(strings.Join(errs, "; "))
+
+			return nil, errors.Wrapf(err, "invalid run name '%s'", run.Name)
+		}
+
+		if run.Repetition > 0 {
+			continue
+		}
+
+		scenario, err := in.GenerateScenario(run)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scenario error")
+		}
+
+		scenario.Default()
+
+		if _, err := scenario.ValidateCreate(); err != nil {
+			return nil, errors.Wrapf(err, "run '%s'", run.Name)
+		}
+	}
+
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (in *Sweep) ValidateUpdate(runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (in *Sweep) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
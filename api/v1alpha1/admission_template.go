@@ -65,7 +65,11 @@ func (in *Template) ValidateCreate() (admission.Warnings, error) {
 
 func (in *Template) validateTemplateLanguage() error {
 	{ // Ensure the template is ok and there are no brackets missing.
-		body, err := json.Marshal(in.Spec)
+		// scenario fragments use the parameters of the scenario language, which are expanded upon inclusion.
+		spec := in.Spec.DeepCopy()
+		spec.Scenario = nil
+
+		body, err := json.Marshal(spec)
 		if err != nil {
 			return errors.Wrapf(err, "marshal error")
 		}
@@ -95,6 +99,34 @@ func (in *Template) validateTemplateLanguage() error {
 		return errors.Wrapf(err, "service definition error")
 	}
 
+	if in.Spec.Scenario != nil {
+		fragment, err := GenerateScenarioSpec(in.Spec.Scenario.Raw, nil)
+		if err != nil {
+			return errors.Wrapf(err, "scenario definition error")
+		}
+
+		return errors.Wrapf(ValidateFragment(fragment), "scenario definition error")
+	}
+
+	if in.Spec.Manifests != nil {
+		manifests := *in.Spec.Manifests
+
+		if in.Spec.Inputs != nil {
+			raw, err := ExprState(manifests.Raw).Evaluate(struct {
+				Inputs *TemplateInputs `json:"inputs"`
+			}{
+				in.Spec.Inputs,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "manifests template error")
+			}
+
+			manifests.Raw = raw
+		}
+
+		return errors.Wrapf(manifests.CheckKinds(), "manifests definition error")
+	}
+
 	if in.Spec.Chaos != nil {
 		chaos := Chaos{
 			Spec: *in.Spec.Chaos,
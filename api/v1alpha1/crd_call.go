@@ -69,6 +69,11 @@ type CallSpec struct {
 	// +optional
 	Expect []MatchOutputs `json:"expect,omitempty"`
 
+	// Capture extracts values from the outputs of the calls into scenario variables. If the call has multiple
+	// invocations, the variables hold the values captured by the latest invocation.
+	// +optional
+	Capture []CaptureSpec `json:"capture,omitempty"`
+
 	/*
 		Execution Flow
 	*/
@@ -103,6 +108,10 @@ type CallStatus struct {
 
 	// LastScheduleTime provide information about  the last time a Service was successfully scheduled.
 	LastScheduleTime metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Captured are the values extracted from the outputs of the calls, as defined by Capture.
+	// +optional
+	Captured map[string]string `json:"captured,omitempty"`
 }
 
 func (in *Call) GetReconcileStatus() Lifecycle {
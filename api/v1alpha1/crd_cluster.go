@@ -97,6 +97,12 @@ type ClusterSpec struct {
 	// Tolerate forces the Controller to continue in spite of failed jobs.
 	// +optional
 	Tolerate *TolerateSpec `json:"tolerate,omitempty"`
+
+	// RemovalOrder defines which services are removed first, when the instances are decreased while the cluster
+	// is running. Defaults to Newest.
+	// +kubebuilder:validation:Enum=Newest;Oldest;Random
+	// +optional
+	RemovalOrder ScaleOrder `json:"removalOrder,omitempty"`
 }
 
 // ClusterStatus defines the observed state of Cluster.
@@ -120,6 +126,20 @@ type ClusterStatus struct {
 
 	// LastScheduleTime provide information about  the last time a Job was successfully scheduled.
 	LastScheduleTime metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// RemovedJobs is a list of services that have been removed by decreasing the instances of the cluster.
+	// Removed services are not accounted in the lifecycle of the cluster.
+	// +optional
+	RemovedJobs []string `json:"removedJobs,omitempty"`
+
+	// Restarts is the total number of times that the containers of the services have been restarted.
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
+}
+
+// ExpectedJobs returns the number of services the cluster is expected to run, excluding the removed services.
+func (in *ClusterStatus) ExpectedJobs() int {
+	return len(in.QueuedJobs) - len(in.RemovedJobs)
 }
 
 func (in *Cluster) GetReconcileStatus() Lifecycle {
@@ -51,11 +51,14 @@ func (in *Scenario) Table() (header []string, data [][]string) {
 		"Duration",
 	}
 
-	var scheduled string
+	scheduled := fmt.Sprintf("%d/%d", len(in.Status.ScheduledJobs), in.Spec.NumExpectedJobs(&in.Status))
+
+	if len(in.Status.SkippedJobs) > 0 {
+		scheduled += fmt.Sprintf(" (Skipped: %d)", len(in.Status.SkippedJobs))
+	}
+
 	if in.Spec.Suspend != nil && *in.Spec.Suspend {
-		scheduled = fmt.Sprintf("%d/%d (Suspended)", len(in.Status.ScheduledJobs), len(in.Spec.Actions))
-	} else {
-		scheduled = fmt.Sprintf("%d/%d", len(in.Status.ScheduledJobs), len(in.Spec.Actions))
+		scheduled += " (Suspended)"
 	}
 
 	// age is the elapsed time since the test was created
@@ -83,6 +86,11 @@ func (in *Scenario) Table() (header []string, data [][]string) {
 		duration = cond.LastTransitionTime.Sub(in.GetCreationTimestamp().Time)
 	}
 
+	if meta.IsStatusConditionTrue(in.Status.Conditions, ConditionDeadlineExceeded.String()) {
+		cond := meta.FindStatusCondition(in.Status.Conditions, ConditionDeadlineExceeded.String())
+		duration = cond.LastTransitionTime.Sub(in.GetCreationTimestamp().Time)
+	}
+
 	data = append(data, []string{
 		in.GetNamespace(),
 		in.GetName(),
@@ -110,12 +118,22 @@ const (
 	ActionDelete ActionType = "Delete"
 	// ActionCall starts a remote process execution, from the controller to the targeted services.
 	ActionCall ActionType = "Call"
+	// ActionScenario includes the actions of a scenario fragment, defined by a template.
+	ActionScenario ActionType = "Scenario"
+	// ActionWait blocks the dependent actions until a duration has elapsed, or a condition is met.
+	ActionWait ActionType = "Wait"
+	// ActionScale grows, or shrinks, a running cluster.
+	ActionScale ActionType = "Scale"
+	// ActionApply creates arbitrary Kubernetes objects, defined by a template.
+	ActionApply ActionType = "Apply"
+	// ActionHelm installs, upgrades, or uninstalls a Helm release.
+	ActionHelm ActionType = "Helm"
 )
 
 // Action is a step in a workflow that defines a particular part of a testing process.
 type Action struct {
 	// ActionType refers to a category of actions that can be associated with a specific controller.
-	// +kubebuilder:validation:Enum=Service;Cluster;Chaos;Cascade;Delete;Call
+	// +kubebuilder:validation:Enum=Service;Cluster;Chaos;Cascade;Delete;Call;Scenario;Wait;Scale;Apply;Helm
 	ActionType ActionType `json:"action"`
 
 	// Name is a unique identifier of the action
@@ -130,6 +148,26 @@ type Action struct {
 	// +optional
 	Assert *ConditionalExpr `json:"assert,omitempty"`
 
+	// ActiveDeadline is the maximum duration the action may be active, measured since the creation of its job.
+	// If the deadline is exceeded, the Scenario will abort immediately.
+	// +optional
+	ActiveDeadline *metav1.Duration `json:"activeDeadline,omitempty"`
+
+	// When guards the execution of the action. The condition is evaluated once the dependencies are met.
+	// If the condition is true, the action is scheduled. Otherwise, the action is skipped.
+	// A skipped action satisfies the Success and Completed dependencies of other actions, so that the branches
+	// can be joined. Actions that depend on a skipped action to be Running or Failed are skipped as well.
+	// Metrics conditions are armed at the beginning of the scenario, and are regarded as true
+	// for as long as the alert is not fired. Since the alerts are set on the scenario, a metrics condition
+	// cannot be combined with other metrics expressions of the scenario (e.g, assertions).
+	// +optional
+	When *ConditionalExpr `json:"when,omitempty"`
+
+	// Else is a list of actions that run only if this action is skipped (e.g, the When condition is false).
+	// If this action is scheduled, the listed actions are skipped.
+	// +optional
+	Else []string `json:"else,omitempty"`
+
 	*EmbedActions `json:",inline"`
 }
 
@@ -138,13 +176,71 @@ type WaitSpec struct {
 	// +optional
 	Running []string `json:"running,omitempty"`
 
-	// Success waits for the given groups to be succeeded
+	// Success waits for the given groups to be succeeded. Skipped groups are regarded as succeeded.
 	// +optional
 	Success []string `json:"success,omitempty"`
 
-	// After is the time offset since the beginning of this action.
+	// Failed waits for the given groups to be failed. Failures of the given groups are tolerated by the scenario.
+	// +optional
+	Failed []string `json:"failed,omitempty"`
+
+	// Completed waits for the given groups to be completed, either successfully or not.
+	// Failures of the given groups are tolerated by the scenario. Skipped groups are regarded as completed.
+	// +optional
+	Completed []string `json:"completed,omitempty"`
+
+	// After is the time offset since the beginning of the scenario, or since the transition referenced by Since.
 	// +optional
 	After *metav1.Duration `json:"after,omitempty"`
+
+	// Since anchors the After offset to the transition of another action, e.g, 2m after 'servers' became Running.
+	// If the referenced transition has not yet happened, the action keeps waiting. If the transition will never
+	// happen (e.g, the referenced action is skipped, or has completed in another phase), the action is skipped.
+	// +optional
+	Since *TransitionRef `json:"since,omitempty"`
+}
+
+// TransitionRef points to the transition of an action to a given phase.
+type TransitionRef struct {
+	// Action is the name of the referenced action.
+	Action string `json:"action"`
+
+	// Phase is the phase that the referenced action has transitioned to.
+	// +kubebuilder:validation:Enum=Running;Success;Failed
+	Phase Phase `json:"phase"`
+}
+
+// ActionTransitions records the time at which an action has transitioned to a given phase.
+type ActionTransitions struct {
+	// Running is the time the action was first observed as Running.
+	// +optional
+	Running *metav1.Time `json:"running,omitempty"`
+
+	// Success is the time the action was first observed as Success.
+	// +optional
+	Success *metav1.Time `json:"success,omitempty"`
+
+	// Failed is the time the action was first observed as Failed.
+	// +optional
+	Failed *metav1.Time `json:"failed,omitempty"`
+}
+
+// Get returns the time of the transition to the given phase, or nil if the transition has not yet happened.
+func (in *ActionTransitions) Get(phase Phase) *metav1.Time {
+	if in == nil {
+		return nil
+	}
+
+	switch phase {
+	case PhaseRunning:
+		return in.Running
+	case PhaseSuccess:
+		return in.Success
+	case PhaseFailed:
+		return in.Failed
+	default:
+		return nil
+	}
 }
 
 type DeleteSpec struct {
@@ -152,6 +248,19 @@ type DeleteSpec struct {
 	Jobs []string `json:"jobs"`
 }
 
+// BarrierSpec completes once a duration has elapsed, or a condition is met. If both are defined,
+// the condition is evaluated once the duration has elapsed.
+type BarrierSpec struct {
+	// Duration is the time to wait, since the action has started.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Until is the condition that completes the wait. State expressions complete the wait once they are true,
+	// whereas metrics expressions complete the wait once their alert is fired.
+	// +optional
+	Until *ConditionalExpr `json:"until,omitempty"`
+}
+
 type EmbedActions struct {
 	// +optional
 	Service *GenerateObjectFromTemplate `json:"service,omitempty"`
@@ -170,6 +279,21 @@ type EmbedActions struct {
 
 	// +optional
 	Call *CallSpec `json:"call,omitempty"`
+
+	// +optional
+	Scenario *IncludeSpec `json:"scenario,omitempty"`
+
+	// +optional
+	Wait *BarrierSpec `json:"wait,omitempty"`
+
+	// +optional
+	Scale *ScaleSpec `json:"scale,omitempty"`
+
+	// +optional
+	Apply *GenerateObjectFromTemplate `json:"apply,omitempty"`
+
+	// +optional
+	Helm *HelmSpec `json:"helm,omitempty"`
 }
 
 type TestdataVolume struct {
@@ -186,15 +310,58 @@ type ScenarioSpec struct {
 	// TestData defines a volume that will be mounted across the Scenario's Services.
 	TestData *TestdataVolume `json:"testData,omitempty"`
 
+	// Inputs are dynamic fields that populate the actions of the scenario.
+	// +optional
+	Inputs *ScenarioInputs `json:"inputs,omitempty"`
+
 	// Actions are the tasks that will be taken.
 	Actions []Action `json:"actions"`
 
+	// Finally are the tasks that will be taken once the scenario reaches a terminal phase (either Success or Failed),
+	// such as exporting the state of a database, or revoking chaos events. Finally actions run in parallel, before the
+	// cleanup of the scenario, and their outcome does not affect the verdict of the scenario.
+	// +optional
+	Finally []Action `json:"finally,omitempty"`
+
+	// ActiveDeadline is the maximum duration the scenario may be active, measured since its creation.
+	// For repeated scenarios, the deadline applies to every trial, and it is measured since the beginning of the trial.
+	// If the deadline is exceeded, the Scenario will abort immediately.
+	// +optional
+	ActiveDeadline *metav1.Duration `json:"activeDeadline,omitempty"`
+
+	// SuccessWhen declares the scenario successful once the condition is met, even if some jobs are still running.
+	// The remaining jobs are removed. This allows scenarios with long-lived services (e.g, soak tests) to complete
+	// without explicit deletions. State expressions are met once they are true, whereas metrics expressions are met
+	// once their alert is fired.
+	// +optional
+	SuccessWhen *ConditionalExpr `json:"successWhen,omitempty"`
+
+	// FailWhen declares the scenario failed once the condition is met. It is evaluated before SuccessWhen.
+	// State expressions are met once they are true, whereas metrics expressions are met once their alert is fired.
+	// +optional
+	FailWhen *ConditionalExpr `json:"failWhen,omitempty"`
+
+	// PostConditions are evaluated once, when the scenario reaches a terminal phase. If any post-condition
+	// is not met, a successful scenario is declared failed.
+	// +optional
+	PostConditions []PostCondition `json:"postConditions,omitempty"`
+
+	// Repeat executes the actions of the scenario multiple times, one trial after the other, and aggregates
+	// the collected metrics across the trials. The finally actions run once, after the last trial.
+	// +optional
+	Repeat *RepeatSpec `json:"repeat,omitempty"`
+
 	// Suspend flag tells the controller to suspend subsequent executions, it does
 	// not apply to already started executions.  Defaults to false.
 	// +optional
 	Suspend *bool `json:"suspend,omitempty"`
 }
 
+// NumExpectedJobs returns the number of actions that are expected to run, excluding the skipped ones.
+func (in *ScenarioSpec) NumExpectedJobs(status *ScenarioStatus) int {
+	return len(in.Actions) - len(status.SkippedJobs)
+}
+
 // ScenarioStatus defines the observed state of Scenario.
 type ScenarioStatus struct {
 	Lifecycle `json:",inline"`
@@ -203,6 +370,46 @@ type ScenarioStatus struct {
 	// +optional
 	ScheduledJobs []string `json:"scheduledJobs,omitempty"`
 
+	// SkippedJobs is a list of references to the names of actions that will never run, either because their
+	// When condition was false, or because they belong to a branch that was not taken.
+	// Skipped actions satisfy the Success dependencies of subsequent actions.
+	// +optional
+	SkippedJobs []string `json:"skippedJobs,omitempty"`
+
+	// Transitions records the time at which every scheduled action has transitioned to a new phase.
+	// The timestamps are used for anchoring time offsets to other actions.
+	// +optional
+	Transitions map[string]ActionTransitions `json:"transitions,omitempty"`
+
+	// Timeline records the execution of every scheduled action, in the order the actions were scheduled.
+	// +optional
+	Timeline ActionTimeline `json:"timeline,omitempty"`
+
+	// Verdict records the expression that has declared the outcome of the scenario (SuccessWhen or FailWhen), if any.
+	// +optional
+	Verdict *VerdictStatus `json:"verdict,omitempty"`
+
+	// PostConditions reports the outcome of every post-condition, once the scenario has reached a terminal phase.
+	// +optional
+	PostConditions []PostConditionResult `json:"postConditions,omitempty"`
+
+	// Variables are the values captured by the calls of the scenario. Subsequent actions reference them
+	// as {{.variables.<name>}}.
+	// +optional
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// Includes lists the actions that have been instantiated by every included scenario.
+	// +optional
+	Includes map[string][]string `json:"includes,omitempty"`
+
+	// Finally reports the outcome of the finally actions, separately from the verdict of the scenario.
+	// +optional
+	Finally *FinallyStatus `json:"finally,omitempty"`
+
+	// Trials reports the outcome of every trial, and a summary across the trials, for repeated scenarios.
+	// +optional
+	Trials *TrialsStatus `json:"trials,omitempty"`
+
 	// GrafanaEndpoint points to the local Grafana instance
 	GrafanaEndpoint string `json:"grafanaEndpoint,omitempty"`
 
@@ -213,12 +420,48 @@ type ScenarioStatus struct {
 	DataviewerEndpoint string `json:"dataviewerEndpoint,omitempty"`
 }
 
+const (
+	// VerdictSuccessWhen is the rule of outcomes declared by the SuccessWhen expression.
+	VerdictSuccessWhen = "SuccessWhen"
+
+	// VerdictFailWhen is the rule of outcomes declared by the FailWhen expression.
+	VerdictFailWhen = "FailWhen"
+)
+
+// VerdictStatus records the expression that has declared the outcome of the scenario.
+type VerdictStatus struct {
+	// Rule is the field of the expression that has declared the outcome, i.e, SuccessWhen or FailWhen.
+	Rule string `json:"rule"`
+
+	// Expr is the expression that has been met.
+	Expr ConditionalExpr `json:"expr"`
+
+	// Time is when the outcome was declared.
+	Time metav1.Time `json:"time"`
+}
+
+// FinallyStatus defines the observed state of the finally actions.
+type FinallyStatus struct {
+	Lifecycle `json:",inline"`
+
+	// ScheduledJobs is a list of references to the names of executed finally actions.
+	// +optional
+	ScheduledJobs []string `json:"scheduledJobs,omitempty"`
+
+	// SkippedJobs is a list of references to the names of finally actions whose When condition was false.
+	// +optional
+	SkippedJobs []string `json:"skippedJobs,omitempty"`
+}
+
 func (in *ScenarioStatus) Table() (header []string, data [][]string) {
 	header = []string{
 		"Phase",
 		"Reason",
 		"Message",
 		"Conditions",
+		"Finally",
+		"PostConditions",
+		"Trials",
 	}
 
 	// encode message to escape it
@@ -238,11 +481,38 @@ func (in *ScenarioStatus) Table() (header []string, data [][]string) {
 		}
 	}
 
+	// the outcome of the finally actions is reported separately from the verdict
+	finally := "\t----"
+	if in.Finally != nil {
+		finally = in.Finally.Phase.String()
+	}
+
+	postConditions := "\t----"
+	if len(in.PostConditions) > 0 {
+		passed := 0
+
+		for _, result := range in.PostConditions {
+			if result.Passed {
+				passed++
+			}
+		}
+
+		postConditions = fmt.Sprintf("%d/%d passed", passed, len(in.PostConditions))
+	}
+
+	trials := "\t----"
+	if in.Trials != nil {
+		trials = fmt.Sprintf("%d (Completed: %d)", in.Trials.Current, len(in.Trials.Results))
+	}
+
 	data = append(data, []string{
 		in.Phase.String(),
 		in.Reason,
 		string(message),
 		conditions.String(),
+		finally,
+		postConditions,
+		trials,
 	})
 
 	return header, data
@@ -256,6 +526,10 @@ func (in *Scenario) SetReconcileStatus(lifecycle Lifecycle) {
 	in.Status.Lifecycle = lifecycle
 }
 
+func (in *Scenario) GetVariables() map[string]string {
+	return in.Status.Variables
+}
+
 // +kubebuilder:object:root=true
 
 // ScenarioList contains a list of Scenario.
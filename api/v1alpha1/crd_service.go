@@ -91,6 +91,10 @@ type ServiceStatus struct {
 
 	// LastScheduleTime provide information about  the last time a Pod was scheduled.
 	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Restarts is the number of times that the containers of the Pod have been restarted.
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
 }
 
 func (in *Service) GetReconcileStatus() Lifecycle {
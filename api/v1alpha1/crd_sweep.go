@@ -0,0 +1,332 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Sweep is the Schema for the Sweeps API. A Sweep runs a scenario over the Cartesian product of parameter axes.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Sweep struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SweepSpec   `json:"spec,omitempty"`
+	Status SweepStatus `json:"status,omitempty"`
+}
+
+// SweepAxis is a parameter whose values are swept.
+type SweepAxis struct {
+	// Name is the scenario parameter that is set by the axis, i.e., {{.inputs.parameters.<name>}}.
+	Name string `json:"name"`
+
+	// Values is the list of values the parameter takes.
+	// +kubebuilder:validation:MinItems=1
+	Values []apiextensionsv1.JSON `json:"values"`
+}
+
+// SweepSpec defines the desired state of Sweep.
+type SweepSpec struct {
+	// Scenario is the spec of the scenarios to be created (i.e, a ScenarioSpec). The values of every point in the
+	// sweep are set as parameters of the scenario (spec.inputs.parameters).
+	// The spec is kept as raw, so that the references to the parameters are resolved on the created scenarios.
+	Scenario apiextensionsv1.JSON `json:"scenario"`
+
+	// Axes are the parameters to be swept. A scenario is created for every combination of their values.
+	// +kubebuilder:validation:MinItems=1
+	Axes []SweepAxis `json:"axes"`
+
+	// Repetitions is the number of times every combination is executed. Defaults to 1.
+	// +optional
+	Repetitions int `json:"repetitions,omitempty"`
+
+	// Parallelism is the maximum number of scenarios that may run concurrently. Defaults to 1.
+	// +optional
+	Parallelism int `json:"parallelism,omitempty"`
+
+	// Suspend forces the Controller to stop scheduling any new scenarios until it is resumed. Defaults to false.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// Tolerate forces the Controller to continue in spite of failed scenarios.
+	// +optional
+	Tolerate *TolerateSpec `json:"tolerate,omitempty"`
+}
+
+// SweepRun is a scenario created by the sweep.
+type SweepRun struct {
+	// Name is the name of the scenario, and of the namespace it runs in. It is derived from the hash of the
+	// parameters and the repetition of the run.
+	Name string `json:"name"`
+
+	// Parameters are the values of the axes for this run.
+	Parameters Parameters `json:"parameters,omitempty"`
+
+	// Repetition is the index of this run among the repetitions of the same combination.
+	// +optional
+	Repetition int `json:"repetition,omitempty"`
+
+	// Phase is the last observed phase of the scenario.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Reason is the last observed reason of the scenario's phase.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// SweepStatus defines the observed state of Sweep.
+type SweepStatus struct {
+	Lifecycle `json:",inline"`
+
+	// Runs is the list of scenarios that the controller has to create, along with their outcome.
+	// +optional
+	Runs []SweepRun `json:"runs,omitempty"`
+
+	// ScheduledRuns is the number of Runs that have been created.
+	// +optional
+	ScheduledRuns int `json:"scheduledRuns,omitempty"`
+}
+
+func (in *Sweep) GetReconcileStatus() Lifecycle {
+	return in.Status.Lifecycle
+}
+
+func (in *Sweep) SetReconcileStatus(lifecycle Lifecycle) {
+	in.Status.Lifecycle = lifecycle
+}
+
+// GenerateRuns returns a run for every combination of the axes' values and every repetition.
+// The last axis changes faster.
+func (in *Sweep) GenerateRuns() []SweepRun {
+	repetitions := in.Spec.Repetitions
+	if repetitions < 1 {
+		repetitions = 1
+	}
+
+	// build the Cartesian product of the axes
+	points := []Parameters{{}}
+
+	for _, axis := range in.Spec.Axes {
+		expanded := make([]Parameters, 0, len(points)*len(axis.Values))
+
+		for _, point := range points {
+			for i := range axis.Values {
+				next := point.DeepCopy()
+				next[axis.Name] = axis.Values[i].DeepCopy()
+
+				expanded = append(expanded, next)
+			}
+		}
+
+		points = expanded
+	}
+
+	runs := make([]SweepRun, 0, len(points)*repetitions)
+
+	for _, point := range points {
+		for repetition := 0; repetition < repetitions; repetition++ {
+			runs = append(runs, SweepRun{
+				Name:       runName(in.GetName(), point, repetition),
+				Parameters: point.DeepCopy(),
+				Repetition: repetition,
+			})
+		}
+	}
+
+	return runs
+}
+
+// runHashLength is the number of hexadecimal digits of the hash in the name of a run.
+const runHashLength = 10
+
+// MaxSweepNameLength is the maximum length of the name of a sweep, so that the names of the runs are valid
+// namespace names (i.e, at most 63 characters).
+const MaxSweepNameLength = 63 - runHashLength - 1
+
+// runName derives the name of a run from the hash of its parameter point and repetition. Unlike a sequence
+// number, the name identifies the run, and does not match the runs of other sweeps, or of other specs.
+func runName(sweepName string, point Parameters, repetition int) string {
+	// the keys of the map are sorted, and the raw values are compacted. Thus, the encoding is canonical.
+	encoded, err := json.Marshal(point)
+	if err != nil {
+		panic(errors.Wrapf(err, "cannot encode parameters"))
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", encoded, repetition)))
+
+	return fmt.Sprintf("%s-%s", sweepName, hex.EncodeToString(hash[:])[:runHashLength])
+}
+
+// GenerateScenario returns the scenario of the given run. The parameters of the run override the defaults
+// of the scenario's parameters.
+func (in *Sweep) GenerateScenario(run SweepRun) (*Scenario, error) {
+	spec, err := GenerateScenarioSpec(in.Spec.Scenario.Raw, run.Parameters)
+	if err != nil {
+		return nil, errors.Wrapf(err, "run '%s'", run.Name)
+	}
+
+	var scenario Scenario
+
+	scenario.Spec = *spec
+	scenario.SetName(run.Name)
+	scenario.SetNamespace(run.Name)
+
+	return &scenario, nil
+}
+
+// Table returns a tabular form of the structure for pretty printing.
+func (in *Sweep) Table() (header []string, data [][]string) {
+	header = []string{
+		"Test",
+		"Sweep",
+		"Age",
+		"Runs",
+		"Passed",
+		"Failed",
+		"Phase",
+	}
+
+	var passed, failed int
+
+	for _, run := range in.Status.Runs {
+		switch run.Phase {
+		case PhaseSuccess:
+			passed++
+		case PhaseFailed:
+			failed++
+		}
+	}
+
+	scheduled := fmt.Sprintf("%d/%d", in.Status.ScheduledRuns, len(in.Status.Runs))
+
+	if in.Spec.Suspend != nil && *in.Spec.Suspend {
+		scheduled += " (Suspended)"
+	}
+
+	// age is the elapsed time since the sweep was created
+	age := time.Since(in.GetCreationTimestamp().Time)
+
+	data = append(data, []string{
+		in.GetNamespace(),
+		in.GetName(),
+		age.Round(time.Second).String(),
+		scheduled,
+		fmt.Sprint(passed),
+		fmt.Sprint(failed),
+		in.Status.Phase.String(),
+	})
+
+	return header, data
+}
+
+// Table returns the pass/fail table of the runs.
+func (in *SweepStatus) Table() (header []string, data [][]string) {
+	header = []string{
+		"Run",
+		"Parameters",
+		"Repetition",
+		"Phase",
+		"Reason",
+	}
+
+	for _, run := range in.Runs {
+		// encode parameters in a deterministic order
+		keys := make([]string, 0, len(run.Parameters))
+		for key := range run.Parameters {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		params := make([]string, 0, len(keys))
+		for _, key := range keys {
+			params = append(params, fmt.Sprintf("%s=%s", key, run.Parameters[key].Raw))
+		}
+
+		phase := run.Phase.String()
+		if phase == "" {
+			phase = "\t----"
+		}
+
+		data = append(data, []string{
+			run.Name,
+			strings.Join(params, ","),
+			fmt.Sprint(run.Repetition),
+			phase,
+			run.Reason,
+		})
+	}
+
+	return header, data
+}
+
+// +kubebuilder:object:root=true
+
+// SweepList contains a list of Sweep.
+type SweepList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Sweep `json:"items"`
+}
+
+// Table returns a tabular form of the structure for pretty printing.
+func (in *SweepList) Table() (header []string, data [][]string) {
+	header = []string{
+		"Test",
+		"Sweep",
+		"Age",
+		"Runs",
+		"Passed",
+		"Failed",
+		"Phase",
+	}
+
+	// arrange in descending order (latest created goes first)
+	sort.SliceStable(in.Items, func(i, j int) bool {
+		tsI := in.Items[i].GetCreationTimestamp()
+		tsJ := in.Items[j].GetCreationTimestamp()
+
+		return tsI.After(tsJ.Time)
+	})
+
+	for _, sweep := range in.Items {
+		_, sweepData := sweep.Table()
+
+		data = append(data, sweepData...)
+	}
+
+	return header, data
+}
+
+func init() {
+	SchemeBuilder.Register(&Sweep{}, &SweepList{})
+}
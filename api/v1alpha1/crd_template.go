@@ -93,6 +93,16 @@ type EmbedSpecs struct {
 
 	// +optional
 	Chaos *ChaosSpec `json:"chaos,omitempty"`
+
+	// Scenario is a fragment of a scenario (i.e, a ScenarioSpec) that can be included by the actions of other
+	// scenarios. The spec is kept as raw, so that the references to the parameters are resolved upon inclusion.
+	// +optional
+	Scenario *apiextensionsv1.JSON `json:"scenario,omitempty"`
+
+	// Manifests are Kubernetes objects that are created by the Apply action. Supported kinds are ConfigMap, Secret,
+	// Service, PersistentVolumeClaim, Pod, Job, Deployment, StatefulSet, Ingress, and NetworkPolicy.
+	// +optional
+	Manifests *ManifestSpec `json:"manifests,omitempty"`
 }
 
 // TemplateStatus defines the observed state of Template.
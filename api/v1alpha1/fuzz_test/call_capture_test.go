@@ -0,0 +1,134 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestCaptureSpec_Extract(t *testing.T) {
+	tests := []struct {
+		name    string
+		capture v1alpha1.CaptureSpec
+		stdout  string
+		stderr  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "regex-group",
+			capture: v1alpha1.CaptureSpec{Name: "leader", Regex: `leader: (\S+)`},
+			stdout:  "term: 4\nleader: node-2\n",
+			want:    "node-2",
+		},
+		{
+			name:    "regex-whole-match",
+			capture: v1alpha1.CaptureSpec{Name: "node", Regex: `node-\d+`},
+			stdout:  "leader is node-3",
+			want:    "node-3",
+		},
+		{
+			name:    "regex-stderr",
+			capture: v1alpha1.CaptureSpec{Name: "code", Stream: v1alpha1.CaptureStderr, Regex: `code=(\d+)`},
+			stdout:  "code=1",
+			stderr:  "code=2",
+			want:    "2",
+		},
+		{
+			name:    "regex-mismatch",
+			capture: v1alpha1.CaptureSpec{Name: "leader", Regex: `leader: (\S+)`},
+			stdout:  "no leader",
+			wantErr: true,
+		},
+		{
+			name:    "jsonpath",
+			capture: v1alpha1.CaptureSpec{Name: "leader", JSONPath: "{.raft.leader.id}"},
+			stdout:  `{"raft": {"leader": {"id": "node-1", "term": 7}}}`,
+			want:    "node-1",
+		},
+		{
+			name:    "jsonpath-number",
+			capture: v1alpha1.CaptureSpec{Name: "term", JSONPath: "{.raft.leader.term}"},
+			stdout:  `{"raft": {"leader": {"id": "node-1", "term": 7}}}`,
+			want:    "7",
+		},
+		{
+			name:    "jsonpath-missing-field",
+			capture: v1alpha1.CaptureSpec{Name: "leader", JSONPath: "{.raft.follower}"},
+			stdout:  `{"raft": {}}`,
+			wantErr: true,
+		},
+		{
+			name:    "jsonpath-not-json",
+			capture: v1alpha1.CaptureSpec{Name: "leader", JSONPath: "{.raft}"},
+			stdout:  "leader: node-2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.capture.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			got, err := tt.capture.Extract(tt.stdout, tt.stderr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Extract() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Extract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandVariables(t *testing.T) {
+	variables := map[string]string{"leader": "node-2", "term": "7"}
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", in: "{{.variables.leader}}", want: "node-2"},
+		{name: "embedded", in: "kill {{ .variables.leader }} at {{.variables.term}}", want: "kill node-2 at 7"},
+		{name: "no-variables", in: "{{.inputs.parameters.leader}}", want: "{{.inputs.parameters.leader}}"},
+		{name: "missing", in: "{{.variables.follower}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v1alpha1.ExpandVariables(tt.in, variables)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExpandVariables() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ExpandVariables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestScaleSpec_Instances(t *testing.T) {
+	tests := []struct {
+		name    string
+		by      intstr.IntOrString
+		current int
+		want    int
+		wantErr bool
+	}{
+		{name: "grow", by: intstr.FromInt(2), current: 3, want: 5, wantErr: false},
+		{name: "shrink", by: intstr.FromInt(-2), current: 3, want: 1, wantErr: false},
+		{name: "grow-percent", by: intstr.FromString("50%"), current: 4, want: 6, wantErr: false},
+		{name: "shrink-percent-round-up", by: intstr.FromString("-25%"), current: 5, want: 3, wantErr: false},
+		{name: "small-percent", by: intstr.FromString("10%"), current: 2, want: 3, wantErr: false},
+		{name: "no-effect", by: intstr.FromInt(0), current: 3, wantErr: true},
+		{name: "remove-all", by: intstr.FromString("-100%"), current: 3, wantErr: true},
+		{name: "below-one", by: intstr.FromInt(-5), current: 3, wantErr: true},
+		{name: "no-percent-sign", by: intstr.FromString("50"), current: 3, wantErr: true},
+		{name: "invalid-percent", by: intstr.FromString("half%"), current: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scale := v1alpha1.ScaleSpec{Cluster: "clients", By: tt.by}
+
+			got, err := scale.Instances(tt.current)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Instances() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if err == nil && got != tt.want {
+				t.Errorf("Instances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
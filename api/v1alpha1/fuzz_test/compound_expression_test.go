@@ -0,0 +1,111 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestValidateExpr_Compound(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    *v1alpha1.ConditionalExpr
+		wantErr bool
+	}{
+		{
+			name: "allOf state and metrics",
+			expr: &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{
+				{State: `{{.NumRunningJobs}} == 3`},
+				{Metrics: "avg() of query(summary/152/tx-avg, 1m, now) is below(5000)"},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "nested compositions",
+			expr: &v1alpha1.ConditionalExpr{AnyOf: []v1alpha1.ConditionalExpr{
+				{Not: &v1alpha1.ConditionalExpr{State: `isFailed("servers")`, Syntax: v1alpha1.ExprSyntaxCEL}},
+				{AllOf: []v1alpha1.ConditionalExpr{{PromQL: `up == 1`}, {State: `{{.IsSuccessful "clients"}} == true`}}},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "invalid sub-expression",
+			expr: &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{
+				{State: `isSucessful("clients")`, Syntax: v1alpha1.ExprSyntaxCEL},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "empty sub-expression",
+			expr: &v1alpha1.ConditionalExpr{AnyOf: []v1alpha1.ConditionalExpr{
+				{State: `{{.IsSuccessful "clients"}} == true`},
+				{},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "composition combined with state expression",
+			expr: &v1alpha1.ConditionalExpr{
+				State: `{{.IsSuccessful "clients"}} == true`,
+				Not:   &v1alpha1.ConditionalExpr{State: `{{.IsFailed "servers"}} == true`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allOf combined with anyOf",
+			expr: &v1alpha1.ConditionalExpr{
+				AllOf: []v1alpha1.ConditionalExpr{{State: `{{.IsSuccessful "clients"}} == true`}},
+				AnyOf: []v1alpha1.ConditionalExpr{{State: `{{.IsFailed "servers"}} == true`}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "multiple metrics sub-expressions",
+			expr: &v1alpha1.ConditionalExpr{AnyOf: []v1alpha1.ConditionalExpr{
+				{Metrics: "avg() of query(summary/152/tx-avg, 1m, now) is below(5000)"},
+				{Not: &v1alpha1.ConditionalExpr{Metrics: "avg() of query(summary/152/tx-avg, 1m, now) is above(9000)"}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v1alpha1.ValidateExpr(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExpr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditionalExpr_Leaves(t *testing.T) {
+	expr := &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{
+		{State: "a"},
+		{AnyOf: []v1alpha1.ConditionalExpr{{PromQL: "b"}, {Not: &v1alpha1.ConditionalExpr{Metrics: "c"}}}},
+	}}
+
+	leaves := expr.Leaves()
+	if len(leaves) != 3 || leaves[0].State != "a" || leaves[1].PromQL != "b" || leaves[2].Metrics != "c" {
+		t.Errorf("Leaves() = %v, want [a b c]", leaves)
+	}
+
+	if leaves := (*v1alpha1.ConditionalExpr)(nil).Leaves(); len(leaves) != 0 {
+		t.Errorf("Leaves() of nil expression = %v, want none", leaves)
+	}
+}
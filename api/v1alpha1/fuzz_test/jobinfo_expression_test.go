@@ -0,0 +1,370 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var jobInfoStart = time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func setJobInfo(state *lifecycle.Classifier) {
+	// transitioned is the time since the transition to the phase, as recorded by the lifecycle. Zero means unrecorded.
+	service := func(name string, phase v1alpha1.Phase, reason string, age, transitioned time.Duration, restarts int32) {
+		var job v1alpha1.Service
+
+		job.SetName(name)
+		job.SetCreationTimestamp(metav1.NewTime(jobInfoStart.Add(-age)))
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+		job.Status.Lifecycle.Phase = phase
+		job.Status.Lifecycle.Reason = reason
+		job.Status.Restarts = restarts
+
+		if transitioned > 0 {
+			job.Status.Lifecycle.Conditions = []metav1.Condition{{
+				Type:               v1alpha1.ConditionAllJobsAreScheduled.String(),
+				Status:             metav1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(jobInfoStart.Add(-transitioned)),
+			}}
+		}
+
+		state.Classify(job.GetName(), &job)
+	}
+
+	service("server", v1alpha1.PhaseRunning, "MockRunning", 5*time.Minute, 4*time.Minute, 3)
+	service("client", v1alpha1.PhasePending, "MockPending", time.Minute, 0, 0)
+	service("done", v1alpha1.PhaseSuccess, "Completed", 10*time.Minute, 0, 0)
+
+	{
+		var cluster v1alpha1.Cluster
+
+		cluster.SetName("clients")
+		v1alpha1.SetComponentLabel(&cluster.ObjectMeta, v1alpha1.ComponentSUT)
+
+		cluster.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+		cluster.Status.Restarts = 2
+
+		state.Classify(cluster.GetName(), &cluster)
+	}
+
+	{
+		var pod corev1.Pod
+
+		pod.SetName("db")
+		pod.SetCreationTimestamp(metav1.NewTime(jobInfoStart.Add(-3 * time.Minute)))
+
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{Name: "main", RestartCount: 1},
+			{Name: "sidecar", RestartCount: 1},
+		}
+
+		state.ClassifyExternal(pod.GetName(), &pod, func(client.Object) v1alpha1.Lifecycle {
+			return v1alpha1.Lifecycle{Phase: v1alpha1.PhaseRunning, Reason: "PodRunning"}
+		})
+	}
+}
+
+func TestJobInfoState(t *testing.T) {
+	state := lifecycle.NewClassifier(clocktesting.NewFakeClock(jobInfoStart))
+	state.Reset()
+
+	setJobInfo(state)
+
+	tests := []struct {
+		name     string
+		expr     v1alpha1.ExprState
+		wantErr  bool
+		wantPass bool
+	}{
+		{
+			name:     "phase",
+			expr:     `{{.Phase "server"}} == Running && {{.Phase "db"}} == Running`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "reason",
+			expr:     `{{.Reason "done"}} == Completed`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "time in phase since transition",
+			expr:     `{{(.TimeInPhase "server").Seconds}} >= 240 && {{(.TimeInPhase "server").Seconds}} < 300`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "time in phase is not enough",
+			expr:     `{{(.TimeInPhase "client").Minutes}} >= 2`,
+			wantErr:  false,
+			wantPass: false,
+		},
+		{
+			name:     "restarts of service",
+			expr:     `{{.NumRestarts "server"}} > 2`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "restarts of pod",
+			expr:     `{{.NumRestarts "db"}} == 2`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "restarts of cluster",
+			expr:     `{{.NumRestarts "clients"}} == 2`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "max restarts",
+			expr:     `{{.MaxRestarts}} == 3`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "unknown job",
+			expr:     `{{.NumRestarts "unknown"}} == 0 && {{(.TimeInPhase "unknown").Seconds}} == 0`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "missing argument",
+			expr:     `{{.TimeInPhase}} > 0`,
+			wantErr:  true,
+			wantPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pass, err := tt.expr.GoValuate(state)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GoValuate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if pass != tt.wantPass {
+				t.Errorf("GoValuate() pass = %v, want %v", pass, tt.wantPass)
+			}
+		})
+	}
+}
+
+func TestJobInfoCELState(t *testing.T) {
+	state := lifecycle.NewClassifier(clocktesting.NewFakeClock(jobInfoStart))
+	state.Reset()
+
+	setJobInfo(state)
+
+	tests := []struct {
+		name     string
+		expr     v1alpha1.ExprState
+		wantErr  bool
+		wantPass bool
+	}{
+		{
+			name:     "phase and reason",
+			expr:     `phase("server") == "Running" && reason("done") == "Completed"`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "time in phase",
+			expr:     `timeInPhase("server") >= duration("4m") && timeInPhase("client") < duration("2m")`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "restarts",
+			expr:     `numRestarts("db") == 2 && maxRestarts() == 3`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "any job restarted more than twice",
+			expr:     `listRunningJobs().exists(job, numRestarts(job) > 2)`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "non-boolean expression",
+			expr:     `numRestarts("server")`,
+			wantErr:  true,
+			wantPass: false,
+		},
+		{
+			name:     "type mismatch",
+			expr:     `timeInPhase("server") > 300`,
+			wantErr:  true,
+			wantPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pass, err := tt.expr.CELValuate(state, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CELValuate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if pass != tt.wantPass {
+				t.Errorf("CELValuate() pass = %v, want %v", pass, tt.wantPass)
+			}
+		})
+	}
+}
+
+func TestJobInfoTransitions(t *testing.T) {
+	state := lifecycle.NewClassifier(clocktesting.NewFakeClock(jobInfoStart))
+	state.Reset()
+
+	setJobInfo(state)
+
+	runningSince := metav1.NewTime(jobInfoStart.Add(-2 * time.Minute))
+	successSince := metav1.NewTime(jobInfoStart.Add(-time.Minute))
+
+	state.SetTransitions(map[string]v1alpha1.ActionTransitions{
+		"server": {Running: &runningSince},
+		"done":   {Success: &successSince},
+	})
+
+	tests := []struct {
+		name string
+		job  string
+		want time.Duration
+	}{
+		{name: "lifecycle transition precedes recorded transition", job: "server", want: 4 * time.Minute},
+		{name: "recorded transition", job: "done", want: time.Minute},
+		{name: "pending since creation", job: "client", want: time.Minute},
+		{name: "unrecorded transition is not measured since creation", job: "db", want: 0},
+		{name: "unknown job", job: "unknown", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := state.TimeInPhase(tt.job); got != tt.want {
+				t.Errorf("TimeInPhase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// the transitions are cleared on reset.
+	state.Reset()
+	setJobInfo(state)
+
+	if got := state.TimeInPhase("done"); got != 0 {
+		t.Errorf("TimeInPhase() after reset = %v, want %v", got, 0)
+	}
+}
+
+func TestExprState_IsPolled(t *testing.T) {
+	tests := []struct {
+		name string
+		expr v1alpha1.ExprState
+		want bool
+	}{
+		{name: "phase transitions", expr: `{{.IsRunning "server"}} == true`, want: false},
+		{name: "phase of job", expr: `phase("server") == "Running"`, want: false},
+		{name: "time in phase", expr: `{{(.TimeInPhase "server").Seconds}} >= 120`, want: true},
+		{name: "time in phase (cel)", expr: `timeInPhase("server") >= duration("2m")`, want: true},
+		{name: "restarts", expr: `maxRestarts() > 2`, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.expr.IsPolled(); got != tt.want {
+				t.Errorf("IsPolled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifier_TotalRestarts(t *testing.T) {
+	state := lifecycle.NewClassifier(clocktesting.NewFakeClock(jobInfoStart))
+	state.Reset()
+
+	setJobInfo(state)
+
+	// server (3) + clients (2) + db (2).
+	if got := state.TotalRestarts(); got != 7 {
+		t.Errorf("TotalRestarts() = %d, want %d", got, 7)
+	}
+}
+
+func TestValidateRestarts(t *testing.T) {
+	newScenario := func(assert *v1alpha1.ConditionalExpr) *v1alpha1.Scenario {
+		var scenario v1alpha1.Scenario
+
+		scenario.Spec.Actions = []v1alpha1.Action{
+			{ActionType: v1alpha1.ActionService, Name: "server"},
+			{ActionType: v1alpha1.ActionCluster, Name: "clients"},
+			{ActionType: v1alpha1.ActionCascade, Name: "killer"},
+			{ActionType: v1alpha1.ActionWait, Name: "warmup", Assert: assert},
+		}
+
+		return &scenario
+	}
+
+	tests := []struct {
+		name    string
+		assert  *v1alpha1.ConditionalExpr
+		wantErr bool
+	}{
+		{name: "service", assert: &v1alpha1.ConditionalExpr{State: `{{.NumRestarts "server"}} <= 2`}},
+		{name: "cluster", assert: &v1alpha1.ConditionalExpr{Syntax: v1alpha1.ExprSyntaxCEL, State: `numRestarts("clients") <= 2`}},
+		{name: "unknown job", assert: &v1alpha1.ConditionalExpr{State: `{{.NumRestarts "included-server"}} <= 2`}},
+		{name: "max restarts", assert: &v1alpha1.ConditionalExpr{Syntax: v1alpha1.ExprSyntaxCEL, State: `maxRestarts() <= 2`}},
+		{
+			name:    "cascade",
+			assert:  &v1alpha1.ConditionalExpr{Syntax: v1alpha1.ExprSyntaxCEL, State: `numRestarts("killer") == 0`},
+			wantErr: true,
+		},
+		{
+			name: "cascade within composition",
+			assert: &v1alpha1.ConditionalExpr{Not: &v1alpha1.ConditionalExpr{
+				State: `{{.NumRestarts "killer"}} > 0`,
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario := newScenario(tt.assert)
+
+			references := make(map[string]*v1alpha1.Action)
+			for i, action := range scenario.Spec.Actions {
+				references[action.Name] = &scenario.Spec.Actions[i]
+			}
+
+			if err := v1alpha1.ValidateRestarts(scenario, references); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRestarts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestExprPromQL_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    v1alpha1.ExprPromQL
+		want    *v1alpha1.PromQLRule
+		wantErr bool
+	}{
+		{
+			name: "simple comparison",
+			expr: `sum(rate(http_requests_total[1m])) > 100`,
+			want: &v1alpha1.PromQLRule{Query: `sum(rate(http_requests_total[1m]))`, Operator: ">", Threshold: 100},
+		},
+		{
+			name: "comparison with duration",
+			expr: `histogram_quantile(0.99, sum(rate(latency_bucket[1m])) by (le)) < 0.02 for 1m`,
+			want: &v1alpha1.PromQLRule{
+				Query:     `histogram_quantile(0.99, sum(rate(latency_bucket[1m])) by (le))`,
+				Operator:  "<",
+				Threshold: 0.02,
+				For:       time.Minute,
+			},
+		},
+		{
+			name: "comparison within the query",
+			expr: `count(up{job="server"} == 1) >= 3`,
+			want: &v1alpha1.PromQLRule{Query: `count(up{job="server"} == 1)`, Operator: ">=", Threshold: 3},
+		},
+		{
+			name: "negative threshold without spaces",
+			expr: `delta(temperature[5m])<-1.5e1`,
+			want: &v1alpha1.PromQLRule{Query: `delta(temperature[5m])`, Operator: "<", Threshold: -15},
+		},
+		{
+			name:    "missing threshold",
+			expr:    `sum(rate(http_requests_total[1m]))`,
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric threshold",
+			expr:    `up == on`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			expr:    `up == 1 for ever`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.Parse()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromQLRule_Compare(t *testing.T) {
+	tests := []struct {
+		operator string
+		value    float64
+		want     bool
+	}{
+		{operator: "<", value: 1, want: true},
+		{operator: "<", value: 2, want: false},
+		{operator: "<=", value: 2, want: true},
+		{operator: ">", value: 2, want: false},
+		{operator: ">=", value: 2, want: true},
+		{operator: "==", value: 2, want: true},
+		{operator: "!=", value: 2, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator, func(t *testing.T) {
+			rule := v1alpha1.PromQLRule{Query: "up", Operator: tt.operator, Threshold: 2}
+
+			if got := rule.Compare(tt.value); got != tt.want {
+				t.Errorf("Compare(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateExpr_PromQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    *v1alpha1.ConditionalExpr
+		wantErr bool
+	}{
+		{
+			name:    "promQL expression",
+			expr:    &v1alpha1.ConditionalExpr{PromQL: `up{job="server"} == 1 for 30s`},
+			wantErr: false,
+		},
+		{
+			name:    "erroneous promQL expression",
+			expr:    &v1alpha1.ConditionalExpr{PromQL: `up{job="server"}`},
+			wantErr: true,
+		},
+		{
+			name: "promQL combined with state expression",
+			expr: &v1alpha1.ConditionalExpr{
+				PromQL: `up == 1`,
+				State:  `{{.IsSuccessful "clients"}} == true`,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v1alpha1.ValidateExpr(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExpr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
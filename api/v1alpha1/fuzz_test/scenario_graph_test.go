@@ -0,0 +1,214 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestBuildDependencyGraph(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions string
+		wantErr string
+	}{
+		{
+			name: "forward-dependency",
+			actions: `[
+				{"action": "Service", "name": "client", "depends": {"running": ["server"]}, "service": {"templateRef": "client"}},
+				{"action": "Service", "name": "server", "service": {"templateRef": "server"}}
+			]`,
+		},
+		{
+			name: "undefined-dependency",
+			actions: `[
+				{"action": "Service", "name": "client", "depends": {"success": ["server"]}, "service": {"templateRef": "client"}}
+			]`,
+			wantErr: "action [client] has a success dependency on undefined action [server]",
+		},
+		{
+			name: "undefined-anchor",
+			actions: `[
+				{"action": "Service", "name": "client", "depends": {"after": "1m", "since": {"action": "server", "phase": "Running"}},
+					"service": {"templateRef": "client"}}
+			]`,
+			wantErr: "action [client] has a time anchor on undefined action [server]",
+		},
+		{
+			name: "self-dependency",
+			actions: `[
+				{"action": "Service", "name": "client", "depends": {"completed": ["client"]}, "service": {"templateRef": "client"}}
+			]`,
+			wantErr: "action [client] has a completed dependency on itself",
+		},
+		{
+			name: "running-call",
+			actions: `[
+				{"action": "Call", "name": "probe", "call": {"callable": "ping", "services": ["server"]}},
+				{"action": "Service", "name": "client", "depends": {"running": ["probe"]}, "service": {"templateRef": "client"}}
+			]`,
+			wantErr: "action [client] has a running dependency on [probe], but Call actions are short-lived",
+		},
+		{
+			name: "running-delete",
+			actions: `[
+				{"action": "Service", "name": "server", "service": {"templateRef": "server"}},
+				{"action": "Delete", "name": "teardown", "delete": {"jobs": ["server"]}},
+				{"action": "Service", "name": "client", "depends": {"running": ["teardown"]}, "service": {"templateRef": "client"}}
+			]`,
+			wantErr: "action [client] has a running dependency on [teardown], but Delete actions are short-lived",
+		},
+		{
+			name: "cycle",
+			actions: `[
+				{"action": "Service", "name": "a", "depends": {"success": ["c"]}, "service": {"templateRef": "a"}},
+				{"action": "Service", "name": "b", "depends": {"running": ["a"]}, "service": {"templateRef": "b"}},
+				{"action": "Service", "name": "c", "depends": {"completed": ["b"]}, "service": {"templateRef": "c"}}
+			]`,
+			wantErr: "dependency cycle [a]->[c]->[b]->[a]",
+		},
+		{
+			name: "cycle-through-anchor",
+			actions: `[
+				{"action": "Service", "name": "a", "depends": {"after": "1m", "since": {"action": "b", "phase": "Running"}},
+					"service": {"templateRef": "a"}},
+				{"action": "Service", "name": "b", "depends": {"running": ["a"]}, "service": {"templateRef": "b"}}
+			]`,
+			wantErr: "dependency cycle [a]->[b]->[a]",
+		},
+		{
+			name: "cycle-through-guard",
+			actions: `[
+				{"action": "Service", "name": "a", "depends": {"success": ["b"]}, "when": {"state": "{{.NumFailedJobs}} == 0"},
+					"else": ["b"], "service": {"templateRef": "a"}},
+				{"action": "Service", "name": "b", "service": {"templateRef": "b"}}
+			]`,
+			wantErr: "dependency cycle [a]->[b]->[a]",
+		},
+		{
+			name: "success-and-failed",
+			actions: `[
+				{"action": "Service", "name": "server", "service": {"templateRef": "server"}},
+				{"action": "Service", "name": "client", "depends": {"success": ["server"], "failed": ["server"]},
+					"service": {"templateRef": "client"}}
+			]`,
+			wantErr: "action [client] is unreachable. It waits for [server] to be both successful and failed",
+		},
+		{
+			name: "running-and-completed",
+			actions: `[
+				{"action": "Service", "name": "server", "service": {"templateRef": "server"}},
+				{"action": "Service", "name": "client", "depends": {"running": ["server"], "completed": ["server"]},
+					"service": {"templateRef": "client"}}
+			]`,
+			wantErr: "action [client] is unreachable. It waits for [server] to be both running and completed",
+		},
+		{
+			name: "anchor-conflicts-with-dependency",
+			actions: `[
+				{"action": "Service", "name": "server", "service": {"templateRef": "server"}},
+				{"action": "Service", "name": "client", "depends": {"failed": ["server"], "after": "1m",
+					"since": {"action": "server", "phase": "Success"}}, "service": {"templateRef": "client"}}
+			]`,
+			wantErr: "action [client] is unreachable. It waits for [server] to be both successful and failed",
+		},
+		{
+			name: "alternative-waits-for-guard",
+			actions: `[
+				{"action": "Service", "name": "a", "when": {"state": "{{.NumFailedJobs}} == 0"}, "else": ["b"],
+					"service": {"templateRef": "a"}},
+				{"action": "Service", "name": "b", "depends": {"running": ["a"]}, "service": {"templateRef": "b"}}
+			]`,
+			wantErr: "action [b] is unreachable. It waits for its guard [a] to be scheduled",
+		},
+		{
+			name: "alternative-joins-guard",
+			actions: `[
+				{"action": "Service", "name": "a", "when": {"state": "{{.NumFailedJobs}} == 0"}, "else": ["b"],
+					"service": {"templateRef": "a"}},
+				{"action": "Service", "name": "b", "depends": {"success": ["a"]}, "service": {"templateRef": "b"}}
+			]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scenario v1alpha1.Scenario
+
+			if err := json.Unmarshal([]byte(`{"actions": `+tt.actions+`}`), &scenario.Spec); err != nil {
+				t.Fatalf("cannot decode actions: %v", err)
+			}
+
+			_, err := v1alpha1.BuildDependencyGraph(&scenario)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("BuildDependencyGraph() unexpected error = %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Errorf("BuildDependencyGraph() expected error '%s'", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("BuildDependencyGraph() error = %v, want '%s'", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReferencedActions(t *testing.T) {
+	actions := `[
+		{"action": "Service", "name": "server", "service": {"templateRef": "server"}},
+		{"action": "Cluster", "name": "clients", "cluster": {"templateRef": "client", "instances": 2,
+			"inputs": [{"server": ".service.server.one"}]}},
+		{"action": "Chaos", "name": "partition", "chaos": {"templateRef": "partition", "inputs": [{"target": "server"}]}},
+		{"action": "Call", "name": "probe", "call": {"callable": "ping", "services": ["clients-1", "clients-2", "external"]}},
+		{"action": "Delete", "name": "teardown", "delete": {"jobs": ["server", "clients"]}}
+	]`
+
+	var scenario v1alpha1.Scenario
+
+	if err := json.Unmarshal([]byte(`{"actions": `+actions+`}`), &scenario.Spec); err != nil {
+		t.Fatalf("cannot decode actions: %v", err)
+	}
+
+	callIndex := make(map[string]*v1alpha1.Action)
+	for i := range scenario.Spec.Actions {
+		callIndex[scenario.Spec.Actions[i].Name] = &scenario.Spec.Actions[i]
+	}
+
+	tests := []struct {
+		action string
+		want   []string
+	}{
+		{action: "server", want: nil},
+		{action: "clients", want: []string{"server"}},
+		{action: "partition", want: []string{"server"}},
+		{action: "probe", want: []string{"clients"}},
+		{action: "teardown", want: []string{"server", "clients"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			if got := v1alpha1.ReferencedActions(callIndex[tt.action], callIndex); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReferencedActions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestHelmSpec_Args(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    v1alpha1.HelmSpec
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "install",
+			spec: v1alpha1.HelmSpec{Release: "db", Chart: "frisbee/cockroachdb"},
+			want: []string{
+				"install", "db", "frisbee/cockroachdb",
+				"--namespace", "test", "--wait", "--timeout", "5m0s",
+			},
+		},
+		{
+			name: "upgrade-with-values",
+			spec: v1alpha1.HelmSpec{
+				Operation: v1alpha1.HelmUpgrade,
+				Release:   "db",
+				Chart:     "cockroachdb",
+				Repo:      "https://charts.cockroachdb.com",
+				Version:   "11.0.0",
+				Values: v1alpha1.Parameters{
+					"statefulset.replicas": v1alpha1.ParameterValue(5),
+					"image.tag":            v1alpha1.ParameterValue("v23.1.0"),
+				},
+				Timeout: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+			want: []string{
+				"upgrade", "db", "cockroachdb", "--reuse-values",
+				"--repo", "https://charts.cockroachdb.com",
+				"--version", "11.0.0",
+				"--set-json", `image.tag="v23.1.0"`,
+				"--set-json", "statefulset.replicas=5",
+				"--namespace", "test", "--wait", "--timeout", "10m0s",
+			},
+		},
+		{
+			name: "uninstall",
+			spec: v1alpha1.HelmSpec{Operation: v1alpha1.HelmUninstall, Release: "db"},
+			want: []string{
+				"uninstall", "db",
+				"--namespace", "test", "--wait", "--timeout", "5m0s",
+			},
+		},
+		{
+			name: "install-as-service-account",
+			spec: v1alpha1.HelmSpec{Release: "db", Chart: "frisbee/cockroachdb", ServiceAccountName: "helm"},
+			want: []string{
+				"install", "db", "frisbee/cockroachdb",
+				"--kube-as-user", "system:serviceaccount:test:helm",
+				"--namespace", "test", "--wait", "--timeout", "5m0s",
+			},
+		},
+		{
+			name:    "not-allowed-service-account",
+			spec:    v1alpha1.HelmSpec{Release: "db", Chart: "frisbee/cockroachdb", ServiceAccountName: "installer"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid-service-account",
+			spec:    v1alpha1.HelmSpec{Release: "db", Chart: "frisbee/cockroachdb", ServiceAccountName: "Installer"},
+			wantErr: true,
+		},
+		{
+			name:    "install-without-chart",
+			spec:    v1alpha1.HelmSpec{Release: "db"},
+			wantErr: true,
+		},
+		{
+			name:    "uninstall-with-values",
+			spec:    v1alpha1.HelmSpec{Operation: v1alpha1.HelmUninstall, Release: "db", Values: v1alpha1.Parameters{"a": v1alpha1.ParameterValue(1)}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid-release",
+			spec:    v1alpha1.HelmSpec{Release: "My_DB", Chart: "frisbee/cockroachdb"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown-operation",
+			spec:    v1alpha1.HelmSpec{Operation: "Rollback", Release: "db"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := tt.spec.Args("test"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Args() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetHelmServiceAccounts(t *testing.T) {
+	defer func() {
+		if err := v1alpha1.SetHelmServiceAccounts(v1alpha1.DefaultHelmServiceAccounts); err != nil {
+			t.Fatalf("SetHelmServiceAccounts() error = %v", err)
+		}
+	}()
+
+	if err := v1alpha1.SetHelmServiceAccounts("helm, installer"); err != nil {
+		t.Fatalf("SetHelmServiceAccounts() error = %v", err)
+	}
+
+	if !v1alpha1.IsHelmServiceAccount("installer") {
+		t.Errorf("IsHelmServiceAccount() = false for a configured serviceaccount")
+	}
+
+	// an empty list disallows every serviceaccount.
+	if err := v1alpha1.SetHelmServiceAccounts(""); err != nil {
+		t.Fatalf("SetHelmServiceAccounts() error = %v", err)
+	}
+
+	if v1alpha1.IsHelmServiceAccount("helm") {
+		t.Errorf("IsHelmServiceAccount() = true without configured serviceaccounts")
+	}
+
+	if err := v1alpha1.SetHelmServiceAccounts("Helm"); err == nil {
+		t.Errorf("SetHelmServiceAccounts() accepted an invalid name")
+	}
+}
+
+func TestScenarioValidator_Impersonation(t *testing.T) {
+	scheme := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	// only the editor of the namespace is allowed to impersonate the serviceaccount.
+	c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SubjectAccessReview)
+			attrs := review.Spec.ResourceAttributes
+
+			review.Status.Allowed = review.Spec.User == "editor" && attrs.Verb == "impersonate" &&
+				attrs.Resource == "serviceaccounts" && attrs.Namespace == "test" && attrs.Name == "helm"
+
+			return nil
+		},
+	}).Build()
+
+	validator := v1alpha1.NewScenarioValidator(scheme, c)
+
+	newRequest := func(user string, helm v1alpha1.HelmSpec) admission.Request {
+		scenario := v1alpha1.Scenario{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "Scenario"},
+			ObjectMeta: metav1.ObjectMeta{Name: "helm", Namespace: "test"},
+		}
+
+		scenario.Spec.Actions = []v1alpha1.Action{{
+			ActionType:   v1alpha1.ActionHelm,
+			Name:         "install",
+			EmbedActions: &v1alpha1.EmbedActions{Helm: &helm},
+		}}
+
+		scenario.Spec.SuccessWhen = &v1alpha1.ConditionalExpr{State: `{{.NumSuccessfulJobs}} >= 1`}
+
+		raw, err := json.Marshal(&scenario)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var req admission.Request
+
+		req.Operation = admissionv1.Create
+		req.Namespace = "test"
+		req.UserInfo = authenticationv1.UserInfo{Username: user}
+		req.Object.Raw = raw
+
+		return req
+	}
+
+	tests := []struct {
+		name        string
+		user        string
+		helm        v1alpha1.HelmSpec
+		wantAllowed bool
+	}{
+		{
+			name:        "without-service-account",
+			user:        "viewer",
+			helm:        v1alpha1.HelmSpec{Release: "db", Chart: "frisbee/cockroachdb"},
+			wantAllowed: true,
+		},
+		{
+			name:        "allowed-to-impersonate",
+			user:        "editor",
+			helm:        v1alpha1.HelmSpec{Release: "db", Chart: "frisbee/cockroachdb", ServiceAccountName: "helm"},
+			wantAllowed: true,
+		},
+		{
+			name:        "not-allowed-to-impersonate",
+			user:        "viewer",
+			helm:        v1alpha1.HelmSpec{Release: "db", Chart: "frisbee/cockroachdb", ServiceAccountName: "helm"},
+			wantAllowed: false,
+		},
+		{
+			name:        "not-allowed-by-the-operator",
+			user:        "editor",
+			helm:        v1alpha1.HelmSpec{Release: "db", Chart: "frisbee/cockroachdb", ServiceAccountName: "installer"},
+			wantAllowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := validator.Handle(context.Background(), newRequest(tt.user, tt.helm))
+
+			if resp.Allowed != tt.wantAllowed {
+				t.Errorf("Handle() allowed = %v, want %v. Result: %v", resp.Allowed, tt.wantAllowed, resp.Result)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestIncludeSpec_Instantiate(t *testing.T) {
+	fragment := `{
+		"inputs": {"parameters": {"server": "server"}},
+		"actions": [
+			{"action": "Service", "name": "{{.inputs.parameters.server}}", "service": {"templateRef": "redis.server"}},
+			{"action": "Service", "name": "loader", "depends": {"running": ["server"]},
+				"service": {"templateRef": "ycsb.loader", "inputs": [{"server": ".service.server.one", "count": "10"}]}},
+			{"action": "Call", "name": "probe", "depends": {"success": ["loader"], "after": "10s"},
+				"call": {"callable": "ping", "services": ["server", "external"]}},
+			{"action": "Service", "name": "monitor", "depends": {"after": "1m"}, "service": {"templateRef": "monitor"}}
+		]}`
+
+	tests := []struct {
+		name       string
+		fragment   string
+		parameters v1alpha1.Parameters
+		check      func(t *testing.T, actions []v1alpha1.Action)
+		wantErr    bool
+	}{
+		{
+			name:     "rename-members",
+			fragment: fragment,
+			check: func(t *testing.T, actions []v1alpha1.Action) {
+				var names []string
+
+				for _, action := range actions {
+					names = append(names, action.Name)
+				}
+
+				if want := []string{"db-server", "db-loader", "db-probe", "db-monitor"}; !reflect.DeepEqual(names, want) {
+					t.Errorf("expected actions '%v', got '%v'", want, names)
+				}
+
+				if deps := actions[1].DependsOn.Running; !reflect.DeepEqual(deps, []string{"db-server"}) {
+					t.Errorf("unexpected running dependencies '%v'", deps)
+				}
+
+				if input := string(actions[1].Service.Inputs[0]["server"].Raw); input != `".service.db-server.one"` {
+					t.Errorf("unexpected input '%s'", input)
+				}
+
+				if input := string(actions[1].Service.Inputs[0]["count"].Raw); input != `"10"` {
+					t.Errorf("unexpected input '%s'", input)
+				}
+
+				if services := actions[2].Call.Services; !reflect.DeepEqual(services, []string{"db-server", "external"}) {
+					t.Errorf("unexpected services '%v'", services)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "rename-only-references",
+			fragment: `{"actions": [
+				{"action": "Service", "name": "server", "service": {"templateRef": "server"}},
+				{"action": "Cluster", "name": "workers", "depends": {"running": ["server"]},
+					"cluster": {"templateRef": "worker", "instances": 2, "inputs": [{"role": "server", "peer": "server-1"}]}},
+				{"action": "Call", "name": "probe", "depends": {"running": ["workers"]},
+					"assert": {"state": "{{.IsRunning \"server\" \"workers\"}} == true && \"server\" == \"server\""},
+					"when": {"state": "isRunning(['server', 'external']) && phase(\"workers\") == 'Running'", "syntax": "cel"},
+					"call": {"callable": "ping", "services": ["workers-1", "server-1", "external"]}},
+				{"action": "Wait", "name": "barrier", "depends": {"success": ["probe"]},
+					"wait": {"until": {"allOf": [{"state": "{{.IsSuccessful \"probe\"}}"}]}}},
+				{"action": "Delete", "name": "teardown", "depends": {"success": ["barrier"]}, "delete": {"jobs": ["server", "workers"]}}
+			]}`,
+			check: func(t *testing.T, actions []v1alpha1.Action) {
+				inputs := actions[1].Cluster.Inputs[0]
+
+				if role, peer := string(inputs["role"].Raw), string(inputs["peer"].Raw); role != `"server"` || peer != `"server-1"` {
+					t.Errorf("plain inputs should not be renamed, got role '%s', peer '%s'", role, peer)
+				}
+
+				if services := actions[2].Call.Services; !reflect.DeepEqual(services, []string{"db-workers-1", "server-1", "external"}) {
+					t.Errorf("unexpected services '%v'", services)
+				}
+
+				if assert := string(actions[2].Assert.State); assert != `{{.IsRunning "db-server" "db-workers"}} == true && "server" == "server"` {
+					t.Errorf("unexpected assert '%s'", assert)
+				}
+
+				if when := string(actions[2].When.State); when != `isRunning(['db-server', 'external']) && phase("db-workers") == 'Running'` {
+					t.Errorf("unexpected when '%s'", when)
+				}
+
+				if until := string(actions[3].Wait.Until.AllOf[0].State); until != `{{.IsSuccessful "db-probe"}}` {
+					t.Errorf("unexpected until '%s'", until)
+				}
+
+				if jobs := actions[4].Delete.Jobs; !reflect.DeepEqual(jobs, []string{"db-server", "db-workers"}) {
+					t.Errorf("unexpected deleted jobs '%v'", jobs)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name:     "roots-wait-for-include",
+			fragment: fragment,
+			check: func(t *testing.T, actions []v1alpha1.Action) {
+				if deps := actions[0].DependsOn.Running; !reflect.DeepEqual(deps, []string{"db"}) {
+					t.Errorf("expected root to wait for the include, got '%v'", deps)
+				}
+
+				if actions[2].DependsOn.Since != nil {
+					t.Errorf("internal action should not be relative to the include")
+				}
+
+				since := actions[3].DependsOn.Since
+				if since == nil || since.Action != "db" || since.Phase != v1alpha1.PhaseRunning {
+					t.Errorf("expected delayed root to be relative to the include, got '%v'", since)
+				}
+
+				if after := actions[3].DependsOn.After.Duration; after != time.Minute {
+					t.Errorf("unexpected delay '%s'", after)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "override-parameters",
+			fragment: `{"inputs": {"parameters": {"template": "redis.server"}},
+				"actions": [{"action": "Service", "name": "server", "service": {"templateRef": "{{.inputs.parameters.template}}"}}]}`,
+			parameters: v1alpha1.Parameters{"template": &apiextensionsv1.JSON{Raw: []byte(`"redis.master"`)}},
+			check: func(t *testing.T, actions []v1alpha1.Action) {
+				if ref := actions[0].Service.TemplateRef; ref != "redis.master" {
+					t.Errorf("expected 'redis.master', got '%s'", ref)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			// renaming the server leaves the dependency of the loader dangling.
+			name:       "dangling-dependency",
+			fragment:   fragment,
+			parameters: v1alpha1.Parameters{"server": &apiextensionsv1.JSON{Raw: []byte(`"master"`)}},
+			wantErr:    true,
+		},
+		{
+			name: "fragment-with-finally",
+			fragment: `{"actions": [{"action": "Service", "name": "server", "service": {"templateRef": "server"}}],
+				"finally": [{"action": "Service", "name": "report", "service": {"templateRef": "report"}}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := v1alpha1.GenerateScenarioSpec([]byte(tt.fragment), tt.parameters)
+			if err != nil {
+				t.Fatalf("GenerateScenarioSpec() error = %v", err)
+			}
+
+			include := v1alpha1.IncludeSpec{TemplateRef: "fragment"}
+
+			actions, err := include.Instantiate("db", spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Instantiate() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if err == nil && tt.check != nil {
+				tt.check(t, actions)
+			}
+		})
+	}
+}
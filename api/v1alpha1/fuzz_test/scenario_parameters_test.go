@@ -0,0 +1,138 @@
+package fuzz_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+func TestExpandScenario(t *testing.T) {
+	const inputs = `"inputs": {"parameters": {"servers": 4, "duration": "30", "callable": "launch"}}`
+
+	tests := []struct {
+		name    string
+		spec    string
+		check   func(t *testing.T, spec *v1alpha1.ScenarioSpec)
+		wantErr bool
+	}{
+		{
+			name: "no-parameters",
+			spec: `{"actions": [{"action": "Service", "name": "server", "service": {"templateRef": "server"}}]}`,
+			check: func(t *testing.T, spec *v1alpha1.ScenarioSpec) {
+				if spec.Actions[0].Service.TemplateRef != "server" {
+					t.Errorf("unexpected templateRef '%s'", spec.Actions[0].Service.TemplateRef)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "numeric-field",
+			spec: `{` + inputs + `, "actions": [{"action": "Cluster", "name": "clients",
+				"cluster": {"templateRef": "client", "instances": "{{.inputs.parameters.servers}}"}}]}`,
+			check: func(t *testing.T, spec *v1alpha1.ScenarioSpec) {
+				if spec.Actions[0].Cluster.MaxInstances != 4 {
+					t.Errorf("expected 4 instances, got %d", spec.Actions[0].Cluster.MaxInstances)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "string-fields",
+			spec: `{` + inputs + `, "actions": [{"action": "Call", "name": "{{.inputs.parameters.callable}}-call",
+				"depends": {"after": "{{.inputs.parameters.duration}}s"},
+				"call": {"callable": "{{.inputs.parameters.callable}}", "services": ["server"]}}]}`,
+			check: func(t *testing.T, spec *v1alpha1.ScenarioSpec) {
+				action := spec.Actions[0]
+
+				if action.Name != "launch-call" || action.Call.Callable != "launch" {
+					t.Errorf("unexpected name '%s' or callable '%s'", action.Name, action.Call.Callable)
+				}
+
+				if action.DependsOn.After.Duration != 30*time.Second {
+					t.Errorf("unexpected duration '%s'", action.DependsOn.After.Duration)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "template-inputs-keep-type",
+			spec: `{` + inputs + `, "actions": [{"action": "Service", "name": "server",
+				"service": {"templateRef": "server", "inputs": [{"duration": "{{.inputs.parameters.duration}}", "n": "{{.inputs.parameters.servers}}"}]}}]}`,
+			check: func(t *testing.T, spec *v1alpha1.ScenarioSpec) {
+				params, err := spec.Actions[0].Service.Inputs[0].Unmarshal()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if params["duration"] != "30" || params["n"] != int64(4) {
+					t.Errorf("unexpected inputs '%v'", params)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "mixed-state-expression",
+			spec: `{` + inputs + `, "actions": [{"action": "Service", "name": "server", "service": {"templateRef": "server"},
+				"assert": {"state": "{{.NumSuccessfulJobs}} >= {{.inputs.parameters.servers}}"}}]}`,
+			check: func(t *testing.T, spec *v1alpha1.ScenarioSpec) {
+				if spec.Actions[0].Assert.State != "{{.NumSuccessfulJobs}} >= 4" {
+					t.Errorf("unexpected expression '%s'", spec.Actions[0].Assert.State)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing-parameter",
+			spec: `{` + inputs + `, "actions": [{"action": "Cluster", "name": "clients",
+				"cluster": {"templateRef": "client", "instances": "{{.inputs.parameters.clients}}"}}]}`,
+			wantErr: true,
+		},
+		{
+			name: "not-a-number",
+			spec: `{` + inputs + `, "actions": [{"action": "Cluster", "name": "clients",
+				"cluster": {"templateRef": "client", "instances": "{{.inputs.parameters.callable}}"}}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := v1alpha1.ExpandScenario([]byte(`{"kind": "Scenario", "spec": ` + tt.spec + `}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandScenario() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			var scenario v1alpha1.Scenario
+
+			if err := json.Unmarshal(expanded, &scenario); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if tt.check != nil {
+				tt.check(t, &scenario.Spec)
+			}
+		})
+	}
+}
+
+func TestScenarioSpec_UnmarshalJSON_DoesNotExpand(t *testing.T) {
+	spec := `{"inputs": {"parameters": {"callable": "launch"}}, "actions": [{"action": "Call", "name": "call",
+		"call": {"callable": "{{.inputs.parameters.callable}}", "services": ["server"]}}]}`
+
+	var decoded v1alpha1.ScenarioSpec
+
+	if err := json.Unmarshal([]byte(spec), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	// the parameters are expanded explicitly, by ExpandScenario, and not by the decoding.
+	if callable := decoded.Actions[0].Call.Callable; callable != "{{.inputs.parameters.callable}}" {
+		t.Errorf("Unmarshal() expanded the callable into '%s'", callable)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestExprRange_Contains(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    v1alpha1.ExprRange
+		value   float64
+		want    bool
+		wantErr bool
+	}{
+		{name: "below", expr: "below(20)", value: 10, want: true},
+		{name: "below-bound", expr: "below(20)", value: 20, want: false},
+		{name: "above-negative", expr: "above(-0.5)", value: 0, want: true},
+		{name: "withinrange", expr: "withinrange(5, 20)", value: 12.5, want: true},
+		{name: "withinrange-outside", expr: "withinrange(5, 20)", value: 25, want: false},
+		{name: "outsiderange", expr: "outsiderange(5, 20)", value: 2, want: true},
+		{name: "missing-bound", expr: "withinrange(5)", wantErr: true},
+		{name: "extra-bound", expr: "below(5, 20)", wantErr: true},
+		{name: "invalid-bound", expr: "below(x)", wantErr: true},
+		{name: "unknown-evaluator", expr: "equals(5)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.Contains(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Contains() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePostConditions(t *testing.T) {
+	tests := []struct {
+		name           string
+		postConditions []v1alpha1.PostCondition
+		wantErr        bool
+	}{
+		{
+			name: "state-and-value",
+			postConditions: []v1alpha1.PostCondition{
+				{Name: "no-failures", State: "{{.NumFailedJobs}} == 0"},
+				{Name: "latency", Value: "max() of query(wpFnYRwGk/2/latency)", Is: "below(20)"},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "empty",
+			postConditions: []v1alpha1.PostCondition{{Name: "empty"}},
+			wantErr:        true,
+		},
+		{
+			name: "duplicate",
+			postConditions: []v1alpha1.PostCondition{
+				{Name: "check", State: "{{.NumFailedJobs}} == 0"},
+				{Name: "check", State: "{{.NumSuccessfulJobs}} >= 1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "state-with-value",
+			postConditions: []v1alpha1.PostCondition{
+				{Name: "both", State: "{{.NumFailedJobs}} == 0", Value: "max() of query(wpFnYRwGk/2/latency)", Is: "below(20)"},
+			},
+			wantErr: true,
+		},
+		{
+			name:           "value-without-range",
+			postConditions: []v1alpha1.PostCondition{{Name: "latency", Value: "max() of query(wpFnYRwGk/2/latency)"}},
+			wantErr:        true,
+		},
+		{
+			name:           "invalid-value",
+			postConditions: []v1alpha1.PostCondition{{Name: "latency", Value: "max() of wpFnYRwGk/2/latency", Is: "below(20)"}},
+			wantErr:        true,
+		},
+		{
+			name:           "invalid-state",
+			postConditions: []v1alpha1.PostCondition{{Name: "check", State: "{{.NumFailedJobs}} =="}},
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v1alpha1.ValidatePostConditions(tt.postConditions); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePostConditions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestActionTimeline_Table(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	at := func(offset time.Duration) *metav1.Time {
+		t := metav1.NewTime(start.Add(offset))
+
+		return &t
+	}
+
+	timeline := v1alpha1.ActionTimeline{
+		{
+			Name:        "servers",
+			ActionType:  v1alpha1.ActionCluster,
+			ScheduledAt: *at(0),
+			RunningAt:   at(10 * time.Second),
+			CompletedAt: at(5 * time.Minute),
+			Phase:       v1alpha1.PhaseSuccess,
+			Reason:      "AllJobsCompleted",
+			Children:    3,
+		},
+		{
+			Name:        "teardown",
+			ActionType:  v1alpha1.ActionDelete,
+			ScheduledAt: *at(5 * time.Minute),
+			Phase:       v1alpha1.PhasePending,
+		},
+	}
+
+	_, data := timeline.Table()
+
+	want := [][]string{
+		{"servers", "Cluster", "Success", "AllJobsCompleted", "3", "+0s", "+10s", "+5m0s", "4m50s"},
+		{"teardown", "Delete", "Pending", "", "0", "+5m0s", "-", "-", "-"},
+	}
+
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Table() = %v, want %v", data, want)
+	}
+
+	if record := timeline.Get("teardown"); record == nil || record.ActionType != v1alpha1.ActionDelete {
+		t.Errorf("Get() = %v, want the record of 'teardown'", record)
+	}
+
+	if record := timeline.Get("clients"); record != nil {
+		t.Errorf("Get() = %v, want nil", record)
+	}
+}
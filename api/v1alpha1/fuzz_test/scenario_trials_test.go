@@ -0,0 +1,169 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestTrialsStatus_Summarize(t *testing.T) {
+	metrics := []v1alpha1.TrialMetric{
+		{Name: "throughput", Value: "avg() of query(wpFnYRwGk/2/bitrate)"},
+	}
+
+	tests := []struct {
+		name         string
+		results      []v1alpha1.TrialResult
+		wantPassRate float64
+		wantSamples  int
+		wantMean     float64
+		wantStdDev   float64
+		wantBounds   [2]float64
+	}{
+		{
+			name: "three-trials",
+			results: []v1alpha1.TrialResult{
+				{Trial: 1, Phase: v1alpha1.PhaseSuccess, AssertionsPassed: true, Values: map[string]float64{"throughput": 10}},
+				{Trial: 2, Phase: v1alpha1.PhaseSuccess, AssertionsPassed: true, Values: map[string]float64{"throughput": 12}},
+				{Trial: 3, Phase: v1alpha1.PhaseFailed, AssertionsPassed: false, Values: map[string]float64{"throughput": 14}},
+			},
+			wantPassRate: 2.0 / 3.0,
+			wantSamples:  3,
+			wantMean:     12,
+			wantStdDev:   2,
+			wantBounds:   [2]float64{12 - 4.303*2/math.Sqrt(3), 12 + 4.303*2/math.Sqrt(3)},
+		},
+		{
+			name: "missing-values",
+			results: []v1alpha1.TrialResult{
+				{Trial: 1, Phase: v1alpha1.PhaseSuccess, AssertionsPassed: true, Values: map[string]float64{"throughput": 8}},
+				{Trial: 2, Phase: v1alpha1.PhaseFailed, AssertionsPassed: true},
+			},
+			wantPassRate: 1,
+			wantSamples:  1,
+			wantMean:     8,
+			wantStdDev:   0,
+			wantBounds:   [2]float64{8, 8},
+		},
+		{
+			name:         "no-trials",
+			results:      nil,
+			wantPassRate: 0,
+			wantSamples:  0,
+			wantMean:     0,
+			wantStdDev:   0,
+			wantBounds:   [2]float64{0, 0},
+		},
+	}
+
+	almostEqual := func(a, b float64) bool {
+		return math.Abs(a-b) < 1e-9
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trials := v1alpha1.TrialsStatus{Results: tt.results}
+			trials.Summarize(metrics)
+
+			if !almostEqual(trials.Summary.AssertionPassRate, tt.wantPassRate) {
+				t.Errorf("AssertionPassRate = %v, want %v", trials.Summary.AssertionPassRate, tt.wantPassRate)
+			}
+
+			got := trials.Summary.Metrics[0]
+
+			if got.Samples != tt.wantSamples {
+				t.Errorf("Samples = %d, want %d", got.Samples, tt.wantSamples)
+			}
+
+			if !almostEqual(got.Mean, tt.wantMean) || !almostEqual(got.StdDev, tt.wantStdDev) {
+				t.Errorf("Mean, StdDev = %v, %v, want %v, %v", got.Mean, got.StdDev, tt.wantMean, tt.wantStdDev)
+			}
+
+			if !almostEqual(got.LowerBound, tt.wantBounds[0]) || !almostEqual(got.UpperBound, tt.wantBounds[1]) {
+				t.Errorf("CI = [%v, %v], want %v", got.LowerBound, got.UpperBound, tt.wantBounds)
+			}
+		})
+	}
+}
+
+func TestValidateRepeat(t *testing.T) {
+	tests := []struct {
+		name    string
+		repeat  *v1alpha1.RepeatSpec
+		wantErr bool
+	}{
+		{
+			name:    "no-repeat",
+			repeat:  nil,
+			wantErr: false,
+		},
+		{
+			name: "valid",
+			repeat: &v1alpha1.RepeatSpec{
+				Trials: 5,
+				Metrics: []v1alpha1.TrialMetric{
+					{Name: "throughput", Value: "avg() of query(wpFnYRwGk/2/bitrate)"},
+					{Name: "latency", Value: "median() of query(summary/152/tx-avg)"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "zero-trials",
+			repeat:  &v1alpha1.RepeatSpec{Trials: 0},
+			wantErr: true,
+		},
+		{
+			name: "unsupported-reducer",
+			repeat: &v1alpha1.RepeatSpec{
+				Trials:  2,
+				Metrics: []v1alpha1.TrialMetric{{Name: "throughput", Value: "diff() of query(wpFnYRwGk/2/bitrate)"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "alert-expression",
+			repeat: &v1alpha1.RepeatSpec{
+				Trials:  2,
+				Metrics: []v1alpha1.TrialMetric{{Name: "throughput", Value: "avg() of query(wpFnYRwGk/2/bitrate, 1m, now) is below(4)"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate-metric",
+			repeat: &v1alpha1.RepeatSpec{
+				Trials: 2,
+				Metrics: []v1alpha1.TrialMetric{
+					{Name: "throughput", Value: "avg() of query(wpFnYRwGk/2/bitrate)"},
+					{Name: "throughput", Value: "max() of query(wpFnYRwGk/2/bitrate)"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v1alpha1.ValidateRepeat(tt.repeat); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRepeat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
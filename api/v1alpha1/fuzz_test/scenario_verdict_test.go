@@ -0,0 +1,120 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestScenario_ValidateVerdict(t *testing.T) {
+	const (
+		servers = `{"action": "Cluster", "name": "servers", "cluster": {"templateRef": "server", "instances": 3}}`
+		assert  = `{"action": "Cluster", "name": "servers", "cluster": {"templateRef": "server", "instances": 3},
+			"assert": {"metrics": "avg() of query(wpFnYRwGk/2/bitrate, 1m, now) is below(100)"}}`
+		metrics = `{"metrics": "avg() of query(wpFnYRwGk/2/bitrate, 1m, now) is below(100)"}`
+		guard   = `{"action": "Delete", "name": "guarded", "depends": {"running": ["servers"]},
+			"when": {"metrics": "avg() of query(wpFnYRwGk/2/bitrate, 1m, now) is above(200)"},
+			"delete": {"jobs": ["servers"]}}`
+		state = `{"state": "{{.NumRunningJobs}} >= 1"}`
+	)
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{
+			name:    "long-lived-without-verdict",
+			spec:    `{"actions": [` + servers + `]}`,
+			wantErr: true,
+		},
+		{
+			name:    "long-lived-with-success-when",
+			spec:    `{"actions": [` + servers + `], "successWhen": {"state": "{{.NumRunningJobs}} >= 1"}}`,
+			wantErr: false,
+		},
+		{
+			name:    "long-lived-with-fail-when",
+			spec:    `{"actions": [` + servers + `], "failWhen": {"state": "{{.NumFailedJobs}} >= 1"}}`,
+			wantErr: true,
+		},
+		{
+			name: "success-and-fail-when",
+			spec: `{"actions": [` + servers + `], "successWhen": {"state": "{{.NumRunningJobs}} >= 1"},
+				"failWhen": {"state": "{{.NumFailedJobs}} >= 1"}}`,
+			wantErr: false,
+		},
+		{
+			name:    "invalid-state",
+			spec:    `{"actions": [` + servers + `], "successWhen": {"state": "{{.NumRunningJobs}} >="}}`,
+			wantErr: true,
+		},
+		{
+			name:    "metrics",
+			spec:    `{"actions": [` + servers + `], "successWhen": ` + metrics + `}`,
+			wantErr: false,
+		},
+		{
+			name:    "metrics-in-success-and-fail-when",
+			spec:    `{"actions": [` + servers + `], "successWhen": ` + metrics + `, "failWhen": ` + metrics + `}`,
+			wantErr: true,
+		},
+		{
+			name:    "metrics-with-metrics-assertion",
+			spec:    `{"actions": [` + assert + `], "successWhen": ` + metrics + `}`,
+			wantErr: true,
+		},
+		{
+			name:    "metrics-guard",
+			spec:    `{"actions": [` + servers + `, ` + guard + `], "successWhen": ` + state + `}`,
+			wantErr: false,
+		},
+		{
+			name:    "metrics-guard-with-metrics-assertion",
+			spec:    `{"actions": [` + assert + `, ` + guard + `], "successWhen": ` + state + `}`,
+			wantErr: true,
+		},
+		{
+			name: "finally-with-metrics-guard",
+			spec: `{"actions": [` + servers + `], "successWhen": ` + state + `,
+				"finally": [{"action": "Service", "name": "report", "service": {"templateRef": "report"}, "when": ` + metrics + `}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "state-with-metrics-assertion",
+			spec:    `{"actions": [` + assert + `], "successWhen": {"state": "{{.NumRunningJobs}} >= 1"}}`,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scenario v1alpha1.Scenario
+
+			if err := json.Unmarshal([]byte(tt.spec), &scenario.Spec); err != nil {
+				t.Fatalf("cannot decode spec: %v", err)
+			}
+
+			if _, err := scenario.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
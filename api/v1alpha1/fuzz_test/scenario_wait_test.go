@@ -0,0 +1,86 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckAction_Wait(t *testing.T) {
+	tests := []struct {
+		name    string
+		wait    *v1alpha1.BarrierSpec
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			wait:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "no-duration-or-condition",
+			wait:    &v1alpha1.BarrierSpec{},
+			wantErr: true,
+		},
+		{
+			name:    "duration",
+			wait:    &v1alpha1.BarrierSpec{Duration: &metav1.Duration{Duration: time.Minute}},
+			wantErr: false,
+		},
+		{
+			name:    "negative-duration",
+			wait:    &v1alpha1.BarrierSpec{Duration: &metav1.Duration{Duration: -time.Minute}},
+			wantErr: true,
+		},
+		{
+			name:    "state",
+			wait:    &v1alpha1.BarrierSpec{Until: &v1alpha1.ConditionalExpr{State: `{{.NumSuccessfulJobs}} >= 1`}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid-state",
+			wait:    &v1alpha1.BarrierSpec{Until: &v1alpha1.ConditionalExpr{State: `{{.NumSuccessfulJobs}} >=`}},
+			wantErr: true,
+		},
+		{
+			name: "metrics-after-duration",
+			wait: &v1alpha1.BarrierSpec{
+				Duration: &metav1.Duration{Duration: time.Minute},
+				Until:    &v1alpha1.ConditionalExpr{Metrics: "avg() of query(wpFnYRwGk/2/bitrate, 1m, now) is below(100)"},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := v1alpha1.Action{
+				ActionType:   v1alpha1.ActionWait,
+				Name:         "barrier",
+				EmbedActions: &v1alpha1.EmbedActions{Wait: tt.wait},
+			}
+
+			if err := v1alpha1.CheckAction(&action, nil); (err != nil) != tt.wantErr {
+				t.Errorf("CheckAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
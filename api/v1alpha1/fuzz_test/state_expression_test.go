@@ -154,3 +154,147 @@ func TestFiredState(t *testing.T) {
 		})
 	}
 }
+
+func TestCELState(t *testing.T) {
+	state := new(lifecycle.Classifier)
+	state.Reset()
+
+	setJobs(state)
+
+	variables := map[string]string{"leader": "service2"}
+
+	tests := []struct {
+		name     string
+		expr     v1alpha1.ExprState
+		wantErr  bool
+		wantPass bool
+	}{
+		{
+			name:     "empty expression",
+			expr:     "",
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "unknown function",
+			expr:     `isSomethingWrong("service0")`,
+			wantErr:  true,
+			wantPass: false,
+		},
+		{
+			name:     "unknown identifier",
+			expr:     `isSuccessful(service0)`,
+			wantErr:  true,
+			wantPass: false,
+		},
+		{
+			name:     "non-boolean expression",
+			expr:     `numRunningJobs()`,
+			wantErr:  true,
+			wantPass: false,
+		},
+		{
+			name:     "type mismatch",
+			expr:     `numRunningJobs() == "2"`,
+			wantErr:  true,
+			wantPass: false,
+		},
+		{
+			name:     "test should pass",
+			expr:     `isSuccessful("service0")`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "test should fail",
+			expr:     `isFailed("service0")`,
+			wantErr:  false,
+			wantPass: false,
+		},
+		{
+			name:     "list of jobs",
+			expr:     `isRunning(["service2", "service3"]) && !isRunning(["service0", "service2"])`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "numeric comparison",
+			expr:     `numRunningJobs() == 2 && numFailedJobs() <= 1`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "job lists",
+			expr:     `"service1" in listFailedJobs() && listRunningJobs().all(job, job.startsWith("service"))`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "variables",
+			expr:     `isRunning(variables.leader)`,
+			wantErr:  false,
+			wantPass: true,
+		},
+		{
+			name:     "missing variable",
+			expr:     `variables.follower == "service3"`,
+			wantErr:  true,
+			wantPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pass, err := tt.expr.CELValuate(state, variables)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CELValuate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if pass != tt.wantPass {
+				t.Errorf("CELValuate() pass = %v, want %v", pass, tt.wantPass)
+			}
+		})
+	}
+}
+
+func TestValidateExpr_Syntax(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    *v1alpha1.ConditionalExpr
+		wantErr bool
+	}{
+		{
+			name:    "template syntax by default",
+			expr:    &v1alpha1.ConditionalExpr{State: `{{.IsSuccessful "clients"}} == true`},
+			wantErr: false,
+		},
+		{
+			name:    "template expression with cel syntax",
+			expr:    &v1alpha1.ConditionalExpr{State: `{{.IsSuccessful "clients"}} == true`, Syntax: v1alpha1.ExprSyntaxCEL},
+			wantErr: true,
+		},
+		{
+			name:    "cel expression",
+			expr:    &v1alpha1.ConditionalExpr{State: `isSuccessful("clients") && variables.leader != ""`, Syntax: v1alpha1.ExprSyntaxCEL},
+			wantErr: false,
+		},
+		{
+			name:    "typo in cel expression",
+			expr:    &v1alpha1.ConditionalExpr{State: `isSucessful("clients")`, Syntax: v1alpha1.ExprSyntaxCEL},
+			wantErr: true,
+		},
+		{
+			name:    "unknown syntax",
+			expr:    &v1alpha1.ConditionalExpr{State: `true`, Syntax: "lua"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v1alpha1.ValidateExpr(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExpr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
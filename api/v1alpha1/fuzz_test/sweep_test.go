@@ -0,0 +1,171 @@
+package fuzz_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func values(raw ...string) []apiextensionsv1.JSON {
+	list := make([]apiextensionsv1.JSON, 0, len(raw))
+
+	for _, value := range raw {
+		list = append(list, apiextensionsv1.JSON{Raw: []byte(value)})
+	}
+
+	return list
+}
+
+const sweepScenario = `{"inputs": {"parameters": {"servers": 1}}, "actions": [
+	{"action": "Cluster", "name": "clients", "cluster": {"templateRef": "client", "instances": "{{.inputs.parameters.servers}}"}},
+	{"action": "Delete", "name": "teardown", "depends": {"running": ["clients"], "after": "{{.inputs.parameters.duration}}"},
+		"delete": {"jobs": ["clients"]}}
+]}`
+
+func TestSweep_GenerateRuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		sweep    v1alpha1.SweepSpec
+		wantRuns int
+		wantErr  bool
+	}{
+		{
+			name: "single-axis",
+			sweep: v1alpha1.SweepSpec{
+				Scenario: apiextensionsv1.JSON{Raw: []byte(sweepScenario)},
+				Axes: []v1alpha1.SweepAxis{
+					{Name: "servers", Values: values("1", "2", "4")},
+					{Name: "duration", Values: values(`"1m"`)},
+				},
+			},
+			wantRuns: 3,
+			wantErr:  false,
+		},
+		{
+			name: "product-with-repetitions",
+			sweep: v1alpha1.SweepSpec{
+				Scenario: apiextensionsv1.JSON{Raw: []byte(sweepScenario)},
+				Axes: []v1alpha1.SweepAxis{
+					{Name: "servers", Values: values("1", "2", "4")},
+					{Name: "duration", Values: values(`"1m"`, `"5m"`)},
+				},
+				Repetitions: 2,
+			},
+			wantRuns: 12,
+			wantErr:  false,
+		},
+		{
+			name: "missing-parameter",
+			sweep: v1alpha1.SweepSpec{
+				Scenario: apiextensionsv1.JSON{Raw: []byte(sweepScenario)},
+				Axes: []v1alpha1.SweepAxis{
+					{Name: "servers", Values: values("1", "2")},
+				},
+			},
+			wantRuns: 2,
+			wantErr:  true,
+		},
+		{
+			name: "duplicate-axis",
+			sweep: v1alpha1.SweepSpec{
+				Scenario: apiextensionsv1.JSON{Raw: []byte(sweepScenario)},
+				Axes: []v1alpha1.SweepAxis{
+					{Name: "duration", Values: values(`"1m"`)},
+					{Name: "duration", Values: values(`"5m"`)},
+				},
+			},
+			wantRuns: 1,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sweep := v1alpha1.Sweep{Spec: tt.sweep}
+			sweep.SetName("sweep")
+			sweep.Default()
+
+			runs := sweep.GenerateRuns()
+			if len(runs) != tt.wantRuns {
+				t.Errorf("GenerateRuns() = %d runs, want %d", len(runs), tt.wantRuns)
+			}
+
+			if _, err := sweep.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// the parameters of a run override the defaults of the scenario.
+	sweep := v1alpha1.Sweep{Spec: tests[1].sweep}
+	sweep.SetName("sweep")
+
+	runs := sweep.GenerateRuns()
+
+	scenario, err := sweep.GenerateScenario(runs[len(runs)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scenario.GetName() != runs[len(runs)-1].Name || scenario.GetNamespace() != scenario.GetName() {
+		t.Errorf("unexpected scenario '%s/%s'", scenario.GetNamespace(), scenario.GetName())
+	}
+
+	// the names of the runs are unique, and stable across invocations.
+	names := make(map[string]bool, len(runs))
+
+	for i, run := range sweep.GenerateRuns() {
+		if run.Name != runs[i].Name {
+			t.Errorf("run %d is renamed from '%s' to '%s'", i, runs[i].Name, run.Name)
+		}
+
+		if !strings.HasPrefix(run.Name, "sweep-") || len(run.Name) > 63 {
+			t.Errorf("invalid run name '%s'", run.Name)
+		}
+
+		if names[run.Name] {
+			t.Errorf("duplicate run name '%s'", run.Name)
+		}
+
+		names[run.Name] = true
+	}
+
+	if instances := scenario.Spec.Actions[0].Cluster.MaxInstances; instances != 4 {
+		t.Errorf("expected 4 instances, got %d", instances)
+	}
+
+	if after := scenario.Spec.Actions[1].DependsOn.After.Duration.String(); after != "5m0s" {
+		t.Errorf("expected after '5m0s', got '%s'", after)
+	}
+}
+
+func TestIsSweepChild(t *testing.T) {
+	sweep := v1alpha1.Sweep{}
+	sweep.SetName("sweep")
+	sweep.SetNamespace("default")
+	sweep.SetUID("1234")
+
+	var child v1alpha1.Scenario
+
+	v1alpha1.SetSweepLabels(&child, &sweep)
+
+	if !v1alpha1.IsSweepChild(&child, &sweep) {
+		t.Errorf("IsSweepChild() = false for a child of the sweep")
+	}
+
+	// a sweep with the same name, that has been deleted and re-created.
+	recreated := sweep.DeepCopy()
+	recreated.SetUID("5678")
+
+	if v1alpha1.IsSweepChild(&child, recreated) {
+		t.Errorf("IsSweepChild() = true for a child of another sweep")
+	}
+
+	var foreign v1alpha1.Scenario
+
+	if v1alpha1.IsSweepChild(&foreign, &sweep) {
+		t.Errorf("IsSweepChild() = true for an unlabeled scenario")
+	}
+}
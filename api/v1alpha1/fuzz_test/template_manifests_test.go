@@ -0,0 +1,228 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz_test
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestManifestSpec_Objects(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "single",
+			raw: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  key: value
+`,
+			want: []string{"ConfigMap.settings"},
+		},
+		{
+			name: "multi-document",
+			raw: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: loader
+---
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: proxy
+`,
+			want: []string{"Job.loader", "Deployment.proxy"},
+		},
+		{
+			name:    "empty",
+			raw:     "---\n",
+			wantErr: true,
+		},
+		{
+			name: "no-kind",
+			raw: `
+apiVersion: v1
+metadata:
+  name: settings
+`,
+			wantErr: true,
+		},
+		{
+			name: "generated-name",
+			raw: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: loader-
+`,
+			wantErr: true,
+		},
+		{
+			name: "duplicate",
+			raw: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: loader
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: loader
+`,
+			wantErr: true,
+		},
+		{
+			name: "unsupported-kind",
+			raw: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: admin
+`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			raw:     "kind: [Job",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1alpha1.ManifestSpec{Raw: tt.raw}
+
+			got, err := spec.Objects()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Objects() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Objects() = %d objects, want %d", len(got), len(tt.want))
+			}
+
+			for i, obj := range got {
+				if key := v1alpha1.ManifestKey(obj); key != tt.want[i] {
+					t.Errorf("Objects()[%d] = %v, want %v", i, key, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTemplate_ValidateManifests(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{
+			name: "templated-name",
+			raw: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: "{{.inputs.parameters.name}}"
+`,
+		},
+		{
+			name: "unsupported-kind",
+			raw: `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: "{{.inputs.parameters.name}}"
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := v1alpha1.Template{
+				Spec: v1alpha1.TemplateSpec{
+					Inputs: &v1alpha1.TemplateInputs{
+						Parameters: v1alpha1.Parameters{"name": v1alpha1.ParameterValue("loader")},
+					},
+					EmbedSpecs: &v1alpha1.EmbedSpecs{
+						Manifests: &v1alpha1.ManifestSpec{Raw: tt.raw},
+					},
+				},
+			}
+
+			template.SetName("manifests")
+
+			if _, err := template.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetApplyKinds(t *testing.T) {
+	widget := `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: gadget
+`
+
+	defer func() {
+		if err := v1alpha1.SetApplyKinds(v1alpha1.DefaultApplyKinds); err != nil {
+			t.Fatalf("SetApplyKinds() error = %v", err)
+		}
+	}()
+
+	if _, err := (&v1alpha1.ManifestSpec{Raw: widget}).Objects(); err == nil {
+		t.Errorf("Objects() accepted a kind outside of the defaults")
+	}
+
+	if err := v1alpha1.SetApplyKinds("ConfigMap, Widget.example.com"); err != nil {
+		t.Fatalf("SetApplyKinds() error = %v", err)
+	}
+
+	if _, err := (&v1alpha1.ManifestSpec{Raw: widget}).Objects(); err != nil {
+		t.Errorf("Objects() error = %v", err)
+	}
+
+	if _, err := (&v1alpha1.ManifestSpec{Raw: "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: loader\n"}).Objects(); err == nil {
+		t.Errorf("Objects() accepted a kind that is no longer set")
+	}
+
+	for _, kinds := range []string{"", " , ", ".example.com"} {
+		if err := v1alpha1.SetApplyKinds(kinds); err == nil {
+			t.Errorf("SetApplyKinds(%q) accepted invalid kinds", kinds)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// DefaultApplyKinds are the kinds of objects that the Apply action can create, unless configured otherwise.
+// Kinds are given as comma-separated 'Kind.group' (e.g, 'Job.batch'), and core kinds have no group.
+const DefaultApplyKinds = "ConfigMap,Secret,Service,PersistentVolumeClaim,Pod,Job.batch,Deployment.apps," +
+	"StatefulSet.apps,Ingress.networking.k8s.io,NetworkPolicy.networking.k8s.io"
+
+var (
+	applyKindsMu sync.RWMutex
+	applyKinds   = mustParseApplyKinds(DefaultApplyKinds)
+)
+
+// SetApplyKinds sets the kinds of objects that the Apply action can create, in the format of DefaultApplyKinds.
+// Manifests of other kinds are rejected upon admission. The operator must be granted the permissions
+// (get;list;watch;create;update;patch;delete) for these kinds, as done by the 'operator.apply.kinds' of the chart.
+func SetApplyKinds(kinds string) error {
+	parsed, err := parseApplyKinds(kinds)
+	if err != nil {
+		return err
+	}
+
+	applyKindsMu.Lock()
+	defer applyKindsMu.Unlock()
+
+	applyKinds = parsed
+
+	return nil
+}
+
+// IsApplyKind returns true if the Apply action can create objects of the given kind.
+func IsApplyKind(gk schema.GroupKind) bool {
+	applyKindsMu.RLock()
+	defer applyKindsMu.RUnlock()
+
+	_, supported := applyKinds[gk]
+
+	return supported
+}
+
+func parseApplyKinds(kinds string) (map[schema.GroupKind]struct{}, error) {
+	parsed := make(map[schema.GroupKind]struct{})
+
+	for _, kind := range strings.Split(kinds, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+
+		gk := schema.ParseGroupKind(kind)
+		if gk.Kind == "" {
+			return nil, errors.Errorf("invalid kind '%s'", kind)
+		}
+
+		parsed[gk] = struct{}{}
+	}
+
+	if len(parsed) == 0 {
+		return nil, errors.New("no kinds")
+	}
+
+	return parsed, nil
+}
+
+func mustParseApplyKinds(kinds string) map[schema.GroupKind]struct{} {
+	parsed, err := parseApplyKinds(kinds)
+	if err != nil {
+		panic(errors.Wrapf(err, "invalid apply kinds"))
+	}
+
+	return parsed
+}
+
+// ManifestSpec is a multi-document YAML of Kubernetes objects, as used in 'kubectl apply'. The objects must be
+// namespaced, of the kinds set by SetApplyKinds, and they are created in the namespace of the scenario.
+type ManifestSpec struct {
+	Raw string `json:"raw,omitempty"`
+}
+
+// Objects decodes the manifests into unstructured objects. Every object must have an apiVersion, a supported kind,
+// and a name. Empty documents are ignored.
+func (in *ManifestSpec) Objects() ([]*unstructured.Unstructured, error) {
+	objects, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{})
+
+	for i, obj := range objects {
+		// objects are tracked by name. Generated names are therefore not supported.
+		if obj.GetName() == "" {
+			return nil, errors.Errorf("manifest '%d' (%s) has no name", i, obj.GetKind())
+		}
+
+		key := ManifestKey(obj)
+		if _, exists := names[key]; exists {
+			return nil, errors.Errorf("duplicate manifest '%s'", key)
+		}
+
+		names[key] = struct{}{}
+	}
+
+	return objects, nil
+}
+
+// CheckKinds validates that the manifests are well-formed, and that all the objects are of supported kinds.
+// Unlike Objects, it does not check the names, as they may be set by the inputs of a template.
+func (in *ManifestSpec) CheckKinds() error {
+	_, err := in.decode()
+
+	return err
+}
+
+func (in *ManifestSpec) decode() ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(in.Raw), 4096)
+
+	var objects []*unstructured.Unstructured
+
+	for {
+		var body map[string]interface{}
+
+		if err := decoder.Decode(&body); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, errors.Wrapf(err, "cannot decode manifest '%d'", len(objects))
+		}
+
+		if len(body) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: body}
+
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+			return nil, errors.Errorf("manifest '%d' has no apiVersion or kind", len(objects))
+		}
+
+		if !IsApplyKind(obj.GroupVersionKind().GroupKind()) {
+			return nil, errors.Errorf("manifest '%d' has unsupported kind '%s'", len(objects), obj.GroupVersionKind().GroupKind())
+		}
+
+		objects = append(objects, obj)
+	}
+
+	if len(objects) == 0 {
+		return nil, errors.New("no manifests")
+	}
+
+	return objects, nil
+}
+
+// ManifestKey returns a unique identifier for the object, in the form of 'kind.name' (e.g, Job.loader).
+func ManifestKey(obj *unstructured.Unstructured) string {
+	return obj.GetKind() + "." + obj.GetName()
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// CaptureStream is the output stream of a call.
+type CaptureStream string
+
+const (
+	CaptureStdout CaptureStream = "Stdout"
+	CaptureStderr CaptureStream = "Stderr"
+)
+
+// CaptureSpec extracts a value from the output of a call into a scenario variable.
+// Exactly one of Regex and JSONPath must be defined.
+type CaptureSpec struct {
+	// Name is the name of the variable. Subsequent actions reference it as {{.variables.<name>}}.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+
+	// Stream is the output from which the value is extracted. Defaults to Stdout.
+	// +kubebuilder:validation:Enum=Stdout;Stderr
+	// +optional
+	Stream CaptureStream `json:"stream,omitempty"`
+
+	// Regex extracts the first group of the first match. If the regex has no groups, the whole match is used.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// JSONPath extracts a field from the output, parsed as JSON, e.g, {.leader.id}.
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+}
+
+// variableName matches the valid names of variables.
+var variableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Validate checks that the capture is well-formed.
+func (in *CaptureSpec) Validate() error {
+	if !variableName.MatchString(in.Name) {
+		return errors.Errorf("invalid variable name '%s'", in.Name)
+	}
+
+	switch in.Stream {
+	case "", CaptureStdout, CaptureStderr:
+	default:
+		return errors.Errorf("unknown stream '%s'", in.Stream)
+	}
+
+	switch {
+	case in.Regex != "" && in.JSONPath != "", in.Regex == "" && in.JSONPath == "":
+		return errors.Errorf("variable '%s' requires exactly one of regex and jsonPath", in.Name)
+
+	case in.Regex != "":
+		if _, err := regexp.Compile(in.Regex); err != nil {
+			return errors.Wrapf(err, "variable '%s'", in.Name)
+		}
+
+	default:
+		if err := jsonpath.New(in.Name).Parse(in.JSONPath); err != nil {
+			return errors.Wrapf(err, "variable '%s'", in.Name)
+		}
+	}
+
+	return nil
+}
+
+// Extract returns the value of the variable, as found in the output of a call.
+func (in *CaptureSpec) Extract(stdout, stderr string) (string, error) {
+	output := stdout
+	if in.Stream == CaptureStderr {
+		output = stderr
+	}
+
+	if in.Regex != "" {
+		re, err := regexp.Compile(in.Regex)
+		if err != nil {
+			return "", errors.Wrapf(err, "regex error")
+		}
+
+		match := re.FindStringSubmatch(output)
+
+		switch {
+		case match == nil:
+			return "", errors.Errorf("regex '%s' does not match the output", in.Regex)
+		case len(match) > 1:
+			return match[1], nil
+		default:
+			return match[0], nil
+		}
+	}
+
+	var data interface{}
+
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		return "", errors.Wrapf(err, "output is not json")
+	}
+
+	parser := jsonpath.New(in.Name)
+
+	if err := parser.Parse(in.JSONPath); err != nil {
+		return "", errors.Wrapf(err, "jsonpath error")
+	}
+
+	var out bytes.Buffer
+
+	if err := parser.Execute(&out, data); err != nil {
+		return "", errors.Wrapf(err, "jsonpath '%s' does not match the output", in.JSONPath)
+	}
+
+	return out.String(), nil
+}
+
+/*
+	Expand Scenario Variables
+*/
+
+// variableRef matches the references to the scenario variables, e.g, {{.variables.leader}}.
+var variableRef = regexp.MustCompile(`{{\s*\.variables\.([a-zA-Z_][a-zA-Z0-9_]*)\s*}}`)
+
+// VariablesAware is implemented by objects that hold captured variables.
+// +kubebuilder:object:generate=false
+type VariablesAware interface {
+	GetVariables() map[string]string
+}
+
+// HasVariables returns true if the string references any variable.
+func HasVariables(s string) bool {
+	return strings.Contains(s, ".variables.") && variableRef.MatchString(s)
+}
+
+// ExpandVariables replaces the references to variables with their values.
+// It fails if a referenced variable is not yet captured.
+func ExpandVariables(s string, variables map[string]string) (string, error) {
+	var missing []string
+
+	expanded := variableRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := variableRef.FindStringSubmatch(ref)[1]
+
+		value, exists := variables[name]
+		if !exists {
+			missing = append(missing, name)
+		}
+
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", errors.Errorf("variables '%s' are not captured", missing)
+	}
+
+	return expanded, nil
+}
+
+// MaskVariables replaces the references to variables with a dummy value. It is used for validating
+// expressions whose variables are known only at runtime.
+func MaskVariables(s string) string {
+	return variableRef.ReplaceAllString(s, "variable")
+}
+
+// ExpandVariables returns a copy of the action, where the references to variables in the spec of the action
+// are replaced with their values. Conditions are not expanded, as they are expanded upon evaluation.
+func (in *Action) ExpandVariables(variables map[string]string) (*Action, error) {
+	action := in.DeepCopy()
+
+	if action.EmbedActions == nil {
+		return action, nil
+	}
+
+	body, err := json.Marshal(action.EmbedActions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot marshal action")
+	}
+
+	if !HasVariables(string(body)) {
+		return action, nil
+	}
+
+	var raw interface{}
+
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, errors.Wrapf(err, "cannot unmarshal action")
+	}
+
+	expanded, err := expandVariablesValue(raw, variables)
+	if err != nil {
+		return nil, errors.Wrapf(err, "action '%s'", in.Name)
+	}
+
+	body, err = json.Marshal(expanded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot marshal expanded action")
+	}
+
+	var spec EmbedActions
+
+	if err := json.Unmarshal(body, &spec); err != nil {
+		return nil, errors.Wrapf(err, "cannot unmarshal expanded action")
+	}
+
+	action.EmbedActions = &spec
+
+	return action, nil
+}
+
+func expandVariablesValue(value interface{}, variables map[string]string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return ExpandVariables(v, variables)
+
+	case []interface{}:
+		for i := range v {
+			expanded, err := expandVariablesValue(v[i], variables)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = expanded
+		}
+
+	case map[string]interface{}:
+		for key := range v {
+			expanded, err := expandVariablesValue(v[key], variables)
+			if err != nil {
+				return nil, errors.Wrapf(err, "field '%s'", key)
+			}
+
+			v[key] = expanded
+		}
+	}
+
+	return value, nil
+}
@@ -91,6 +91,11 @@ type Timeline []metav1.Time
 
 // Next returns the next activation time, later than the given time.
 func (in Timeline) Next(ref time.Time) time.Time {
+	return in.NextAt(ref, time.Now())
+}
+
+// NextAt is like Next, but the time that the controller runs at is given, instead of using the wall clock.
+func (in Timeline) NextAt(ref time.Time, now time.Time) time.Time {
 	for _, t := range in {
 		if t.After(ref) {
 			return t.Time
@@ -99,7 +104,7 @@ func (in Timeline) Next(ref time.Time) time.Time {
 
 	// bad hack. If there is no actual schedule, return something far in the future
 	// for the controller to keep running, but also to raise trigger to the test.
-	return time.Now().Add(12 * time.Hour)
+	return now.Add(12 * time.Hour)
 }
 
 func (in Timeline) String() string {
@@ -29,6 +29,10 @@ import (
 
 // ConditionalExpr is a source of information about whether the state of the workflow after a given time is correct or not.
 // This is needed because some scenarios may run in infinite-horizons.
+// Expressions can be composed with allOf, anyOf, and not. Within compositions, metrics sub-expressions have the
+// same meaning as a metrics expression at the top level: in verdicts and waits they are true once their alert is
+// fired, elsewhere they are true for as long as their alert is not fired. The sub-expressions of the composition
+// are reported in the evaluation message (e.g, 'allOf is false: {[0] true (...); [1] false (...)}').
 type ConditionalExpr struct {
 	// Metrics set a Grafana alert that will be triggered once the condition is met.
 	// Parsing:
@@ -39,21 +43,87 @@ type ConditionalExpr struct {
 	// +nullable
 	Metrics ExprMetrics `json:"metrics,omitempty"`
 
+	// PromQL is evaluated periodically against the Prometheus of the scenario, without going through Grafana.
+	// It compares the samples of a query against a threshold, optionally for a given duration
+	// (e.g, 'histogram_quantile(0.99, sum(rate(latency_bucket[1m])) by (le)) < 0.02 for 1m').
+	// Unlike Metrics, the condition is true once the comparison holds for all the samples of the query.
+	// +optional
+	PromQL ExprPromQL `json:"promQL,omitempty"`
+
 	// State describe the runtime condition that should be met after the action has been executed
 	// Shall be defined using .Lifecycle() methods. The methods account only jobs that are managed by the object.
 	// +optional
 	// +nullable
 	State ExprState `json:"state,omitempty"`
+
+	// Syntax selects the language of the State expression. The default 'template' syntax renders the expression
+	// as a Go template (e.g, '{{.IsSuccessful "clients"}} == true'). The 'cel' syntax type-checks the expression
+	// at admission, and evaluates it with CEL (e.g, 'isSuccessful("clients") && numFailedJobs() == 0').
+	// +optional
+	Syntax ExprSyntax `json:"syntax,omitempty"`
+
+	// AllOf is true if all the sub-expressions are true. Sub-expressions may be compositions themselves.
+	// It cannot be combined with other expressions at the same level.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	AllOf []ConditionalExpr `json:"allOf,omitempty"`
+
+	// AnyOf is true if at least one of the sub-expressions is true. Sub-expressions may be compositions themselves.
+	// It cannot be combined with other expressions at the same level.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	AnyOf []ConditionalExpr `json:"anyOf,omitempty"`
+
+	// Not negates the sub-expression. It cannot be combined with other expressions at the same level.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Not *ConditionalExpr `json:"not,omitempty"`
 }
 
 func (in *ConditionalExpr) IsZero() bool {
-	return in == nil || *in == (ConditionalExpr{})
+	return in == nil || (in.Metrics == "" && in.PromQL == "" && in.State == "" && in.Syntax == "" && !in.IsCompound())
+}
+
+// IsCompound returns true if the expression is a composition (allOf, anyOf, not) of sub-expressions.
+func (in *ConditionalExpr) IsCompound() bool {
+	return in != nil && (len(in.AllOf) > 0 || len(in.AnyOf) > 0 || in.Not != nil)
+}
+
+// Leaves returns the non-compound expressions of the composition, in depth-first order.
+// For a non-compound expression, it returns the expression itself.
+func (in *ConditionalExpr) Leaves() []*ConditionalExpr {
+	if in.IsZero() {
+		return nil
+	}
+
+	if !in.IsCompound() {
+		return []*ConditionalExpr{in}
+	}
+
+	var leaves []*ConditionalExpr
+
+	for i := range in.AllOf {
+		leaves = append(leaves, in.AllOf[i].Leaves()...)
+	}
+
+	for i := range in.AnyOf {
+		leaves = append(leaves, in.AnyOf[i].Leaves()...)
+	}
+
+	return append(leaves, in.Not.Leaves()...)
 }
 
 func (in *ConditionalExpr) HasMetricsExpr() bool {
 	return in != nil && in.Metrics != ""
 }
 
+func (in *ConditionalExpr) HasPromQLExpr() bool {
+	return in != nil && in.PromQL != ""
+}
+
 func (in *ConditionalExpr) HasStateExpr() bool {
 	return in != nil && in.State != ""
 }
@@ -107,6 +177,23 @@ func (expr ExprState) Evaluate(state interface{}) (string, error) {
 	return out.String(), nil
 }
 
+// polledStateFunctions are the functions whose values change without a phase transition.
+var polledStateFunctions = []string{"timeinphase", "numrestarts", "maxrestarts"}
+
+// IsPolled returns true if the expression depends on the time in phase, or on the restarts of the jobs.
+// Since these values change without a phase transition, the expression must be periodically re-evaluated.
+func (expr ExprState) IsPolled() bool {
+	lowercase := strings.ToLower(string(expr))
+
+	for _, fn := range polledStateFunctions {
+		if strings.Contains(lowercase, fn) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GoValuate wraps the Evaluate function to the GoValuate expressions.
 func (expr ExprState) GoValuate(state interface{}) (bool, error) {
 	if expr == "" {
@@ -186,3 +273,31 @@ func (query ExprMetrics) Parse() ([]string, error) {
 
 	return matches, nil
 }
+
+/*
+	Validate Value Expressions
+*/
+
+// +kubebuilder:object:generate=false
+
+// ExprValueValidator expressions reduce the values of a Grafana query into a single number.
+var ExprValueValidator = regexp.MustCompile(`(?m)^(?P<reducer>avg|min|max|sum|count|last|median)\(\)\s+of\s+query\((?P<dashboardUID>\w+)\/(?P<panelID>\d+)\/(?P<metric>[^,\s\)]+)\)\s*$`)
+
+// ExprValue is a metrics query whose values are reduced into a single number, e.g, for aggregating the
+// outcome of multiple trials. Unlike ExprMetrics, the time range is not given by the expression.
+type ExprValue string
+
+func (query ExprValue) Parse() ([]string, error) {
+	matches := ExprValueValidator.FindStringSubmatch(string(query))
+
+	if len(matches) == 0 {
+		return nil, errors.Errorf(`erroneous query '%s'.
+		Examples:
+			- 'avg() of query(wpFnYRwGk/2/bitrate)'
+			- 'max() of query(summary/152/tx-avg)'
+
+		Supported reducers: avg, min, max, sum, count, last, median`, query)
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter/functions"
+	"github.com/pkg/errors"
+)
+
+// ExprSyntax is the language of state expressions.
+// +kubebuilder:validation:Enum=template;cel
+type ExprSyntax string
+
+const (
+	// ExprSyntaxTemplate renders the expression as a Go template, and evaluates the outcome with govaluate.
+	// Unknown identifiers are compared as strings. This is the default syntax.
+	ExprSyntaxTemplate ExprSyntax = "template"
+
+	// ExprSyntaxCEL type-checks the expression against the declared environment, and evaluates it with CEL.
+	// Example: 'isSuccessful(["server", "clients"]) && numFailedJobs() == 0 && variables.leader == "server-1"'
+	ExprSyntaxCEL ExprSyntax = "cel"
+)
+
+// Validate ensures that the syntax is known.
+func (syntax ExprSyntax) Validate() error {
+	switch syntax {
+	case "", ExprSyntaxTemplate, ExprSyntaxCEL:
+		return nil
+	default:
+		return errors.Errorf("unknown syntax '%s'. Expected '%s' or '%s'", syntax, ExprSyntaxTemplate, ExprSyntaxCEL)
+	}
+}
+
+/*
+	CEL Environment
+*/
+
+// celVariables is the name of the map with the variables captured by the scenario.
+const celVariables = "variables"
+
+// celJobStatus are the functions of the JobStatus. They accept the name of a job, or a list of names.
+var celJobStatus = map[string]func(state StateAggregationFunctions, jobs ...string) bool{
+	"isPending":    func(state StateAggregationFunctions, jobs ...string) bool { return state.IsPending(jobs...) },
+	"isRunning":    func(state StateAggregationFunctions, jobs ...string) bool { return state.IsRunning(jobs...) },
+	"isSuccessful": func(state StateAggregationFunctions, jobs ...string) bool { return state.IsSuccessful(jobs...) },
+	"isFailed":     func(state StateAggregationFunctions, jobs ...string) bool { return state.IsFailed(jobs...) },
+}
+
+// celNumberOfJobs are the functions of the NumberOfJobs.
+var celNumberOfJobs = map[string]func(state StateAggregationFunctions) int{
+	"numPendingJobs":    StateAggregationFunctions.NumPendingJobs,
+	"numRunningJobs":    StateAggregationFunctions.NumRunningJobs,
+	"numSuccessfulJobs": StateAggregationFunctions.NumSuccessfulJobs,
+	"numFailedJobs":     StateAggregationFunctions.NumFailedJobs,
+}
+
+// celListJobs are the functions of the ListJobs.
+var celListJobs = map[string]func(state StateAggregationFunctions) []string{
+	"listPendingJobs":    StateAggregationFunctions.ListPendingJobs,
+	"listRunningJobs":    StateAggregationFunctions.ListRunningJobs,
+	"listSuccessfulJobs": StateAggregationFunctions.ListSuccessfulJobs,
+	"listFailedJobs":     StateAggregationFunctions.ListFailedJobs,
+}
+
+// celJobInfo are the string functions of the JobInfo. They accept the name of a job.
+var celJobInfo = map[string]func(state StateAggregationFunctions, job string) string{
+	"phase":  StateAggregationFunctions.Phase,
+	"reason": StateAggregationFunctions.Reason,
+}
+
+// The remaining functions of the JobInfo.
+const (
+	celTimeInPhase = "timeInPhase"
+	celNumRestarts = "numRestarts"
+	celMaxRestarts = "maxRestarts"
+)
+
+// celEnvironment declares the functions and the variables of state expressions. The functions are declared
+// without implementation, since they are bound to the state upon evaluation.
+var celEnvironment, celEnvironmentErr = newCELEnvironment()
+
+func newCELEnvironment() (*cel.Env, error) {
+	options := []cel.EnvOption{
+		cel.Variable(celVariables, cel.MapType(cel.StringType, cel.StringType)),
+	}
+
+	for name := range celJobStatus {
+		options = append(options, cel.Function(name,
+			cel.Overload(name+"_string", []*cel.Type{cel.StringType}, cel.BoolType),
+			cel.Overload(name+"_list", []*cel.Type{cel.ListType(cel.StringType)}, cel.BoolType),
+		))
+	}
+
+	for name := range celNumberOfJobs {
+		options = append(options, cel.Function(name, cel.Overload(name, []*cel.Type{}, cel.IntType)))
+	}
+
+	for name := range celListJobs {
+		options = append(options, cel.Function(name, cel.Overload(name, []*cel.Type{}, cel.ListType(cel.StringType))))
+	}
+
+	for name := range celJobInfo {
+		options = append(options, cel.Function(name, cel.Overload(name, []*cel.Type{cel.StringType}, cel.StringType)))
+	}
+
+	options = append(options,
+		cel.Function(celTimeInPhase, cel.Overload(celTimeInPhase, []*cel.Type{cel.StringType}, cel.DurationType)),
+		cel.Function(celNumRestarts, cel.Overload(celNumRestarts, []*cel.Type{cel.StringType}, cel.IntType)),
+		cel.Function(celMaxRestarts, cel.Overload(celMaxRestarts, []*cel.Type{}, cel.IntType)),
+	)
+
+	return cel.NewEnv(options...)
+}
+
+// celBindings implements the declared functions over the given state.
+func celBindings(state StateAggregationFunctions) []*functions.Overload {
+	var bindings []*functions.Overload
+
+	for name, fn := range celJobStatus {
+		fn := fn
+
+		bindings = append(bindings,
+			&functions.Overload{
+				Operator: name + "_string",
+				Unary: func(job ref.Val) ref.Val {
+					return types.Bool(fn(state, string(job.(types.String))))
+				},
+			},
+			&functions.Overload{
+				Operator: name + "_list",
+				Unary: func(jobs ref.Val) ref.Val {
+					native, err := jobs.ConvertToNative(reflect.TypeOf([]string{}))
+					if err != nil {
+						return types.NewErr("cannot convert the list of jobs: %v", err)
+					}
+
+					return types.Bool(fn(state, native.([]string)...))
+				},
+			},
+		)
+	}
+
+	for name, fn := range celNumberOfJobs {
+		fn := fn
+
+		bindings = append(bindings, &functions.Overload{
+			Operator: name,
+			Function: func(...ref.Val) ref.Val { return types.Int(fn(state)) },
+		})
+	}
+
+	for name, fn := range celListJobs {
+		fn := fn
+
+		bindings = append(bindings, &functions.Overload{
+			Operator: name,
+			Function: func(...ref.Val) ref.Val { return types.NewStringList(types.DefaultTypeAdapter, fn(state)) },
+		})
+	}
+
+	for name, fn := range celJobInfo {
+		fn := fn
+
+		bindings = append(bindings, &functions.Overload{
+			Operator: name,
+			Unary: func(job ref.Val) ref.Val {
+				return types.String(fn(state, string(job.(types.String))))
+			},
+		})
+	}
+
+	bindings = append(bindings,
+		&functions.Overload{
+			Operator: celTimeInPhase,
+			Unary: func(job ref.Val) ref.Val {
+				return types.Duration{Duration: state.TimeInPhase(string(job.(types.String)))}
+			},
+		},
+		&functions.Overload{
+			Operator: celNumRestarts,
+			Unary: func(job ref.Val) ref.Val {
+				return types.Int(state.NumRestarts(string(job.(types.String))))
+			},
+		},
+		&functions.Overload{
+			Operator: celMaxRestarts,
+			Function: func(...ref.Val) ref.Val { return types.Int(state.MaxRestarts()) },
+		},
+	)
+
+	return bindings
+}
+
+// celPrograms caches the type-checked expressions, since the same expressions are evaluated on every reconciliation.
+var celPrograms sync.Map
+
+/*
+	CEL Expressions
+*/
+
+// CompileCEL parses and type-checks the state expression against the declared environment.
+// The expression must evaluate to a boolean.
+func (expr ExprState) CompileCEL() (*cel.Ast, error) {
+	if celEnvironmentErr != nil {
+		return nil, errors.Wrapf(celEnvironmentErr, "invalid CEL environment")
+	}
+
+	if cached, ok := celPrograms.Load(expr); ok {
+		return cached.(*cel.Ast), nil
+	}
+
+	ast, issues := celEnvironment.Compile(string(expr))
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Errorf("invalid expression '%s': %s", expr, issues.Err())
+	}
+
+	if !reflect.DeepEqual(ast.OutputType(), cel.BoolType) {
+		return nil, errors.Errorf("expected boolean expression for '%s'. Got %s", expr, ast.OutputType())
+	}
+
+	celPrograms.Store(expr, ast)
+
+	return ast, nil
+}
+
+// CELValuate evaluates the state expression as a CEL expression, over the given state and variables.
+func (expr ExprState) CELValuate(state StateAggregationFunctions, variables map[string]string) (bool, error) {
+	if expr == "" {
+		return true, nil
+	}
+
+	ast, err := expr.CompileCEL()
+	if err != nil {
+		return false, err
+	}
+
+	program, err := celEnvironment.Program(ast, cel.Functions(celBindings(state)...))
+	if err != nil {
+		return false, errors.Wrapf(err, "cannot instantiate expression '%s'", expr)
+	}
+
+	if variables == nil {
+		variables = map[string]string{}
+	}
+
+	out, _, err := program.Eval(map[string]interface{}{celVariables: variables})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate expression '%s'", expr)
+	}
+
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.Errorf("expected boolean evaluation for '%s'. Got %v", expr, out)
+	}
+
+	return result, nil
+}
+
+// Valuate evaluates the state expression with the given syntax. Template expressions reference the variables
+// as '{{.variables.name}}', and are expanded before the evaluation. CEL expressions reference the variables
+// as 'variables.name'.
+func (expr ExprState) Valuate(syntax ExprSyntax, state StateAggregationFunctions, variables map[string]string) (bool, error) {
+	if syntax == ExprSyntaxCEL {
+		return expr.CELValuate(state, variables)
+	}
+
+	if HasVariables(string(expr)) {
+		expanded, err := ExpandVariables(string(expr), variables)
+		if err != nil {
+			return false, errors.Wrapf(err, "cannot expand variables")
+		}
+
+		expr = ExprState(expanded)
+	}
+
+	return expr.GoValuate(state)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+/*
+	Validate PromQL Expressions
+*/
+
+// +kubebuilder:object:generate=false
+
+// ExprPromQLValidator splits a PromQL expression into the query, the comparison, and the optional duration.
+// Since the expression is anchored to the threshold, comparisons within the query are left intact.
+var ExprPromQLValidator = regexp.MustCompile(`(?s)^\s*(?P<query>.+?)\s*(?P<operator><=|>=|==|!=|<|>)\s*(?P<threshold>[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?)(?:\s+for\s+(?P<for>\w+))?\s*$`)
+
+// ExprPromQL is a PromQL query compared against a threshold, optionally for a given duration.
+// Example: 'histogram_quantile(0.99, sum(rate(latency_bucket[1m])) by (le)) < 0.02 for 1m'.
+type ExprPromQL string
+
+// PromQLRule is the parsed form of a PromQL expression.
+// +kubebuilder:object:generate=false
+type PromQLRule struct {
+	// Query is evaluated as an instant query against Prometheus.
+	Query string
+
+	// Operator compares every sample of the query against the Threshold.
+	Operator string
+
+	Threshold float64
+
+	// For is the duration for which the comparison must hold before the condition is met.
+	For time.Duration
+}
+
+// Compare returns true if the value satisfies the comparison of the rule.
+func (rule *PromQLRule) Compare(value float64) bool {
+	switch rule.Operator {
+	case "<":
+		return value < rule.Threshold
+	case "<=":
+		return value <= rule.Threshold
+	case ">":
+		return value > rule.Threshold
+	case ">=":
+		return value >= rule.Threshold
+	case "==":
+		return value == rule.Threshold
+	case "!=":
+		return value != rule.Threshold
+	default:
+		panic(errors.Errorf("unknown operator '%s'", rule.Operator))
+	}
+}
+
+func (rule *PromQLRule) String() string {
+	expr := rule.Query + " " + rule.Operator + " " + strconv.FormatFloat(rule.Threshold, 'g', -1, 64)
+
+	if rule.For > 0 {
+		expr += " for " + rule.For.String()
+	}
+
+	return expr
+}
+
+func (query ExprPromQL) Parse() (*PromQLRule, error) {
+	matches := ExprPromQLValidator.FindStringSubmatch(string(query))
+
+	if len(matches) == 0 {
+		return nil, errors.Errorf(`erroneous promQL '%s'.
+		Examples:
+			- 'sum(rate(http_requests_total[1m])) > 100'
+			- 'histogram_quantile(0.99, sum(rate(latency_bucket[1m])) by (le)) < 0.02 for 1m'
+			- 'up{job="server"} == 1 for 30s'
+
+		Supported operators: <, <=, >, >=, ==, !=`, query)
+	}
+
+	rule := &PromQLRule{
+		Query:    strings.TrimSpace(matches[ExprPromQLValidator.SubexpIndex("query")]),
+		Operator: matches[ExprPromQLValidator.SubexpIndex("operator")],
+	}
+
+	threshold, err := strconv.ParseFloat(matches[ExprPromQLValidator.SubexpIndex("threshold")], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid threshold")
+	}
+
+	rule.Threshold = threshold
+
+	if duration := matches[ExprPromQLValidator.SubexpIndex("for")]; duration != "" {
+		rule.For, err = time.ParseDuration(duration)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid duration")
+		}
+	}
+
+	return rule, nil
+}
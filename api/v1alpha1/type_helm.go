@@ -0,0 +1,238 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// HelmOperation is the operation on a Helm release.
+type HelmOperation string
+
+const (
+	// HelmInstall installs a new release.
+	HelmInstall HelmOperation = "Install"
+	// HelmUpgrade upgrades an existing release. Values that are not given are reused from the previous revision.
+	HelmUpgrade HelmOperation = "Upgrade"
+	// HelmUninstall removes an existing release.
+	HelmUninstall HelmOperation = "Uninstall"
+)
+
+// maxReleaseName is the maximum length of a release name, as imposed by Helm.
+const maxReleaseName = 53
+
+// DefaultHelmTimeout is the time to wait for the resources of a release, if no timeout is set.
+const DefaultHelmTimeout = 5 * time.Minute
+
+// DefaultHelmServiceAccounts are the ServiceAccounts that Helm actions can use, unless configured otherwise.
+// ServiceAccounts are given as comma-separated names, and are allowed in every namespace.
+const DefaultHelmServiceAccounts = "helm"
+
+var (
+	helmServiceAccountsMu sync.RWMutex
+	helmServiceAccounts   = parseHelmServiceAccounts(DefaultHelmServiceAccounts)
+)
+
+// SetHelmServiceAccounts sets the ServiceAccounts that Helm actions can use, in the format of
+// DefaultHelmServiceAccounts. An empty list disallows the serviceAccountName of Helm actions. The operator must be
+// allowed to impersonate these ServiceAccounts, as done by the 'operator.helm.serviceAccounts' of the chart.
+func SetHelmServiceAccounts(names string) error {
+	parsed := parseHelmServiceAccounts(names)
+
+	for name := range parsed {
+		if errs := validation.IsDNS1123Subdomain(name); errs != nil {
+			return errors.Errorf("invalid serviceaccount '%s': %s", name, strings.Join(errs, "; "))
+		}
+	}
+
+	helmServiceAccountsMu.Lock()
+	defer helmServiceAccountsMu.Unlock()
+
+	helmServiceAccounts = parsed
+
+	return nil
+}
+
+// IsHelmServiceAccount returns true if Helm actions can use the ServiceAccount.
+func IsHelmServiceAccount(name string) bool {
+	helmServiceAccountsMu.RLock()
+	defer helmServiceAccountsMu.RUnlock()
+
+	_, allowed := helmServiceAccounts[name]
+
+	return allowed
+}
+
+func parseHelmServiceAccounts(names string) map[string]struct{} {
+	parsed := make(map[string]struct{})
+
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			parsed[name] = struct{}{}
+		}
+	}
+
+	return parsed
+}
+
+// HelmSpec installs, upgrades, or uninstalls a Helm release in the namespace of the scenario.
+type HelmSpec struct {
+	// Operation is the operation on the release. Defaults to Install.
+	// +kubebuilder:validation:Enum=Install;Upgrade;Uninstall
+	// +optional
+	Operation HelmOperation `json:"operation,omitempty"`
+
+	// Release is the name of the release.
+	Release string `json:"release"`
+
+	// Chart is the chart to be installed (e.g, frisbee/ycsb), as a reference, a path, or a URL.
+	// It is required for installs and upgrades.
+	// +optional
+	Chart string `json:"chart,omitempty"`
+
+	// Repo is the URL of the chart repository.
+	// +optional
+	Repo string `json:"repo,omitempty"`
+
+	// Version is the version of the chart. If it is not set, the latest version is used.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Values override the values of the chart. Keys are paths in the values of the chart (e.g, image.tag).
+	// Values can reference the scenario parameters, e.g, {{.inputs.parameters.replicas}}.
+	// +optional
+	Values Parameters `json:"values,omitempty"`
+
+	// Timeout is the time to wait for the resources of the release to become ready. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// ServiceAccountName is a ServiceAccount in the namespace of the scenario, whose permissions are used
+	// for operating the release (helm --kube-as-user). The ServiceAccount must be allowed to manage the resources
+	// of the chart, and the secrets where Helm stores the release (e.g, a RoleBinding to the 'admin' ClusterRole).
+	// If it is not set, the release is operated with the permissions of the controller, which are limited to the
+	// kinds used by Frisbee. Most charts also need ServiceAccounts, Roles, or PodDisruptionBudgets, and therefore
+	// require a ServiceAccount.
+	//
+	// The controller impersonates the ServiceAccount. Therefore, whoever can create a scenario could act with the
+	// permissions of the ServiceAccount, which is an escalation path if these permissions exceed their own.
+	// To guard it, the ServiceAccount must be one that the operator is allowed to impersonate
+	// (operator.helm.serviceAccounts of the chart), and the creator of the scenario must be allowed to
+	// impersonate the ServiceAccount as well (as granted, for example, by the 'edit' ClusterRole of the namespace).
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// Validate checks that the release can be operated.
+func (in *HelmSpec) Validate() error {
+	switch in.Operation {
+	case "", HelmInstall, HelmUpgrade:
+		if in.Chart == "" {
+			return errors.Errorf("chart is required for '%s'", in.GetOperation())
+		}
+
+	case HelmUninstall:
+		if in.Chart != "" || in.Repo != "" || in.Version != "" || len(in.Values) > 0 {
+			return errors.New("uninstall does not accept chart, repo, version, or values")
+		}
+
+	default:
+		return errors.Errorf("unknown operation '%s'", in.Operation)
+	}
+
+	if errs := validation.IsDNS1123Label(in.Release); errs != nil {
+		return errors.Errorf("invalid release '%s': %s", in.Release, strings.Join(errs, "; "))
+	}
+
+	if len(in.Release) > maxReleaseName {
+		return errors.Errorf("release '%s' exceeds %d characters", in.Release, maxReleaseName)
+	}
+
+	if in.Timeout != nil && in.Timeout.Duration <= 0 {
+		return errors.Errorf("invalid timeout '%s'", in.Timeout.Duration)
+	}
+
+	if in.ServiceAccountName != "" {
+		if errs := validation.IsDNS1123Subdomain(in.ServiceAccountName); errs != nil {
+			return errors.Errorf("invalid serviceAccountName '%s': %s", in.ServiceAccountName, strings.Join(errs, "; "))
+		}
+
+		if !IsHelmServiceAccount(in.ServiceAccountName) {
+			return errors.Errorf("serviceAccountName '%s' is not allowed by the operator", in.ServiceAccountName)
+		}
+	}
+
+	return nil
+}
+
+// GetOperation returns the operation on the release, or Install if no operation is set.
+func (in *HelmSpec) GetOperation() HelmOperation {
+	if in.Operation == "" {
+		return HelmInstall
+	}
+
+	return in.Operation
+}
+
+// GetTimeout returns the time to wait for the resources of the release, or DefaultHelmTimeout if no timeout is set.
+func (in *HelmSpec) GetTimeout() time.Duration {
+	if in.Timeout == nil {
+		return DefaultHelmTimeout
+	}
+
+	return in.Timeout.Duration
+}
+
+// Args returns the arguments of the helm command for operating the release in the given namespace.
+// The command waits until the resources of the release are ready (or removed).
+func (in *HelmSpec) Args(namespace string) []string {
+	var args []string
+
+	switch in.GetOperation() {
+	case HelmInstall:
+		args = []string{"install", in.Release, in.Chart}
+	case HelmUpgrade:
+		args = []string{"upgrade", in.Release, in.Chart, "--reuse-values"}
+	case HelmUninstall:
+		args = []string{"uninstall", in.Release}
+	}
+
+	if in.Repo != "" {
+		args = append(args, "--repo", in.Repo)
+	}
+
+	if in.Version != "" {
+		args = append(args, "--version", in.Version)
+	}
+
+	for _, key := range structure.SortedMapKeys(in.Values) {
+		args = append(args, "--set-json", key+"="+string(in.Values[key].Raw))
+	}
+
+	if in.ServiceAccountName != "" {
+		args = append(args, "--kube-as-user", "system:serviceaccount:"+namespace+":"+in.ServiceAccountName)
+	}
+
+	return append(args, "--namespace", namespace, "--wait", "--timeout", in.GetTimeout().String())
+}
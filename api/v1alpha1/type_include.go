@@ -0,0 +1,254 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// IncludeSpec instantiates the actions of a scenario fragment within the parent scenario.
+//
+// The instantiated actions are named after the including action, e.g, the action 'masters' of a fragment
+// included by 'db-setup' becomes 'db-setup-masters'. References among the actions of the fragment are renamed
+// accordingly. These are the dependencies, the anchors, the branches, the deleted jobs, the scaled clusters,
+// the services of calls, the macros of inputs (e.g, '.service.masters.one'), and the job arguments of state
+// expressions (e.g, '{{.IsRunning "masters"}}'). Other strings, such as plain input values, are not renamed,
+// even if they happen to match the name of an action.
+//
+// The actions of the fragment that have no dependencies on other actions of the fragment start once the
+// including action is running, and their time offsets are measured since then.
+//
+// The including action succeeds once the actions of the fragment are completed. Actions that other actions
+// of the fragment wait to be running (e.g, the deployed database) are only required to be running.
+// These actions are left running, and the parent scenario is responsible for their termination.
+type IncludeSpec struct {
+	// TemplateRef refers to a Template that defines a scenario fragment (spec.scenario).
+	TemplateRef string `json:"templateRef"`
+
+	// Parameters override the defaults of the fragment's parameters (spec.inputs.parameters).
+	// +optional
+	Parameters Parameters `json:"parameters,omitempty"`
+}
+
+// Instantiate returns the actions of the fragment, as they are included by the given action.
+func (in *IncludeSpec) Instantiate(include string, fragment *ScenarioSpec) ([]Action, error) {
+	if err := ValidateFragment(fragment); err != nil {
+		return nil, errors.Wrapf(err, "invalid fragment '%s'", in.TemplateRef)
+	}
+
+	members := make(map[string]ActionType, len(fragment.Actions))
+
+	for _, action := range fragment.Actions {
+		members[action.Name] = action.ActionType
+	}
+
+	rename := func(name string) string {
+		if _, exists := members[name]; exists {
+			return include + "-" + name
+		}
+
+		return name
+	}
+
+	// the instances of a cluster are named after the cluster, e.g, 'masters-1'.
+	renameService := func(name string) string {
+		if sep := strings.LastIndex(name, "-"); sep > 0 && members[name[:sep]] == ActionCluster {
+			if _, err := strconv.Atoi(name[sep+1:]); err == nil {
+				return rename(name[:sep]) + name[sep:]
+			}
+		}
+
+		return rename(name)
+	}
+
+	actions := make([]Action, len(fragment.Actions))
+
+	for i, action := range fragment.Actions {
+		instance := action.DeepCopy()
+		instance.Name = rename(action.Name)
+
+		// whether the action waits for other actions of the fragment.
+		internal := false
+
+		if deps := instance.DependsOn; deps != nil {
+			for _, list := range [][]string{deps.Running, deps.Success, deps.Failed, deps.Completed} {
+				for j, dep := range list {
+					if _, exists := members[dep]; exists {
+						internal = true
+					}
+
+					list[j] = rename(dep)
+				}
+			}
+
+			if since := deps.Since; since != nil {
+				if _, exists := members[since.Action]; exists {
+					internal = true
+				}
+
+				since.Action = rename(since.Action)
+			}
+		}
+
+		for j, alt := range instance.Else {
+			instance.Else[j] = rename(alt)
+		}
+
+		if err := renameReferences(instance.EmbedActions, rename, renameService); err != nil {
+			return nil, errors.Wrapf(err, "action '%s'", action.Name)
+		}
+
+		renameExprReferences(instance.Assert, rename)
+		renameExprReferences(instance.When, rename)
+
+		if instance.EmbedActions != nil && instance.Wait != nil {
+			renameExprReferences(instance.Wait.Until, rename)
+		}
+
+		// the roots of the fragment start once the including action is running.
+		if !internal {
+			if instance.DependsOn == nil {
+				instance.DependsOn = &WaitSpec{}
+			}
+
+			instance.DependsOn.Running = append(instance.DependsOn.Running, include)
+
+			if instance.DependsOn.After != nil && instance.DependsOn.Since == nil {
+				instance.DependsOn.Since = &TransitionRef{Action: include, Phase: PhaseRunning}
+			}
+		}
+
+		actions[i] = *instance
+	}
+
+	return actions, nil
+}
+
+// renameReferences renames the references to actions in the spec of an action.
+func renameReferences(spec *EmbedActions, rename, renameService func(string) string) error {
+	if spec == nil {
+		return nil
+	}
+
+	switch {
+	case spec.Delete != nil:
+		for i, job := range spec.Delete.Jobs {
+			spec.Delete.Jobs[i] = rename(job)
+		}
+	case spec.Call != nil:
+		for i, service := range spec.Call.Services {
+			spec.Call.Services[i] = renameService(service)
+		}
+	case spec.Scale != nil:
+		spec.Scale.Cluster = rename(spec.Scale.Cluster)
+	case spec.Scenario != nil:
+		for key, value := range spec.Scenario.Parameters {
+			renamed, err := renameMacro(value.Raw, rename)
+			if err != nil {
+				return errors.Wrapf(err, "parameter '%s'", key)
+			}
+
+			spec.Scenario.Parameters[key].Raw = renamed
+		}
+	}
+
+	for _, input := range inputsOf(spec) {
+		for key, value := range input {
+			renamed, err := renameMacro(value.Raw, rename)
+			if err != nil {
+				return errors.Wrapf(err, "input '%s'", key)
+			}
+
+			input[key].Raw = renamed
+		}
+	}
+
+	return nil
+}
+
+// inputsOf returns the template inputs of the action.
+func inputsOf(spec *EmbedActions) []UserInputs {
+	switch {
+	case spec.Service != nil:
+		return spec.Service.Inputs
+	case spec.Cluster != nil:
+		return spec.Cluster.Inputs
+	case spec.Chaos != nil:
+		return spec.Chaos.Inputs
+	case spec.Cascade != nil:
+		return spec.Cascade.Inputs
+	case spec.Apply != nil:
+		return spec.Apply.Inputs
+	default:
+		return nil
+	}
+}
+
+// renameMacro renames the action referenced by a macro value in the form of '.kind.name.selector'.
+// Values other than macros are returned intact.
+func renameMacro(raw []byte, rename func(string) string) ([]byte, error) {
+	var value interface{}
+
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	str, ok := value.(string)
+	if !ok || !strings.HasPrefix(str, ".") {
+		return raw, nil
+	}
+
+	fields := strings.Split(str, ".")
+	if len(fields) != 4 {
+		return raw, nil
+	}
+
+	fields[2] = rename(fields[2])
+
+	return json.Marshal(strings.Join(fields, "."))
+}
+
+// jobFunctionCall matches the calls of the state functions that accept job names, along with their arguments,
+// in both the template (e.g, '.IsRunning "a" "b"') and the CEL syntax (e.g, 'isRunning(["a", "b"])').
+var jobFunctionCall = regexp.MustCompile(
+	`\.(?:IsPending|IsRunning|IsSuccessful|IsFailed|Phase|Reason|TimeInPhase|NumRestarts)((?:\s+"[^"]*")+)` +
+		`|\b(?:isPending|isRunning|isSuccessful|isFailed|phase|reason|timeInPhase|numRestarts)\(([^)]*)\)`)
+
+// stringLiteral matches the double- or single-quoted strings within the arguments of a function call.
+var stringLiteral = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+
+// renameExprReferences renames the job arguments of the state functions within the state expressions.
+func renameExprReferences(expr *ConditionalExpr, rename func(string) string) {
+	for _, leaf := range expr.Leaves() {
+		if !leaf.HasStateExpr() {
+			continue
+		}
+
+		leaf.State = ExprState(jobFunctionCall.ReplaceAllStringFunc(string(leaf.State), func(call string) string {
+			return stringLiteral.ReplaceAllStringFunc(call, func(literal string) string {
+				quote := literal[:1]
+
+				return quote + rename(literal[1:len(literal)-1]) + quote
+			})
+		}))
+	}
+}
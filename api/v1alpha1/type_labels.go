@@ -186,6 +186,46 @@ func GetComponentLabel(obj metav1.Object) Component {
 	return componentType
 }
 
+// ///////////////////////////////////////////
+//		Sweeps
+// ///////////////////////////////////////////
+
+const (
+	// LabelManagedBy marks the namespaces that are managed by Frisbee (e.g, the namespaces of the tests).
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+
+	// LabelSweep points to the sweep that created the resource.
+	LabelSweep = "sweep.frisbee.dev/name"
+
+	// LabelSweepNamespace points to the namespace of the sweep that created the resource. Because the scenarios
+	// of a sweep run on dedicated namespaces, it is used along with LabelSweep for listing the children of a sweep.
+	LabelSweepNamespace = "sweep.frisbee.dev/namespace"
+
+	// LabelSweepUID points to the UID of the sweep that created the resource. Since the children of a sweep are
+	// located in other namespaces, it replaces the owner references for telling apart the children of the sweep
+	// from the resources of others (e.g, of a deleted sweep with the same name).
+	LabelSweepUID = "sweep.frisbee.dev/uid"
+)
+
+// SetSweepLabels links the resource to the given sweep.
+func SetSweepLabels(obj metav1.Object, sweep metav1.Object) {
+	obj.SetLabels(labels.Merge(obj.GetLabels(), GetSweepLabels(sweep)))
+}
+
+// IsSweepChild returns true if the resource is created by the given sweep.
+func IsSweepChild(obj metav1.Object, sweep metav1.Object) bool {
+	return labels.SelectorFromSet(GetSweepLabels(sweep)).Matches(labels.Set(obj.GetLabels()))
+}
+
+// GetSweepLabels returns the labels that identify the children of the given sweep.
+func GetSweepLabels(sweep metav1.Object) map[string]string {
+	return map[string]string{
+		LabelSweep:          sweep.GetName(),
+		LabelSweepNamespace: sweep.GetNamespace(),
+		LabelSweepUID:       string(sweep.GetUID()),
+	}
+}
+
 // ///////////////////////////////////////////
 //		Telemetry Agents
 // ///////////////////////////////////////////
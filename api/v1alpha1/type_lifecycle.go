@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -47,6 +49,13 @@ const (
 	// ConditionAssertionError indicate that an assertion condition is false.
 	ConditionAssertionError = ConditionType("AssertError")
 
+	// ConditionDeadlineExceeded indicate that an action, or the whole workflow, has been active for longer
+	// than its deadline.
+	ConditionDeadlineExceeded = ConditionType("DeadlineExceeded")
+
+	// ReasonDeadlineExceeded is the reason of the lifecycle when ConditionDeadlineExceeded is raised.
+	ReasonDeadlineExceeded = "DeadlineExceeded"
+
 	// ConditionInvalidStateTransition indicates the transition of a resource into another state.
 	// This is used for debugging.
 	ConditionInvalidStateTransition = ConditionType("InvalidStateTransition")
@@ -171,11 +180,28 @@ type ListJobs interface {
 
 // +kubebuilder:object:generate=false
 
+// JobInfo provides time-aware and per-job information. Unknown jobs return zero values.
+type JobInfo interface {
+	// Phase returns the phase of the given job.
+	Phase(job string) string
+	// Reason returns the reason of the phase of the given job.
+	Reason(job string) string
+	// TimeInPhase returns the time since the given job has transitioned to its current phase.
+	TimeInPhase(job string) time.Duration
+	// NumRestarts returns the number of container restarts of the given job.
+	NumRestarts(job string) int
+	// MaxRestarts returns the maximum number of container restarts among the jobs.
+	MaxRestarts() int
+}
+
+// +kubebuilder:object:generate=false
+
 // StateAggregationFunctions is a set of aggregation functions for managing the lifecycle of different resources.
 type StateAggregationFunctions interface {
 	JobStatus
 	NumberOfJobs
 	ListJobs
+	JobInfo
 }
 
 var _ StateAggregationFunctions = (*DefaultClassifier)(nil)
@@ -237,3 +263,23 @@ func (DefaultClassifier) ListFailedJobs() []string {
 func (DefaultClassifier) ListTerminatingJobs() []string {
 	return nil
 }
+
+func (DefaultClassifier) Phase(_ string) string {
+	return ""
+}
+
+func (DefaultClassifier) Reason(_ string) string {
+	return ""
+}
+
+func (DefaultClassifier) TimeInPhase(_ string) time.Duration {
+	return 0
+}
+
+func (DefaultClassifier) NumRestarts(_ string) int {
+	return 0
+}
+
+func (DefaultClassifier) MaxRestarts() int {
+	return 0
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// ScenarioInputs are dynamic fields that populate the actions of a scenario.
+type ScenarioInputs struct {
+	// Parameters are user-set values that the actions can reference using the templating of the Templates,
+	// e.g, {{.inputs.parameters.servers}}. Parameters can be used in any field of the actions, including
+	// numeric fields, such as instances.
+	// +optional
+	Parameters Parameters `json:"parameters,omitempty"`
+}
+
+/*
+	Expand Scenario Parameters
+*/
+
+// parameterRef matches the templates that reference a scenario parameter, e.g, {{.inputs.parameters.servers}}.
+var parameterRef = regexp.MustCompile(`{{[^{}]*\.inputs\.parameters\.[^{}]*}}`)
+
+// plainParameterRef matches templates that consist of nothing more than a parameter, e.g, {{.inputs.parameters.servers}}.
+var plainParameterRef = regexp.MustCompile(`^{{\s*\.inputs\.parameters\.(\w+)\s*}}$`)
+
+var (
+	jsonUnmarshaler = reflect.TypeOf((*interface{ UnmarshalJSON([]byte) error })(nil)).Elem()
+	rawJSONType     = reflect.TypeOf(apiextensionsv1.JSON{})
+)
+
+// ExpandScenario expands the references to the parameters of a raw (JSON) scenario, and returns the expanded
+// scenario. The expansion happens on the raw scenario, before decoding it, because parameters can be used in
+// typed fields, such as instances, that cannot hold the templates.
+func ExpandScenario(raw []byte) ([]byte, error) {
+	if !bytes.Contains(raw, []byte(".inputs.parameters.")) {
+		return raw, nil
+	}
+
+	var scenario map[string]interface{}
+
+	if err := json.Unmarshal(raw, &scenario); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode scenario")
+	}
+
+	spec, _ := scenario["spec"].(map[string]interface{})
+	if spec == nil {
+		return raw, nil
+	}
+
+	if err := ExpandParameters(spec); err != nil {
+		return nil, errors.Wrapf(err, "cannot expand scenario parameters")
+	}
+
+	expanded, err := json.Marshal(scenario)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot encode scenario")
+	}
+
+	return expanded, nil
+}
+
+// GenerateScenarioSpec decodes a raw scenario spec, after overriding the defaults of its parameters
+// (spec.inputs.parameters) with the given values, and expanding the references to them.
+// Parameters that have no default are added.
+func GenerateScenarioSpec(raw []byte, overrides Parameters) (*ScenarioSpec, error) {
+	var spec map[string]interface{}
+
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, errors.Wrapf(err, "cannot decode scenario")
+	}
+
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+
+	inputs, _ := spec["inputs"].(map[string]interface{})
+	if inputs == nil {
+		inputs = map[string]interface{}{}
+		spec["inputs"] = inputs
+	}
+
+	parameters, _ := inputs["parameters"].(map[string]interface{})
+	if parameters == nil {
+		parameters = map[string]interface{}{}
+		inputs["parameters"] = parameters
+	}
+
+	values, err := overrides.Unmarshal()
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range values {
+		parameters[key] = value
+	}
+
+	if err := ExpandParameters(spec); err != nil {
+		return nil, errors.Wrapf(err, "cannot expand scenario parameters")
+	}
+
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot encode scenario")
+	}
+
+	var scenarioSpec ScenarioSpec
+
+	if err := json.Unmarshal(encoded, &scenarioSpec); err != nil {
+		return nil, err
+	}
+
+	return &scenarioSpec, nil
+}
+
+// ExpandParameters replaces the references to the parameters of a raw (i.e, decoded from JSON) scenario spec
+// with their values. The expansion is guided by the types of ScenarioSpec, so that the values of numeric fields
+// become numbers, whereas the values of string fields remain strings.
+func ExpandParameters(rawSpec map[string]interface{}) error {
+	// Step 1. Load the parameters
+	evaluationParams := struct {
+		Inputs struct {
+			Parameters map[string]interface{} `json:"parameters"`
+		} `json:"inputs"`
+	}{}
+
+	if rawInputs, exists := rawSpec["inputs"]; exists {
+		encoded, err := json.Marshal(rawInputs)
+		if err != nil {
+			return errors.Wrapf(err, "cannot marshal inputs")
+		}
+
+		var inputs ScenarioInputs
+
+		if err := json.Unmarshal(encoded, &inputs); err != nil {
+			return errors.Wrapf(err, "cannot unmarshal inputs")
+		}
+
+		params, err := inputs.Parameters.Unmarshal()
+		if err != nil {
+			return err
+		}
+
+		evaluationParams.Inputs.Parameters = params
+	}
+
+	// Step 2. Expand every field, apart from the inputs.
+	fields := jsonFields(reflect.TypeOf(ScenarioSpec{}))
+
+	for key, value := range rawSpec {
+		fieldType, exists := fields[key]
+		if !exists || key == "inputs" {
+			continue
+		}
+
+		expanded, err := expandValue(value, fieldType, &evaluationParams, evaluationParams.Inputs.Parameters)
+		if err != nil {
+			return errors.Wrapf(err, "field '%s'", key)
+		}
+
+		rawSpec[key] = expanded
+	}
+
+	return nil
+}
+
+// expandValue walks the raw value alongside the type it will be decoded into, and expands the strings
+// that reference parameters.
+func expandValue(value interface{}, typ reflect.Type, evaluationParams interface{}, params map[string]interface{}) (interface{}, error) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch v := value.(type) {
+	case string:
+		if !parameterRef.MatchString(v) {
+			return v, nil
+		}
+
+		// Raw JSON values keep the type of the referenced parameter.
+		if typ == rawJSONType {
+			if match := plainParameterRef.FindStringSubmatch(v); match != nil {
+				param, exists := params[match[1]]
+				if !exists {
+					return nil, errors.Errorf("parameter '%s' does not exist", match[1])
+				}
+
+				return param, nil
+			}
+		}
+
+		var evalErr error
+
+		expanded := parameterRef.ReplaceAllStringFunc(v, func(ref string) string {
+			out, err := ExprState(ref).Evaluate(evaluationParams)
+			if err != nil && evalErr == nil {
+				evalErr = errors.Wrapf(err, "cannot expand '%s'", ref)
+			}
+
+			return out
+		})
+
+		if evalErr != nil {
+			return nil, evalErr
+		}
+
+		// Types with custom decoding (e.g, durations) are decoded from strings.
+		if reflect.PointerTo(typ).Implements(jsonUnmarshaler) {
+			return expanded, nil
+		}
+
+		return convertKind(expanded, typ.Kind())
+
+	case []interface{}:
+		if typ.Kind() != reflect.Slice {
+			return v, nil
+		}
+
+		for i := range v {
+			expanded, err := expandValue(v[i], typ.Elem(), evaluationParams, params)
+			if err != nil {
+				return nil, errors.Wrapf(err, "index '%d'", i)
+			}
+
+			v[i] = expanded
+		}
+
+		return v, nil
+
+	case map[string]interface{}:
+		switch {
+		case typ.Kind() == reflect.Map:
+			for key, elem := range v {
+				expanded, err := expandValue(elem, typ.Elem(), evaluationParams, params)
+				if err != nil {
+					return nil, errors.Wrapf(err, "key '%s'", key)
+				}
+
+				v[key] = expanded
+			}
+
+		case typ.Kind() == reflect.Struct && !reflect.PointerTo(typ).Implements(jsonUnmarshaler):
+			fields := jsonFields(typ)
+
+			for key, elem := range v {
+				fieldType, exists := fields[key]
+				if !exists {
+					continue
+				}
+
+				expanded, err := expandValue(elem, fieldType, evaluationParams, params)
+				if err != nil {
+					return nil, errors.Wrapf(err, "field '%s'", key)
+				}
+
+				v[key] = expanded
+			}
+		}
+
+		return v, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// convertKind converts the expanded string to the kind of the field it will be decoded into.
+func convertKind(expanded string, kind reflect.Kind) (interface{}, error) {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(strings.TrimSpace(expanded), 10, 64)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(strings.TrimSpace(expanded), 10, 64)
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(strings.TrimSpace(expanded), 64)
+
+	case reflect.Bool:
+		return strconv.ParseBool(strings.TrimSpace(expanded))
+
+	default:
+		return expanded, nil
+	}
+}
+
+// jsonFields returns the types of the struct fields, indexed by their JSON name.
+// The fields of embedded structs without a JSON name (e.g, `json:",inline"`) are promoted to the parent.
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, typ.NumField())
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" && field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for embeddedName, embeddedType := range jsonFields(embedded) {
+					fields[embeddedName] = embeddedType
+				}
+
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
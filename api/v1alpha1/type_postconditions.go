@@ -0,0 +1,136 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PostCondition is an assertion about the outcome of the scenario. It is evaluated once, when the scenario
+// reaches a terminal phase, either over the final state of the jobs, or over the metrics of the whole scenario.
+type PostCondition struct {
+	// Name is the identifier of the post-condition in the status.
+	Name string `json:"name"`
+
+	// State is evaluated over the final state of the jobs, e.g, '{{.NumFailedJobs}} <= 2'.
+	// +optional
+	State ExprState `json:"state,omitempty"`
+
+	// Syntax selects the language of the State expression (template or cel). Defaults to template.
+	// +optional
+	Syntax ExprSyntax `json:"syntax,omitempty"`
+
+	// Value reduces the values of a Grafana query, over the duration of the scenario, into a single number.
+	// Example: 'max() of query(wpFnYRwGk/2/latency)'
+	// +optional
+	Value ExprValue `json:"value,omitempty"`
+
+	// Is is the range that the value must fall into, e.g, 'below(20)', 'above(5)', 'withinrange(5, 20)',
+	// or 'outsiderange(5, 20)'. The bounds are exclusive.
+	// +optional
+	Is ExprRange `json:"is,omitempty"`
+}
+
+// PostConditionResult is the outcome of a post-condition.
+type PostConditionResult struct {
+	// Name is the identifier of the post-condition.
+	Name string `json:"name"`
+
+	// Passed is true if the post-condition is met.
+	Passed bool `json:"passed"`
+
+	// Value is the reduced value of metrics post-conditions.
+	// +optional
+	Value *float64 `json:"value,omitempty"`
+
+	// Message explains the outcome, and any error in the evaluation of the post-condition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+/*
+	Validate Range Expressions
+*/
+
+// +kubebuilder:object:generate=false
+
+// ExprRangeValidator expressions bound the value of a metric.
+var ExprRangeValidator = regexp.MustCompile(`(?m)^(?P<evaluator>below|above|withinrange|outsiderange)\((?P<params>[^\)]*)\)\s*$`)
+
+// ExprRange is a range that a value must fall into.
+type ExprRange string
+
+// Parse returns the evaluator and the bounds of the range.
+func (expr ExprRange) Parse() (string, []float64, error) {
+	matches := ExprRangeValidator.FindStringSubmatch(strings.TrimSpace(string(expr)))
+
+	if len(matches) == 0 {
+		return "", nil, errors.Errorf(`erroneous range '%s'.
+		Examples:
+			- 'below(20)'
+			- 'above(0.5)'
+			- 'withinrange(5, 20)'
+			- 'outsiderange(5, 20)'`, expr)
+	}
+
+	evaluator := matches[ExprRangeValidator.SubexpIndex("evaluator")]
+
+	var bounds []float64
+
+	for _, param := range strings.Split(matches[ExprRangeValidator.SubexpIndex("params")], ",") {
+		bound, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "invalid bound in range '%s'", expr)
+		}
+
+		bounds = append(bounds, bound)
+	}
+
+	expected := 1
+	if evaluator == "withinrange" || evaluator == "outsiderange" {
+		expected = 2
+	}
+
+	if len(bounds) != expected {
+		return "", nil, errors.Errorf("range '%s' expects %d bounds, but got %d", expr, expected, len(bounds))
+	}
+
+	return evaluator, bounds, nil
+}
+
+// Contains returns true if the value falls into the range.
+func (expr ExprRange) Contains(value float64) (bool, error) {
+	evaluator, bounds, err := expr.Parse()
+	if err != nil {
+		return false, err
+	}
+
+	switch evaluator {
+	case "below":
+		return value < bounds[0], nil
+	case "above":
+		return value > bounds[0], nil
+	case "withinrange":
+		return value > bounds[0] && value < bounds[1], nil
+	default: // outsiderange
+		return value < bounds[0] || value > bounds[1], nil
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActionRecord is the execution record of a scheduled action.
+type ActionRecord struct {
+	// Name is the name of the action.
+	Name string `json:"name"`
+
+	// ActionType is the type of the action.
+	ActionType ActionType `json:"action"`
+
+	// ScheduledAt is the time the action was scheduled.
+	ScheduledAt metav1.Time `json:"scheduledAt"`
+
+	// RunningAt is the time the action was first observed as Running.
+	// +optional
+	RunningAt *metav1.Time `json:"runningAt,omitempty"`
+
+	// CompletedAt is the time the action was first observed as Success or Failed.
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Phase is the latest phase of the action, as reported by the classifier.
+	// Once the action is completed, the phase is final.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Reason is the reason of the latest phase, as reported by the job of the action.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Children is the number of jobs created by the action, e.g, the services of a cluster,
+	// the invocations of a call, or the actions of an included scenario.
+	// +optional
+	Children int `json:"children,omitempty"`
+}
+
+// ActionTimeline is the list of execution records, in the order the actions were scheduled.
+type ActionTimeline []ActionRecord
+
+// Get returns the record of the given action, or nil if the action has not been scheduled.
+func (in ActionTimeline) Get(actionName string) *ActionRecord {
+	for i := range in {
+		if in[i].Name == actionName {
+			return &in[i]
+		}
+	}
+
+	return nil
+}
+
+// Table returns a tabular form of the timeline for pretty printing.
+// Times are relative to the scheduling of the first action.
+func (in ActionTimeline) Table() (header []string, data [][]string) {
+	return in.TableAt(time.Now())
+}
+
+// TableAt is like Table, but the duration of incomplete actions is measured until the given time.
+func (in ActionTimeline) TableAt(now time.Time) (header []string, data [][]string) {
+	header = []string{
+		"Action",
+		"Type",
+		"Phase",
+		"Reason",
+		"Children",
+		"Scheduled",
+		"Running",
+		"Completed",
+		"Duration",
+	}
+
+	if len(in) == 0 {
+		return header, data
+	}
+
+	start := in[0].ScheduledAt.Time
+
+	offset := func(t *metav1.Time) string {
+		if t == nil {
+			return "-"
+		}
+
+		return "+" + t.Sub(start).Round(time.Second).String()
+	}
+
+	for i, record := range in {
+		// the duration of incomplete actions is measured until now.
+		duration := "-"
+
+		if record.RunningAt != nil {
+			end := now
+			if record.CompletedAt != nil {
+				end = record.CompletedAt.Time
+			}
+
+			duration = end.Sub(record.RunningAt.Time).Round(time.Second).String()
+		}
+
+		data = append(data, []string{
+			record.Name,
+			string(record.ActionType),
+			record.Phase.String(),
+			record.Reason,
+			fmt.Sprint(record.Children),
+			offset(&in[i].ScheduledAt),
+			offset(record.RunningAt),
+			offset(record.CompletedAt),
+			duration,
+		})
+	}
+
+	return header, data
+}
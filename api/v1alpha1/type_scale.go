@@ -0,0 +1,93 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ScaleOrder defines which services of a cluster are removed first, once the cluster is scaled in.
+type ScaleOrder string
+
+const (
+	// ScaleNewest removes the most recently created services first.
+	ScaleNewest ScaleOrder = "Newest"
+	// ScaleOldest removes the least recently created services first.
+	ScaleOldest ScaleOrder = "Oldest"
+	// ScaleRandom removes services in random order.
+	ScaleRandom ScaleOrder = "Random"
+)
+
+// ScaleSpec grows, or shrinks, a running cluster.
+type ScaleSpec struct {
+	// Cluster is the name of the Cluster action to be scaled.
+	Cluster string `json:"cluster"`
+
+	// By is the number of services to add (positive), or remove (negative). It can also be a percentage of the
+	// current instances of the cluster, e.g, "50%" or "-25%". Percentages are rounded up.
+	By intstr.IntOrString `json:"by"`
+
+	// Order defines which services are removed first, when the cluster shrinks. Defaults to Newest.
+	// +kubebuilder:validation:Enum=Newest;Oldest;Random
+	// +optional
+	Order ScaleOrder `json:"order,omitempty"`
+}
+
+// Instances returns the instances of a cluster with the given current instances, once it is scaled.
+// A cluster cannot be scaled to less than one instance.
+func (in *ScaleSpec) Instances(current int) (int, error) {
+	var delta int
+
+	switch in.By.Type {
+	case intstr.Int:
+		delta = in.By.IntValue()
+
+	case intstr.String:
+		if !strings.HasSuffix(in.By.StrVal, "%") {
+			return 0, errors.Errorf("invalid value '%s'. Expected a number, or a percentage", in.By.StrVal)
+		}
+
+		percent, err := strconv.Atoi(strings.TrimSuffix(in.By.StrVal, "%"))
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid percentage '%s'", in.By.StrVal)
+		}
+
+		// round up the magnitude, so that a non-zero percentage always has an effect.
+		magnitude := int(math.Ceil(math.Abs(float64(current*percent)) / 100))
+
+		if percent < 0 {
+			delta = -magnitude
+		} else {
+			delta = magnitude
+		}
+	}
+
+	if delta == 0 {
+		return 0, errors.Errorf("scaling by '%s' has no effect", in.By.String())
+	}
+
+	if current+delta < 1 {
+		return 0, errors.Errorf("cannot scale '%d' instances by '%s'", current, in.By.String())
+	}
+
+	return current + delta, nil
+}
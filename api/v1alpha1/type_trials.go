@@ -0,0 +1,305 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RepeatSpec defines the repetition of the scenario across multiple trials.
+type RepeatSpec struct {
+	// Trials is the number of times that the actions of the scenario are executed. The trials run sequentially,
+	// and every trial starts from a clean state, once the jobs of the previous trial are removed.
+	// For trials that run in parallel, each in a fresh namespace, use the repetitions of a Sweep.
+	// +kubebuilder:validation:Minimum=1
+	Trials int `json:"trials"`
+
+	// Cooldown is the idle period between the completion of a trial and the beginning of the next one.
+	// +optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+
+	// Metrics are the values that are collected at the end of every trial, and are aggregated across the trials.
+	// +optional
+	Metrics []TrialMetric `json:"metrics,omitempty"`
+}
+
+// TrialMetric is a named value that is collected at the end of every trial.
+type TrialMetric struct {
+	// Name is the identifier of the metric in the summary.
+	Name string `json:"name"`
+
+	// Value reduces the values of a Grafana query, over the duration of the trial, into a single number.
+	// Example: 'avg() of query(wpFnYRwGk/2/bitrate)'
+	Value ExprValue `json:"value"`
+}
+
+// TrialResult is the outcome of a single trial.
+type TrialResult struct {
+	// Trial is the index of the trial, starting from 1.
+	Trial int `json:"trial"`
+
+	// Phase is the verdict of the trial.
+	Phase Phase `json:"phase"`
+
+	// Reason is the reason of the verdict.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message explains the verdict, and any error in the collection of the metrics.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time that the trial has started.
+	StartTime metav1.Time `json:"startTime"`
+
+	// EndTime is the time that the trial has reached a terminal phase.
+	EndTime metav1.Time `json:"endTime"`
+
+	// AssertionsPassed is false if an assertion of the trial has failed.
+	AssertionsPassed bool `json:"assertionsPassed"`
+
+	// Values are the collected metrics, indexed by their name. Metrics that could not be collected are omitted.
+	// +optional
+	Values map[string]float64 `json:"values,omitempty"`
+}
+
+// MetricSummary aggregates the values of a metric across the trials.
+type MetricSummary struct {
+	// Name is the identifier of the metric.
+	Name string `json:"name"`
+
+	// Samples is the number of trials that reported a value for the metric.
+	Samples int `json:"samples"`
+
+	// Mean is the arithmetic mean of the values.
+	Mean float64 `json:"mean"`
+
+	// StdDev is the sample standard deviation of the values.
+	StdDev float64 `json:"stddev"`
+
+	// LowerBound is the lower bound of the 95% confidence interval of the mean.
+	LowerBound float64 `json:"lowerBound"`
+
+	// UpperBound is the upper bound of the 95% confidence interval of the mean.
+	UpperBound float64 `json:"upperBound"`
+}
+
+// TrialsSummary aggregates the outcome of the completed trials.
+type TrialsSummary struct {
+	// CompletedTrials is the number of trials that have reached a terminal phase.
+	CompletedTrials int `json:"completedTrials"`
+
+	// SucceededTrials is the number of trials that have succeeded.
+	SucceededTrials int `json:"succeededTrials"`
+
+	// AssertionPassRate is the fraction of the completed trials whose assertions have passed.
+	AssertionPassRate float64 `json:"assertionPassRate"`
+
+	// Metrics are the aggregated values of the trial metrics.
+	// +optional
+	Metrics []MetricSummary `json:"metrics,omitempty"`
+}
+
+// TrialsStatus defines the observed state of the trials.
+type TrialsStatus struct {
+	// Current is the index of the running trial, starting from 1.
+	Current int `json:"current"`
+
+	// StartTime is the time that the running trial has started.
+	StartTime metav1.Time `json:"startTime"`
+
+	// Results are the outcomes of the completed trials.
+	// +optional
+	Results []TrialResult `json:"results,omitempty"`
+
+	// Summary aggregates the results of the completed trials.
+	// +optional
+	Summary *TrialsSummary `json:"summary,omitempty"`
+}
+
+// Summarize aggregates the results of the completed trials.
+func (in *TrialsStatus) Summarize(metrics []TrialMetric) {
+	summary := TrialsSummary{
+		CompletedTrials: len(in.Results),
+	}
+
+	passed := 0
+
+	for _, result := range in.Results {
+		if result.Phase.Is(PhaseSuccess) {
+			summary.SucceededTrials++
+		}
+
+		if result.AssertionsPassed {
+			passed++
+		}
+	}
+
+	if summary.CompletedTrials > 0 {
+		summary.AssertionPassRate = float64(passed) / float64(summary.CompletedTrials)
+	}
+
+	for _, metric := range metrics {
+		var samples []float64
+
+		for _, result := range in.Results {
+			if value, exists := result.Values[metric.Name]; exists {
+				samples = append(samples, value)
+			}
+		}
+
+		summary.Metrics = append(summary.Metrics, summarizeSamples(metric.Name, samples))
+	}
+
+	in.Summary = &summary
+}
+
+// summarizeSamples computes the mean, the sample standard deviation, and the 95% confidence interval of the mean,
+// using the Student's t-distribution. A single sample yields a zero-width interval.
+func summarizeSamples(name string, samples []float64) MetricSummary {
+	summary := MetricSummary{
+		Name:    name,
+		Samples: len(samples),
+	}
+
+	if len(samples) == 0 {
+		return summary
+	}
+
+	var sum float64
+
+	for _, sample := range samples {
+		sum += sample
+	}
+
+	summary.Mean = sum / float64(len(samples))
+
+	if len(samples) > 1 {
+		var squares float64
+
+		for _, sample := range samples {
+			squares += (sample - summary.Mean) * (sample - summary.Mean)
+		}
+
+		summary.StdDev = math.Sqrt(squares / float64(len(samples)-1))
+	}
+
+	margin := studentT95(len(samples)-1) * summary.StdDev / math.Sqrt(float64(len(samples)))
+
+	summary.LowerBound = summary.Mean - margin
+	summary.UpperBound = summary.Mean + margin
+
+	return summary
+}
+
+// studentT95 returns the two-sided 97.5th percentile of the Student's t-distribution for the given degrees of freedom.
+// Beyond 30 degrees of freedom, the normal approximation is used.
+func studentT95(df int) float64 {
+	table := []float64{
+		0, 12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+		2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+		2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+	}
+
+	switch {
+	case df <= 0:
+		return 0
+	case df < len(table):
+		return table[df]
+	default:
+		return 1.960
+	}
+}
+
+// Table returns a tabular form of the trials for pretty printing.
+func (in *TrialsStatus) Table() (header []string, data [][]string) {
+	header = []string{
+		"Trial",
+		"Phase",
+		"Reason",
+		"Duration",
+		"Assertions",
+		"Values",
+	}
+
+	for _, result := range in.Results {
+		assertions := "Passed"
+		if !result.AssertionsPassed {
+			assertions = "Failed"
+		}
+
+		names := make([]string, 0, len(result.Values))
+		for name := range result.Values {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = fmt.Sprintf("%s=%.4g", name, result.Values[name])
+		}
+
+		data = append(data, []string{
+			fmt.Sprint(result.Trial),
+			result.Phase.String(),
+			result.Reason,
+			result.EndTime.Sub(result.StartTime.Time).Round(time.Second).String(),
+			assertions,
+			strings.Join(values, ","),
+		})
+	}
+
+	return header, data
+}
+
+// Table returns a tabular form of the summary for pretty printing.
+func (in *TrialsSummary) Table() (header []string, data [][]string) {
+	header = []string{
+		"Metric",
+		"Samples",
+		"Mean",
+		"StdDev",
+		"95% CI",
+	}
+
+	data = append(data, []string{
+		"(assertion pass rate)",
+		fmt.Sprint(in.CompletedTrials),
+		fmt.Sprintf("%.4g", in.AssertionPassRate),
+		"",
+		"",
+	})
+
+	for _, metric := range in.Metrics {
+		data = append(data, []string{
+			metric.Name,
+			fmt.Sprint(metric.Samples),
+			fmt.Sprintf("%.4g", metric.Mean),
+			fmt.Sprintf("%.4g", metric.StdDev),
+			fmt.Sprintf("[%.4g, %.4g]", metric.LowerBound, metric.UpperBound),
+		})
+	}
+
+	return header, data
+}
@@ -40,8 +40,23 @@ func (in *Action) DeepCopyInto(out *Action) {
 	if in.Assert != nil {
 		in, out := &in.Assert, &out.Assert
 		*out = new(ConditionalExpr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveDeadline != nil {
+		in, out := &in.ActiveDeadline, &out.ActiveDeadline
+		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(ConditionalExpr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Else != nil {
+		in, out := &in.Else, &out.Else
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.EmbedActions != nil {
 		in, out := &in.EmbedActions, &out.EmbedActions
 		*out = new(EmbedActions)
@@ -59,6 +74,103 @@ func (in *Action) DeepCopy() *Action {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionRecord) DeepCopyInto(out *ActionRecord) {
+	*out = *in
+	in.ScheduledAt.DeepCopyInto(&out.ScheduledAt)
+	if in.RunningAt != nil {
+		in, out := &in.RunningAt, &out.RunningAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionRecord.
+func (in *ActionRecord) DeepCopy() *ActionRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ActionTimeline) DeepCopyInto(out *ActionTimeline) {
+	{
+		in := &in
+		*out = make(ActionTimeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionTimeline.
+func (in ActionTimeline) DeepCopy() ActionTimeline {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionTimeline)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionTransitions) DeepCopyInto(out *ActionTransitions) {
+	*out = *in
+	if in.Running != nil {
+		in, out := &in.Running, &out.Running
+		*out = (*in).DeepCopy()
+	}
+	if in.Success != nil {
+		in, out := &in.Success, &out.Success
+		*out = (*in).DeepCopy()
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionTransitions.
+func (in *ActionTransitions) DeepCopy() *ActionTransitions {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionTransitions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BarrierSpec) DeepCopyInto(out *BarrierSpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Until != nil {
+		in, out := &in.Until, &out.Until
+		*out = new(ConditionalExpr)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BarrierSpec.
+func (in *BarrierSpec) DeepCopy() *BarrierSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BarrierSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Call) DeepCopyInto(out *Call) {
 	*out = *in
@@ -138,6 +250,11 @@ func (in *CallSpec) DeepCopyInto(out *CallSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Capture != nil {
+		in, out := &in.Capture, &out.Capture
+		*out = make([]CaptureSpec, len(*in))
+		copy(*out, *in)
+	}
 	if in.Suspend != nil {
 		in, out := &in.Suspend, &out.Suspend
 		*out = new(bool)
@@ -146,7 +263,7 @@ func (in *CallSpec) DeepCopyInto(out *CallSpec) {
 	if in.SuspendWhen != nil {
 		in, out := &in.SuspendWhen, &out.SuspendWhen
 		*out = new(ConditionalExpr)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Tolerate != nil {
 		in, out := &in.Tolerate, &out.Tolerate
@@ -184,6 +301,13 @@ func (in *CallStatus) DeepCopyInto(out *CallStatus) {
 		}
 	}
 	in.LastScheduleTime.DeepCopyInto(&out.LastScheduleTime)
+	if in.Captured != nil {
+		in, out := &in.Captured, &out.Captured
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CallStatus.
@@ -216,6 +340,21 @@ func (in *Callable) DeepCopy() *Callable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CaptureSpec) DeepCopyInto(out *CaptureSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CaptureSpec.
+func (in *CaptureSpec) DeepCopy() *CaptureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CaptureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cascade) DeepCopyInto(out *Cascade) {
 	*out = *in
@@ -292,7 +431,7 @@ func (in *CascadeSpec) DeepCopyInto(out *CascadeSpec) {
 	if in.SuspendWhen != nil {
 		in, out := &in.SuspendWhen, &out.SuspendWhen
 		*out = new(ConditionalExpr)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -525,7 +664,7 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	if in.SuspendWhen != nil {
 		in, out := &in.SuspendWhen, &out.SuspendWhen
 		*out = new(ConditionalExpr)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Tolerate != nil {
 		in, out := &in.Tolerate, &out.Tolerate
@@ -568,6 +707,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		}
 	}
 	in.LastScheduleTime.DeepCopyInto(&out.LastScheduleTime)
+	if in.RemovedJobs != nil {
+		in, out := &in.RemovedJobs, &out.RemovedJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -583,6 +727,25 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConditionalExpr) DeepCopyInto(out *ConditionalExpr) {
 	*out = *in
+	if in.AllOf != nil {
+		in, out := &in.AllOf, &out.AllOf
+		*out = make([]ConditionalExpr, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnyOf != nil {
+		in, out := &in.AnyOf, &out.AnyOf
+		*out = make([]ConditionalExpr, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Not != nil {
+		in, out := &in.Not, &out.Not
+		*out = new(ConditionalExpr)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionalExpr.
@@ -742,6 +905,31 @@ func (in *EmbedActions) DeepCopyInto(out *EmbedActions) {
 		*out = new(CallSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Scenario != nil {
+		in, out := &in.Scenario, &out.Scenario
+		*out = new(IncludeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Wait != nil {
+		in, out := &in.Wait, &out.Wait
+		*out = new(BarrierSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scale != nil {
+		in, out := &in.Scale, &out.Scale
+		*out = new(ScaleSpec)
+		**out = **in
+	}
+	if in.Apply != nil {
+		in, out := &in.Apply, &out.Apply
+		*out = new(GenerateObjectFromTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Helm != nil {
+		in, out := &in.Helm, &out.Helm
+		*out = new(HelmSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbedActions.
@@ -767,6 +955,16 @@ func (in *EmbedSpecs) DeepCopyInto(out *EmbedSpecs) {
 		*out = new(ChaosSpec)
 		**out = **in
 	}
+	if in.Scenario != nil {
+		in, out := &in.Scenario, &out.Scenario
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = new(ManifestSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbedSpecs.
@@ -779,6 +977,32 @@ func (in *EmbedSpecs) DeepCopy() *EmbedSpecs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinallyStatus) DeepCopyInto(out *FinallyStatus) {
+	*out = *in
+	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
+	if in.ScheduledJobs != nil {
+		in, out := &in.ScheduledJobs, &out.ScheduledJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkippedJobs != nil {
+		in, out := &in.SkippedJobs, &out.SkippedJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinallyStatus.
+func (in *FinallyStatus) DeepCopy() *FinallyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FinallyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GenerateObjectFromTemplate) DeepCopyInto(out *GenerateObjectFromTemplate) {
 	*out = *in
@@ -815,6 +1039,71 @@ func (in *GenerateObjectFromTemplate) DeepCopy() *GenerateObjectFromTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmSpec) DeepCopyInto(out *HelmSpec) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(Parameters, len(*in))
+		for key, val := range *in {
+			var outVal *apiextensionsv1.JSON
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(apiextensionsv1.JSON)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmSpec.
+func (in *HelmSpec) DeepCopy() *HelmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncludeSpec) DeepCopyInto(out *IncludeSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(Parameters, len(*in))
+		for key, val := range *in {
+			var outVal *apiextensionsv1.JSON
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(apiextensionsv1.JSON)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncludeSpec.
+func (in *IncludeSpec) DeepCopy() *IncludeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IncludeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Lifecycle) DeepCopyInto(out *Lifecycle) {
 	*out = *in
@@ -837,6 +1126,21 @@ func (in *Lifecycle) DeepCopy() *Lifecycle {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestSpec) DeepCopyInto(out *ManifestSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestSpec.
+func (in *ManifestSpec) DeepCopy() *ManifestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchBy) DeepCopyInto(out *MatchBy) {
 	*out = *in
@@ -899,6 +1203,21 @@ func (in *MatchOutputs) DeepCopy() *MatchOutputs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricSummary) DeepCopyInto(out *MetricSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricSummary.
+func (in *MetricSummary) DeepCopy() *MetricSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Parameters) DeepCopyInto(out *Parameters) {
 	{
@@ -954,44 +1273,104 @@ func (in *PlacementSpec) DeepCopy() *PlacementSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in ResourceDistribution) DeepCopyInto(out *ResourceDistribution) {
-	{
-		in := &in
-		*out = make(ResourceDistribution, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = make(corev1.ResourceList, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val.DeepCopy()
-				}
-			}
-		}
-	}
+func (in *PostCondition) DeepCopyInto(out *PostCondition) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistribution.
-func (in ResourceDistribution) DeepCopy() ResourceDistribution {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostCondition.
+func (in *PostCondition) DeepCopy() *PostCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceDistribution)
+	out := new(PostCondition)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceDistributionSpec) DeepCopyInto(out *ResourceDistributionSpec) {
+func (in *PostConditionResult) DeepCopyInto(out *PostConditionResult) {
 	*out = *in
-	if in.TotalResources != nil {
-		in, out := &in.TotalResources, &out.TotalResources
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(float64)
+		**out = **in
 	}
-	if in.DistributionSpec != nil {
-		in, out := &in.DistributionSpec, &out.DistributionSpec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostConditionResult.
+func (in *PostConditionResult) DeepCopy() *PostConditionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(PostConditionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepeatSpec) DeepCopyInto(out *RepeatSpec) {
+	*out = *in
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]TrialMetric, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepeatSpec.
+func (in *RepeatSpec) DeepCopy() *RepeatSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepeatSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ResourceDistribution) DeepCopyInto(out *ResourceDistribution) {
+	{
+		in := &in
+		*out = make(ResourceDistribution, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(corev1.ResourceList, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistribution.
+func (in ResourceDistribution) DeepCopy() ResourceDistribution {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDistribution)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDistributionSpec) DeepCopyInto(out *ResourceDistributionSpec) {
+	*out = *in
+	if in.TotalResources != nil {
+		in, out := &in.TotalResources, &out.TotalResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.DistributionSpec != nil {
+		in, out := &in.DistributionSpec, &out.DistributionSpec
 		*out = new(DistributionSpec)
 		(*in).DeepCopyInto(*out)
 	}
@@ -1007,6 +1386,22 @@ func (in *ResourceDistributionSpec) DeepCopy() *ResourceDistributionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleSpec) DeepCopyInto(out *ScaleSpec) {
+	*out = *in
+	out.By = in.By
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleSpec.
+func (in *ScaleSpec) DeepCopy() *ScaleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Scenario) DeepCopyInto(out *Scenario) {
 	*out = *in
@@ -1034,6 +1429,36 @@ func (in *Scenario) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScenarioInputs) DeepCopyInto(out *ScenarioInputs) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(Parameters, len(*in))
+		for key, val := range *in {
+			var outVal *apiextensionsv1.JSON
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(apiextensionsv1.JSON)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioInputs.
+func (in *ScenarioInputs) DeepCopy() *ScenarioInputs {
+	if in == nil {
+		return nil
+	}
+	out := new(ScenarioInputs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScenarioList) DeepCopyInto(out *ScenarioList) {
 	*out = *in
@@ -1074,6 +1499,11 @@ func (in *ScenarioSpec) DeepCopyInto(out *ScenarioSpec) {
 		*out = new(TestdataVolume)
 		**out = **in
 	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = new(ScenarioInputs)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Actions != nil {
 		in, out := &in.Actions, &out.Actions
 		*out = make([]Action, len(*in))
@@ -1081,6 +1511,38 @@ func (in *ScenarioSpec) DeepCopyInto(out *ScenarioSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Finally != nil {
+		in, out := &in.Finally, &out.Finally
+		*out = make([]Action, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveDeadline != nil {
+		in, out := &in.ActiveDeadline, &out.ActiveDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SuccessWhen != nil {
+		in, out := &in.SuccessWhen, &out.SuccessWhen
+		*out = new(ConditionalExpr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailWhen != nil {
+		in, out := &in.FailWhen, &out.FailWhen
+		*out = new(ConditionalExpr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostConditions != nil {
+		in, out := &in.PostConditions, &out.PostConditions
+		*out = make([]PostCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Repeat != nil {
+		in, out := &in.Repeat, &out.Repeat
+		*out = new(RepeatSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Suspend != nil {
 		in, out := &in.Suspend, &out.Suspend
 		*out = new(bool)
@@ -1107,6 +1569,69 @@ func (in *ScenarioStatus) DeepCopyInto(out *ScenarioStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SkippedJobs != nil {
+		in, out := &in.SkippedJobs, &out.SkippedJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Transitions != nil {
+		in, out := &in.Transitions, &out.Transitions
+		*out = make(map[string]ActionTransitions, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Timeline != nil {
+		in, out := &in.Timeline, &out.Timeline
+		*out = make(ActionTimeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Verdict != nil {
+		in, out := &in.Verdict, &out.Verdict
+		*out = new(VerdictStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostConditions != nil {
+		in, out := &in.PostConditions, &out.PostConditions
+		*out = make([]PostConditionResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Includes != nil {
+		in, out := &in.Includes, &out.Includes
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Finally != nil {
+		in, out := &in.Finally, &out.Finally
+		*out = new(FinallyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trials != nil {
+		in, out := &in.Trials, &out.Trials
+		*out = new(TrialsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioStatus.
@@ -1258,6 +1783,173 @@ func (in *SetField) DeepCopy() *SetField {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sweep) DeepCopyInto(out *Sweep) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sweep.
+func (in *Sweep) DeepCopy() *Sweep {
+	if in == nil {
+		return nil
+	}
+	out := new(Sweep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Sweep) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SweepAxis) DeepCopyInto(out *SweepAxis) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SweepAxis.
+func (in *SweepAxis) DeepCopy() *SweepAxis {
+	if in == nil {
+		return nil
+	}
+	out := new(SweepAxis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SweepList) DeepCopyInto(out *SweepList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Sweep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SweepList.
+func (in *SweepList) DeepCopy() *SweepList {
+	if in == nil {
+		return nil
+	}
+	out := new(SweepList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SweepList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SweepRun) DeepCopyInto(out *SweepRun) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(Parameters, len(*in))
+		for key, val := range *in {
+			var outVal *apiextensionsv1.JSON
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(apiextensionsv1.JSON)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SweepRun.
+func (in *SweepRun) DeepCopy() *SweepRun {
+	if in == nil {
+		return nil
+	}
+	out := new(SweepRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SweepSpec) DeepCopyInto(out *SweepSpec) {
+	*out = *in
+	in.Scenario.DeepCopyInto(&out.Scenario)
+	if in.Axes != nil {
+		in, out := &in.Axes, &out.Axes
+		*out = make([]SweepAxis, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Tolerate != nil {
+		in, out := &in.Tolerate, &out.Tolerate
+		*out = new(TolerateSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SweepSpec.
+func (in *SweepSpec) DeepCopy() *SweepSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SweepSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SweepStatus) DeepCopyInto(out *SweepStatus) {
+	*out = *in
+	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
+	if in.Runs != nil {
+		in, out := &in.Runs, &out.Runs
+		*out = make([]SweepRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SweepStatus.
+func (in *SweepStatus) DeepCopy() *SweepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SweepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskSchedulerSpec) DeepCopyInto(out *TaskSchedulerSpec) {
 	*out = *in
@@ -1284,7 +1976,7 @@ func (in *TaskSchedulerSpec) DeepCopyInto(out *TaskSchedulerSpec) {
 	if in.Event != nil {
 		in, out := &in.Event, &out.Event
 		*out = new(ConditionalExpr)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -1505,6 +2197,125 @@ func (in *TolerateSpec) DeepCopy() *TolerateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransitionRef) DeepCopyInto(out *TransitionRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitionRef.
+func (in *TransitionRef) DeepCopy() *TransitionRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TransitionRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrialMetric) DeepCopyInto(out *TrialMetric) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrialMetric.
+func (in *TrialMetric) DeepCopy() *TrialMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(TrialMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrialResult) DeepCopyInto(out *TrialResult) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrialResult.
+func (in *TrialResult) DeepCopy() *TrialResult {
+	if in == nil {
+		return nil
+	}
+	out := new(TrialResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrialsStatus) DeepCopyInto(out *TrialsStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]TrialResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(TrialsSummary)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrialsStatus.
+func (in *TrialsStatus) DeepCopy() *TrialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrialsSummary) DeepCopyInto(out *TrialsSummary) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]MetricSummary, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrialsSummary.
+func (in *TrialsSummary) DeepCopy() *TrialsSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(TrialsSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerdictStatus) DeepCopyInto(out *VerdictStatus) {
+	*out = *in
+	in.Expr.DeepCopyInto(&out.Expr)
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerdictStatus.
+func (in *VerdictStatus) DeepCopy() *VerdictStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VerdictStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualObject) DeepCopyInto(out *VirtualObject) {
 	*out = *in
@@ -1615,11 +2426,26 @@ func (in *WaitSpec) DeepCopyInto(out *WaitSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Completed != nil {
+		in, out := &in.Completed, &out.Completed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.After != nil {
 		in, out := &in.After, &out.After
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = new(TransitionRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitSpec.
@@ -0,0 +1,290 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/pkg/errors"
+)
+
+const (
+	GraphDOT     = "dot"
+	GraphMermaid = "mermaid"
+)
+
+// PhaseSkipped marks the actions that were skipped by the scenario. It is used only for rendering.
+const PhaseSkipped = v1alpha1.Phase("Skipped")
+
+// phaseColors are the fill colors of the graph nodes, by phase.
+var phaseColors = map[v1alpha1.Phase]string{
+	v1alpha1.PhasePending: "#fff2a8",
+	v1alpha1.PhaseRunning: "#a8d1ff",
+	v1alpha1.PhaseSuccess: "#b5e8b0",
+	v1alpha1.PhaseFailed:  "#ffb3b3",
+	PhaseSkipped:          "#e0e0e0",
+}
+
+type graphNode struct {
+	Name  string
+	Label []string
+	Phase v1alpha1.Phase
+}
+
+type graphEdge struct {
+	From, To string
+	Label    string
+
+	// Dashed edges do not constrain the scheduling (e.g, the targets of an action).
+	Dashed bool
+}
+
+// ActionPhases returns the phase of every action, as classified by the view of the live jobs (see Snapshot.View).
+// Skipped actions are marked as such. Actions that have not yet been scheduled are omitted.
+func ActionPhases(scenario *v1alpha1.Scenario, view *lifecycle.Classifier) map[string]v1alpha1.Phase {
+	phases := make(map[string]v1alpha1.Phase)
+
+	for _, action := range append(append([]v1alpha1.Action{}, scenario.Spec.Actions...), scenario.Spec.Finally...) {
+		switch {
+		case view.IsFailed(action.Name):
+			phases[action.Name] = v1alpha1.PhaseFailed
+		case view.IsSuccessful(action.Name):
+			phases[action.Name] = v1alpha1.PhaseSuccess
+		case view.IsRunning(action.Name):
+			phases[action.Name] = v1alpha1.PhaseRunning
+		case view.IsPending(action.Name):
+			phases[action.Name] = v1alpha1.PhasePending
+		}
+	}
+
+	for _, name := range scenario.Status.SkippedJobs {
+		phases[name] = PhaseSkipped
+	}
+
+	if finally := scenario.Status.Finally; finally != nil {
+		for _, name := range finally.SkippedJobs {
+			phases[name] = PhaseSkipped
+		}
+	}
+
+	return phases
+}
+
+// RenderGraph renders the dependency graph of the scenario in the given format (dot or mermaid).
+// Solid edges are dependencies, time anchors, and conditional branches. Dashed edges point to the
+// actions that are referenced by the spec of an action (e.g, Delete jobs, Call services, Chaos targets).
+// If phases are given, the nodes are colored by the phase of the respective action.
+func RenderGraph(scenario *v1alpha1.Scenario, phases map[string]v1alpha1.Phase, format string, w io.Writer) error {
+	nodes, edges := buildGraph(scenario, phases)
+
+	switch format {
+	case GraphDOT:
+		return renderDOT(scenario.GetName(), nodes, edges, w)
+	case GraphMermaid:
+		return renderMermaid(nodes, edges, w)
+	default:
+		return errors.Errorf("unknown graph format '%s'. Expected '%s' or '%s'", format, GraphDOT, GraphMermaid)
+	}
+}
+
+func buildGraph(scenario *v1alpha1.Scenario, phases map[string]v1alpha1.Phase) ([]graphNode, []graphEdge) {
+	actions := append(append([]v1alpha1.Action{}, scenario.Spec.Actions...), scenario.Spec.Finally...)
+
+	callIndex := make(map[string]*v1alpha1.Action, len(actions))
+	for i := range actions {
+		callIndex[actions[i].Name] = &actions[i]
+	}
+
+	var nodes []graphNode
+
+	var edges []graphEdge
+
+	for i, action := range actions {
+		node := graphNode{
+			Name:  action.Name,
+			Label: []string{action.Name, string(action.ActionType)},
+			Phase: phases[action.Name],
+		}
+
+		if i >= len(scenario.Spec.Actions) {
+			node.Label = append(node.Label, "finally")
+		}
+
+		if !action.When.IsZero() {
+			node.Label = append(node.Label, "when")
+		}
+
+		if deps := action.DependsOn; deps != nil {
+			for _, dep := range deps.Running {
+				edges = append(edges, graphEdge{From: dep, To: action.Name, Label: "running"})
+			}
+
+			for _, dep := range deps.Success {
+				edges = append(edges, graphEdge{From: dep, To: action.Name, Label: "success"})
+			}
+
+			for _, dep := range deps.Failed {
+				edges = append(edges, graphEdge{From: dep, To: action.Name, Label: "failed"})
+			}
+
+			for _, dep := range deps.Completed {
+				edges = append(edges, graphEdge{From: dep, To: action.Name, Label: "completed"})
+			}
+
+			if after := deps.After; after != nil {
+				if since := deps.Since; since != nil {
+					edges = append(edges, graphEdge{
+						From:  since.Action,
+						To:    action.Name,
+						Label: fmt.Sprintf("%s + %s", strings.ToLower(string(since.Phase)), after.Duration),
+					})
+				} else {
+					node.Label = append(node.Label, fmt.Sprintf("after %s", after.Duration))
+				}
+			}
+		}
+
+		for _, alt := range action.Else {
+			edges = append(edges, graphEdge{From: action.Name, To: alt, Label: "else"})
+		}
+
+		for _, target := range v1alpha1.ReferencedActions(&actions[i], callIndex) {
+			edges = append(edges, graphEdge{From: action.Name, To: target, Label: referenceLabel(action.ActionType), Dashed: true})
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	// drop edges to undefined actions. These are reported by the validation.
+	valid := edges[:0]
+
+	for _, edge := range edges {
+		if _, exists := callIndex[edge.From]; !exists {
+			continue
+		}
+
+		if _, exists := callIndex[edge.To]; !exists {
+			continue
+		}
+
+		valid = append(valid, edge)
+	}
+
+	return nodes, valid
+}
+
+// referenceLabel describes the relation between an action and the actions referenced by its spec.
+func referenceLabel(actionType v1alpha1.ActionType) string {
+	switch actionType {
+	case v1alpha1.ActionDelete:
+		return "deletes"
+	case v1alpha1.ActionCall:
+		return "calls"
+	case v1alpha1.ActionChaos, v1alpha1.ActionCascade:
+		return "targets"
+	case v1alpha1.ActionScale:
+		return "scales"
+	default:
+		return "refers"
+	}
+}
+
+func renderDOT(name string, nodes []graphNode, edges []graphEdge, w io.Writer) error {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "digraph %q {\n", name)
+	out.WriteString("  rankdir=LR;\n")
+	out.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];\n")
+
+	for _, node := range nodes {
+		attrs := fmt.Sprintf("label=%q", strings.Join(node.Label, "\n"))
+
+		if color, exists := phaseColors[node.Phase]; exists {
+			attrs += fmt.Sprintf(", fillcolor=%q, tooltip=%q", color, node.Phase)
+		}
+
+		fmt.Fprintf(&out, "  %q [%s];\n", node.Name, attrs)
+	}
+
+	for _, edge := range edges {
+		attrs := fmt.Sprintf("label=%q", edge.Label)
+
+		if edge.Dashed {
+			attrs += ", style=dashed"
+		}
+
+		fmt.Fprintf(&out, "  %q -> %q [%s];\n", edge.From, edge.To, attrs)
+	}
+
+	out.WriteString("}\n")
+
+	_, err := io.WriteString(w, out.String())
+
+	return err
+}
+
+func renderMermaid(nodes []graphNode, edges []graphEdge, w io.Writer) error {
+	var out strings.Builder
+
+	// action names may conflict with mermaid keywords (e.g, 'end'). Use positional identifiers instead.
+	ids := make(map[string]string, len(nodes))
+
+	out.WriteString("flowchart LR\n")
+
+	for i, node := range nodes {
+		ids[node.Name] = fmt.Sprintf("a%d", i)
+
+		fmt.Fprintf(&out, "  %s[\"%s\"]\n", ids[node.Name], strings.Join(node.Label, "<br/>"))
+	}
+
+	for _, edge := range edges {
+		arrow := "-->"
+		if edge.Dashed {
+			arrow = "-.->"
+		}
+
+		fmt.Fprintf(&out, "  %s %s|%s| %s\n", ids[edge.From], arrow, edge.Label, ids[edge.To])
+	}
+
+	// declare only the classes of the phases in use, in order of appearance.
+	var used []v1alpha1.Phase
+
+	declared := make(map[v1alpha1.Phase]bool)
+
+	for _, node := range nodes {
+		if _, exists := phaseColors[node.Phase]; exists {
+			fmt.Fprintf(&out, "  class %s %s\n", ids[node.Name], node.Phase)
+
+			if !declared[node.Phase] {
+				declared[node.Phase] = true
+				used = append(used, node.Phase)
+			}
+		}
+	}
+
+	for _, phase := range used {
+		fmt.Fprintf(&out, "  classDef %s fill:%s\n", phase, phaseColors[phase])
+	}
+
+	_, err := io.WriteString(w, out.String())
+
+	return err
+}
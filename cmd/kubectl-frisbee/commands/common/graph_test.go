@@ -0,0 +1,80 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+func TestActionPhases(t *testing.T) {
+	var scenario v1alpha1.Scenario
+
+	scenario.SetName("test")
+	scenario.Spec.Actions = []v1alpha1.Action{
+		{Name: "running", ActionType: v1alpha1.ActionService},
+		{Name: "completed", ActionType: v1alpha1.ActionService},
+		{Name: "skipped", ActionType: v1alpha1.ActionService},
+		{Name: "unscheduled", ActionType: v1alpha1.ActionService},
+	}
+	scenario.Spec.Finally = []v1alpha1.Action{
+		{Name: "cleanup", ActionType: v1alpha1.ActionService},
+		{Name: "report", ActionType: v1alpha1.ActionService},
+	}
+
+	// the timeline may lag behind the jobs, and must not be used for the phases.
+	scenario.Status.Timeline = v1alpha1.ActionTimeline{
+		{Name: "running", Phase: v1alpha1.PhasePending},
+		{Name: "completed", Phase: v1alpha1.PhaseRunning},
+	}
+	scenario.Status.SkippedJobs = []string{"skipped"}
+	scenario.Status.Finally = &v1alpha1.FinallyStatus{
+		Lifecycle:     v1alpha1.Lifecycle{Phase: v1alpha1.PhaseRunning},
+		ScheduledJobs: []string{"cleanup"},
+		SkippedJobs:   []string{"report"},
+	}
+
+	snapshot := Snapshot{Scenario: &scenario}
+
+	for name, phase := range map[string]v1alpha1.Phase{
+		"running":   v1alpha1.PhaseRunning,
+		"completed": v1alpha1.PhaseSuccess,
+		"cleanup":   v1alpha1.PhaseFailed,
+	} {
+		var job v1alpha1.Service
+
+		job.SetName(name)
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+		job.Status.Lifecycle.Phase = phase
+
+		snapshot.Jobs = append(snapshot.Jobs, &job)
+	}
+
+	want := map[string]v1alpha1.Phase{
+		"running":   v1alpha1.PhaseRunning,
+		"completed": v1alpha1.PhaseSuccess,
+		"skipped":   PhaseSkipped,
+		"cleanup":   v1alpha1.PhaseFailed,
+		"report":    PhaseSkipped,
+	}
+
+	if got := ActionPhases(&scenario, snapshot.View()); !reflect.DeepEqual(got, want) {
+		t.Errorf("ActionPhases() = %v, want %v", got, want)
+	}
+}
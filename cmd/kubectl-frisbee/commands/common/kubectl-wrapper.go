@@ -187,6 +187,7 @@ const (
 	Calls          = "calls.frisbee.dev"
 	VirtualObjects = "virtualobjects.frisbee.dev"
 	Templates      = "templates.frisbee.dev"
+	Sweeps         = "sweeps.frisbee.dev"
 )
 
 var FrisbeeResourceInspectionFields = strings.Join([]string{
@@ -624,7 +625,7 @@ func ForceDelete(testName string) error {
 	// VirtualObjects
 	// Templates
 
-	crdsWithFinalizers := []string{Services, Clusters, Chaos, Cascades, Calls, Scenarios}
+	crdsWithFinalizers := []string{Services, Clusters, Chaos, Cascades, Calls, Scenarios, Sweeps}
 
 	for _, crd := range crdsWithFinalizers {
 		resourceQuery := []string{"get", crd, "-o", "jsonpath='{.items[*].metadata.name}'"}
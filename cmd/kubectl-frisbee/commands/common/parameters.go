@@ -0,0 +1,160 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/json"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// OverrideParameters replaces the default values of the scenario parameters with the given key=value pairs,
+// and expands the references to the parameters. The modified documents are stored in a temporary file whose
+// path is returned. The caller is responsible for removing the file.
+func OverrideParameters(testName string, testFile string, overrides []string) (string, error) {
+	if len(overrides) == 0 {
+		return testFile, nil
+	}
+
+	values := make(map[string]string, len(overrides))
+
+	for _, override := range overrides {
+		key, value, found := strings.Cut(override, "=")
+		if !found || key == "" {
+			return "", errors.Errorf("invalid override '%s'. Expected key=value", override)
+		}
+
+		values[key] = value
+	}
+
+	in, err := os.Open(testFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot open test file")
+	}
+	defer in.Close()
+
+	var docs []map[string]interface{}
+
+	consumed := make(map[string]bool, len(values))
+
+	decoder := k8syaml.NewYAMLOrJSONDecoder(in, 4096)
+
+	for {
+		var doc map[string]interface{}
+
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return "", errors.Wrapf(err, "cannot decode test file")
+		}
+
+		if doc == nil {
+			continue
+		}
+
+		if doc["kind"] == "Scenario" {
+			if err := setParameters(doc, values, consumed); err != nil {
+				metadata, _ := doc["metadata"].(map[string]interface{})
+
+				return "", errors.Wrapf(err, "scenario '%v'", metadata["name"])
+			}
+
+			if spec, _ := doc["spec"].(map[string]interface{}); spec != nil {
+				if err := v1alpha1.ExpandParameters(spec); err != nil {
+					metadata, _ := doc["metadata"].(map[string]interface{})
+
+					return "", errors.Wrapf(err, "scenario '%v'", metadata["name"])
+				}
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	// All the overrides must be consumed by at least one scenario.
+	for key := range values {
+		if consumed[key] {
+			continue
+		}
+
+		return "", errors.Errorf("parameter '%s' is not defined by any scenario", key)
+	}
+
+	out, err := os.CreateTemp("", testName+"-*"+filepath.Ext(testFile))
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create test file")
+	}
+	defer out.Close()
+
+	encoder := yaml.NewEncoder(out)
+
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			os.Remove(out.Name())
+
+			return "", errors.Wrapf(err, "cannot encode test file")
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		os.Remove(out.Name())
+
+		return "", errors.Wrapf(err, "cannot store test file")
+	}
+
+	return out.Name(), nil
+}
+
+// setParameters overrides the parameters declared by the scenario, and marks the consumed values.
+// The new value retains the type of the default value.
+func setParameters(scenario map[string]interface{}, values map[string]string, consumed map[string]bool) error {
+	spec, _ := scenario["spec"].(map[string]interface{})
+	inputs, _ := spec["inputs"].(map[string]interface{})
+	parameters, _ := inputs["parameters"].(map[string]interface{})
+
+	for key, defaultValue := range parameters {
+		value, exists := values[key]
+		if !exists {
+			continue
+		}
+
+		if _, isString := defaultValue.(string); isString {
+			parameters[key] = value
+		} else {
+			var typed interface{}
+
+			if err := json.Unmarshal([]byte(value), &typed); err != nil {
+				return errors.Wrapf(err, "invalid value '%s' for parameter '%s'", value, key)
+			}
+
+			parameters[key] = typed
+		}
+
+		consumed[key] = true
+	}
+
+	return nil
+}
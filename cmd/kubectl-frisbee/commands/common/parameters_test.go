@@ -0,0 +1,77 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func TestOverrideParameters(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.yml")
+
+	scenario := `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: parameters
+spec:
+  inputs:
+    parameters:
+      clients: 4
+  actions:
+    - action: Cluster
+      name: clients
+      cluster:
+        templateRef: iperf.client
+        instances: "{{.inputs.parameters.clients}}"
+`
+
+	if err := os.WriteFile(testFile, []byte(scenario), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OverrideParameters("test", testFile, []string{"servers=2"}); err == nil {
+		t.Errorf("OverrideParameters() accepted a parameter that is not defined by any scenario")
+	}
+
+	overridden, err := OverrideParameters("test", testFile, []string{"clients=8"})
+	if err != nil {
+		t.Fatalf("OverrideParameters() error = %v", err)
+	}
+	defer os.Remove(overridden)
+
+	raw, err := os.ReadFile(overridden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expanded v1alpha1.Scenario
+
+	// the references are expanded, so that the scenario decodes into the typed fields.
+	if err := k8syaml.Unmarshal(raw, &expanded); err != nil {
+		t.Fatalf("cannot decode overridden scenario: %v", err)
+	}
+
+	if instances := expanded.Spec.Actions[0].Cluster.MaxInstances; instances != 8 {
+		t.Errorf("instances = %d, want 8", instances)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"io"
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// LoadScenarios returns the scenarios defined in the given file. Documents of other kinds are ignored.
+func LoadScenarios(testFile string) ([]v1alpha1.Scenario, error) {
+	var scenarios []v1alpha1.Scenario
+
+	err := loadDocuments(testFile, "Scenario", func(raw []byte) error {
+		var scenario v1alpha1.Scenario
+
+		if err := json.Unmarshal(raw, &scenario); err != nil {
+			return errors.Wrapf(err, "cannot decode scenario")
+		}
+
+		scenarios = append(scenarios, scenario)
+
+		return nil
+	})
+
+	return scenarios, err
+}
+
+// LoadTemplates returns the templates defined in the given file. Documents of other kinds are ignored.
+func LoadTemplates(file string) ([]v1alpha1.Template, error) {
+	var templates []v1alpha1.Template
+
+	err := loadDocuments(file, "Template", func(raw []byte) error {
+		var template v1alpha1.Template
+
+		if err := json.Unmarshal(raw, &template); err != nil {
+			return errors.Wrapf(err, "cannot decode template")
+		}
+
+		templates = append(templates, template)
+
+		return nil
+	})
+
+	return templates, err
+}
+
+// loadDocuments passes the documents of the given kind, encoded as JSON, to the callback.
+func loadDocuments(file string, kind string, callback func(raw []byte) error) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return errors.Wrapf(err, "cannot open test file")
+	}
+	defer in.Close()
+
+	decoder := k8syaml.NewYAMLOrJSONDecoder(in, 4096)
+
+	for {
+		var doc map[string]interface{}
+
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return errors.Wrapf(err, "cannot decode test file")
+		}
+
+		if doc == nil {
+			continue
+		}
+
+		// Lists (e.g, the output of 'kubectl get -o yaml') are flattened into their items.
+		items := []interface{}{doc}
+
+		if doc["kind"] == "List" {
+			items, _ = doc["items"].([]interface{})
+		}
+
+		for _, item := range items {
+			if obj, ok := item.(map[string]interface{}); !ok || obj["kind"] != kind {
+				continue
+			}
+
+			raw, err := json.Marshal(item)
+			if err != nil {
+				return errors.Wrapf(err, "cannot encode %s", kind)
+			}
+
+			if err := callback(raw); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ValidateDependencies analyzes the dependency graph of the scenarios defined in the given file, without
+// contacting the cluster. It reports the same errors as the admission webhook.
+func ValidateDependencies(testFile string) error {
+	scenarios, err := LoadScenarios(testFile)
+	if err != nil {
+		return err
+	}
+
+	for i := range scenarios {
+		scenario := &scenarios[i]
+
+		scenario.Default()
+
+		if _, err := v1alpha1.BuildDependencyGraph(scenario); err != nil {
+			return errors.Wrapf(err, "invalid scenario [%s]", scenario.GetName())
+		}
+	}
+
+	return nil
+}
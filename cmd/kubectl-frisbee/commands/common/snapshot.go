@@ -0,0 +1,182 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// SnapshotTimeAnnotation records the time that a snapshot is captured.
+const SnapshotTimeAnnotation = "snapshot.frisbee.dev/time"
+
+// snapshotKinds are the kinds of jobs that are classified by the scenario view.
+var snapshotKinds = map[string]func() client.Object{
+	"Service":       func() client.Object { return &v1alpha1.Service{} },
+	"Cluster":       func() client.Object { return &v1alpha1.Cluster{} },
+	"Chaos":         func() client.Object { return &v1alpha1.Chaos{} },
+	"Cascade":       func() client.Object { return &v1alpha1.Cascade{} },
+	"Call":          func() client.Object { return &v1alpha1.Call{} },
+	"VirtualObject": func() client.Object { return &v1alpha1.VirtualObject{} },
+}
+
+// Snapshot is the state of a test at a given time: the scenario, and the jobs it has created.
+type Snapshot struct {
+	// Time is the time that the snapshot is captured. The time in phase of the jobs is measured at that time.
+	Time time.Time
+
+	Scenario *v1alpha1.Scenario
+
+	Jobs []client.Object
+}
+
+// CaptureSnapshot returns the current state of the given test.
+func CaptureSnapshot(ctx context.Context, testName string) (*Snapshot, error) {
+	cli := env.Default.GetFrisbeeClient()
+
+	scenario, err := cli.GetScenario(ctx, testName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get test '%s'", testName)
+	}
+
+	if scenario == nil {
+		return nil, errors.Errorf("test '%s' was not found", testName)
+	}
+
+	jobs, err := cli.ListJobs(ctx, scenario)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get the jobs of test '%s'", testName)
+	}
+
+	return &Snapshot{
+		Time:     time.Now(),
+		Scenario: scenario,
+		Jobs:     jobs,
+	}, nil
+}
+
+// LoadSnapshot returns the snapshot stored in the given file. The file contains the scenario and its jobs,
+// as stored by Save, or as returned by 'kubectl get scenarios,services,clusters,chaos,cascades,calls -o yaml'.
+// If the time of the snapshot is not recorded, the current time is used.
+func LoadSnapshot(file string) (*Snapshot, error) {
+	scenarios, err := LoadScenarios(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scenarios) != 1 {
+		return nil, errors.Errorf("expected exactly one scenario in '%s'. Got %d", file, len(scenarios))
+	}
+
+	snapshot := Snapshot{
+		Time:     time.Now(),
+		Scenario: &scenarios[0],
+	}
+
+	if recorded, exists := snapshot.Scenario.GetAnnotations()[SnapshotTimeAnnotation]; exists {
+		snapshot.Time, err = time.Parse(time.RFC3339, recorded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid snapshot time")
+		}
+	}
+
+	for kind, newJob := range snapshotKinds {
+		err := loadDocuments(file, kind, func(raw []byte) error {
+			job := newJob()
+
+			if err := json.Unmarshal(raw, job); err != nil {
+				return errors.Wrapf(err, "cannot decode %s", kind)
+			}
+
+			// ignore the jobs of other scenarios, and the jobs that are created by other jobs (e.g, clusters).
+			if job.GetLabels()[v1alpha1.LabelCreatedBy] != snapshot.Scenario.GetName() {
+				return nil
+			}
+
+			snapshot.Jobs = append(snapshot.Jobs, job)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &snapshot, nil
+}
+
+// Save stores the snapshot in the given file, as a multi-document YAML.
+func (s *Snapshot) Save(file string) error {
+	scenario := s.Scenario.DeepCopy()
+
+	annotations := scenario.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[SnapshotTimeAnnotation] = s.Time.UTC().Format(time.RFC3339)
+	scenario.SetAnnotations(annotations)
+
+	var out bytes.Buffer
+
+	for _, obj := range append([]client.Object{scenario}, s.Jobs...) {
+		// objects of typed lists lack their kind.
+		obj.GetObjectKind().SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(reflect.TypeOf(obj).Elem().Name()))
+		obj.SetManagedFields(nil)
+
+		raw, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "cannot encode '%s'", obj.GetName())
+		}
+
+		out.WriteString("---\n")
+		out.Write(raw)
+	}
+
+	if err := os.WriteFile(file, out.Bytes(), 0o644); err != nil {
+		return errors.Wrapf(err, "cannot write snapshot to '%s'", file)
+	}
+
+	return nil
+}
+
+// View classifies the jobs of the snapshot, as the scenario controller does.
+func (s *Snapshot) View() *lifecycle.Classifier {
+	// time-aware functions are evaluated at the time of the snapshot.
+	view := lifecycle.NewClassifier(clocktesting.NewFakePassiveClock(s.Time))
+	view.Reset()
+
+	for _, job := range s.Jobs {
+		view.Classify(job.GetName(), job)
+	}
+
+	view.SetTransitions(s.Scenario.Status.Transitions)
+
+	return view
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSnapshot_SaveLoad(t *testing.T) {
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	runningSince := metav1.NewTime(start.Add(-2 * time.Minute))
+
+	var scenario v1alpha1.Scenario
+
+	scenario.SetName("test")
+	scenario.SetNamespace("default")
+	scenario.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+	scenario.Status.Transitions = map[string]v1alpha1.ActionTransitions{
+		"server": {Running: &runningSince},
+	}
+
+	createdBy := func(obj client.Object, owner string) {
+		obj.SetNamespace("default")
+		obj.SetLabels(map[string]string{v1alpha1.LabelCreatedBy: owner})
+	}
+
+	var server v1alpha1.Service
+
+	server.SetName("server")
+	createdBy(&server, "test")
+	v1alpha1.SetComponentLabel(&server.ObjectMeta, v1alpha1.ComponentSUT)
+	server.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+	server.Status.Restarts = 1
+
+	var clients v1alpha1.Cluster
+
+	clients.SetName("clients")
+	createdBy(&clients, "test")
+	v1alpha1.SetComponentLabel(&clients.ObjectMeta, v1alpha1.ComponentSUT)
+	clients.Status.Lifecycle.Phase = v1alpha1.PhaseSuccess
+
+	// the jobs of other scenarios are not loaded.
+	var foreign v1alpha1.Service
+
+	foreign.SetName("foreign")
+	createdBy(&foreign, "other")
+
+	saved := Snapshot{
+		Time:     start,
+		Scenario: &scenario,
+		Jobs:     []client.Object{&server, &clients, &foreign},
+	}
+
+	file := filepath.Join(t.TempDir(), "snapshot.yaml")
+
+	if err := saved.Save(file); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, exists := scenario.GetAnnotations()[SnapshotTimeAnnotation]; exists {
+		t.Error("Save() modified the scenario of the snapshot")
+	}
+
+	loaded, err := LoadSnapshot(file)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+
+	if !loaded.Time.Equal(start) {
+		t.Errorf("Time = %v, want %v", loaded.Time, start)
+	}
+
+	if loaded.Scenario.GetName() != "test" || loaded.Scenario.Status.Lifecycle.Phase != v1alpha1.PhaseRunning {
+		t.Errorf("Scenario = %s (%s), want test (%s)",
+			loaded.Scenario.GetName(), loaded.Scenario.Status.Lifecycle.Phase, v1alpha1.PhaseRunning)
+	}
+
+	kinds := make(map[string]string)
+
+	for _, job := range loaded.Jobs {
+		kinds[job.GetName()] = job.GetObjectKind().GroupVersionKind().Kind
+	}
+
+	expectedKinds := map[string]string{"server": "Service", "clients": "Cluster"}
+
+	if len(kinds) != len(expectedKinds) {
+		t.Fatalf("Jobs = %v, want %v", kinds, expectedKinds)
+	}
+
+	for name, kind := range expectedKinds {
+		if kinds[name] != kind {
+			t.Errorf("kind of '%s' = %s, want %s", name, kinds[name], kind)
+		}
+	}
+
+	// the view of the loaded snapshot is evaluated at the time of the snapshot.
+	view := loaded.View()
+
+	if !view.IsRunning("server") || !view.IsSuccessful("clients") {
+		t.Errorf("View() = %s, want server running and clients successful", view.ListAll())
+	}
+
+	if got := view.TimeInPhase("server"); got != 2*time.Minute {
+		t.Errorf("TimeInPhase() = %v, want %v", got, 2*time.Minute)
+	}
+
+	if got := view.NumRestarts("server"); got != 1 {
+		t.Errorf("NumRestarts() = %d, want %d", got, 1)
+	}
+}
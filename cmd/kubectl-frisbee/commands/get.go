@@ -18,6 +18,7 @@ package commands
 
 import (
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/sweeps"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/tests"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
 	"github.com/kubeshop/testkube/pkg/ui"
@@ -44,6 +45,7 @@ func NewGetCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(tests.NewGetTestsCmd())
+	cmd.AddCommand(sweeps.NewGetSweepsCmd())
 
 	return cmd
 }
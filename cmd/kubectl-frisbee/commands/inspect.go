@@ -30,7 +30,11 @@ func NewInspectCmd() *cobra.Command {
 		Aliases: []string{"show"},
 		Short:   "Inspect tests or test suites",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			env.Logo()
+			// keep the rendered graph clean for piping it to other tools.
+			if graph := cmd.Flags().Lookup("graph"); graph == nil || !graph.Changed {
+				env.Logo()
+			}
+
 			ui.SetVerbose(env.Default.Debug)
 
 			if !common.CRDsExist(common.Scenarios) {
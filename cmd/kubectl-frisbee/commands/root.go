@@ -45,10 +45,12 @@ func NewRootCmd() *cobra.Command {
 
 		// Test Management
 		NewValidateCmd(),
+		NewSimulateCmd(),
 		NewSubmitCmd(),
 		NewGetCmd(),
 		NewDeleteCmd(),
 		NewInspectCmd(),
+		NewEvalCmd(),
 
 		// Analysis Tools
 		NewSaveCmd(),
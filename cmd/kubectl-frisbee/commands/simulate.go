@@ -0,0 +1,131 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"os"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/carv-ics-forth/frisbee/pkg/simulator"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+type SimulateCmdOptions struct {
+	Mocks     string
+	Templates []string
+	Horizon   time.Duration
+}
+
+func PopulateSimulateFlags(cmd *cobra.Command, options *SimulateCmdOptions) {
+	cmd.Flags().StringVar(&options.Mocks, "mocks", "", "file with the simulated duration and outcome of the jobs")
+	cmd.Flags().StringArrayVar(&options.Templates, "templates", nil, "additional files with templates (e.g, the rendered system templates)")
+	cmd.Flags().DurationVar(&options.Horizon, "horizon", simulator.DefaultHorizon, "maximum virtual duration of the simulation")
+}
+
+func NewSimulateCmd() *cobra.Command {
+	var options SimulateCmdOptions
+
+	cmd := &cobra.Command{
+		Use:     "simulate <scenario.yaml>",
+		Aliases: []string{"dry-run"},
+		Short:   "Predict the timeline of a test, without a cluster",
+		Long: `Run the scenario, cluster, cascade, and call controllers against an in-memory cluster, with a virtual clock.
+Services and Chaos jobs are not deployed. Their duration and outcome are mocked, and default to success after 1m.
+Templates are loaded from the scenario file. The system templates can be rendered with 'helm template charts/system'
+and passed with --templates.`,
+		Example: `# Simulate a scenario with the default mocks
+  kubectl frisbee simulate my-wf.yaml
+
+  # Keep the servers running until they are deleted, and fail one of the clients
+  cat > mocks.yaml <<EOF
+  jobs:
+    - { match: "server*" }
+    - { match: "clients-2", duration: 30s, outcome: Failed }
+    - { match: "clients-*", duration: 2m }
+  calls:
+    - { match: "server", duration: 5s, stdout: "ok" }
+  EOF
+
+  kubectl frisbee simulate --mocks mocks.yaml my-wf.yaml`,
+		Args: cobra.ExactArgs(1),
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			ui.SetVerbose(env.Default.Debug)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			testFile := args[0]
+
+			var mocks simulator.Mocks
+
+			if options.Mocks != "" {
+				loaded, err := simulator.LoadMocks(options.Mocks)
+				ui.ExitOnError("Loading mocks", err)
+
+				mocks = loaded
+			}
+
+			scenarios, err := common.LoadScenarios(testFile)
+			ui.ExitOnError("Loading scenarios", err)
+
+			if len(scenarios) == 0 {
+				ui.Failf("No scenario found in '%s'", testFile)
+			}
+
+			templates, err := common.LoadTemplates(testFile)
+			ui.ExitOnError("Loading templates", err)
+
+			for _, file := range options.Templates {
+				extra, err := common.LoadTemplates(file)
+				ui.ExitOnError("Loading templates", err)
+
+				templates = append(templates, extra...)
+			}
+
+			for i := range scenarios {
+				result, err := simulator.Run(cmd.Context(), &scenarios[i], templates, simulator.Options{
+					Mocks:   mocks,
+					Horizon: options.Horizon,
+				})
+				ui.ExitOnError("Simulating scenario "+scenarios[i].GetName(), err)
+
+				ui.NL()
+				err = common.RenderList(&result.Scenario.Status, os.Stdout)
+				ui.ExitOnError("== Scenario Status ==", err)
+
+				ui.NL()
+				err = common.RenderList(result.Timeline(), os.Stdout)
+				ui.ExitOnError("== Action Timeline ==", err)
+
+				ui.NL()
+				err = common.RenderList(result.Events, os.Stdout)
+				ui.ExitOnError("== Events ==", err)
+
+				for _, warning := range result.Warnings {
+					ui.Warn(warning)
+				}
+
+				ui.Success("Simulated", scenarios[i].GetName(), "in", result.EndTime.Sub(result.StartTime).String())
+			}
+		},
+	}
+
+	PopulateSimulateFlags(cmd, &options)
+
+	return cmd
+}
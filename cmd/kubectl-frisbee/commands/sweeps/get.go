@@ -0,0 +1,85 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sweeps
+
+import (
+	"os"
+
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewGetSweepsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "sweep [testName]",
+		Aliases:           []string{"sweeps", "sw"},
+		Short:             "Get all available sweeps",
+		Long:              `Getting all available sweeps. If a test name is given, list the runs of the sweeps within the test`,
+		ValidArgsFunction: common.CompleteScenarios,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				ui.Failf("Pass at most one test name")
+			}
+
+			return nil
+		},
+
+		Run: func(cmd *cobra.Command, args []string) {
+			sweeps, err := env.Default.GetFrisbeeClient().ListSweeps(cmd.Context(), common.ManagedNamespace)
+			ui.ExitOnError("Getting all sweeps ", err)
+
+			if len(args) == 0 {
+				err = common.RenderList(&sweeps, os.Stdout)
+				ui.PrintOnError("Rendering list", err)
+
+				return
+			}
+
+			// Show the runs of the sweeps within the given test.
+			testName := args[0]
+
+			var found bool
+
+			for i := range sweeps.Items {
+				sweep := &sweeps.Items[i]
+
+				if sweep.GetNamespace() != testName {
+					continue
+				}
+
+				found = true
+
+				ui.NL()
+				err = common.RenderList(sweep, os.Stdout)
+				ui.ExitOnError("== Sweep Overview ==", err)
+
+				err = common.RenderList(&sweep.Status, os.Stdout)
+				ui.ExitOnError("== Sweep Runs ==", err)
+			}
+
+			if !found {
+				ui.Failf("No sweep in test '%s'", testName)
+			}
+
+			env.Default.Hint("To inspect a run use:", "kubectl frisbee inspect test <run>")
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
+	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func EvalTestCmdCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return common.CompleteScenarios(cmd, args, toComplete)
+
+	default:
+		return common.CompleteFlags(cmd, args, toComplete)
+	}
+}
+
+type EvalTestCmdOptions struct {
+	State   string
+	Syntax  string
+	Metrics string
+
+	Snapshot string
+	Save     string
+}
+
+func PopulateEvalTestFlags(cmd *cobra.Command, options *EvalTestCmdOptions) {
+	cmd.Flags().StringVar(&options.State, "state", "", "state expression to evaluate")
+	cmd.Flags().StringVar(&options.Syntax, "syntax", string(v1alpha1.ExprSyntaxTemplate), "syntax of the state expression (template or cel)")
+	cmd.Flags().StringVar(&options.Metrics, "metrics", "", "metrics expression to evaluate")
+
+	cmd.Flags().StringVar(&options.Snapshot, "snapshot", "", "evaluate against a saved snapshot, instead of a running test")
+	cmd.Flags().StringVar(&options.Save, "save", "", "save the snapshot of the test to the given file")
+}
+
+func NewEvalTestCmd() *cobra.Command {
+	var options EvalTestCmdOptions
+
+	cmd := &cobra.Command{
+		Use:               "test [testName]",
+		Aliases:           []string{"tests", "t"},
+		Short:             "Evaluate a state or metrics expression against a test",
+		Long:              `Evaluate a state or metrics expression against the current state of a running test, or against a saved snapshot.`,
+		ValidArgsFunction: EvalTestCmdCompletion,
+		Example: `# Evaluate a state expression against a running test
+  kubectl frisbee eval test demo --state '{{.NumFailedJobs}} == 0 && {{(.TimeInPhase "server").Seconds}} > 60'
+
+  # Evaluate a CEL expression, and save the snapshot of the test for later use
+  kubectl frisbee eval test demo --syntax cel --state 'maxRestarts() <= 2' --save snapshot.yaml
+
+  # Evaluate a state expression against a saved snapshot
+  kubectl frisbee eval test --snapshot snapshot.yaml --state '{{.IsRunning "server"}}'
+
+  # Evaluate a metrics expression against the Grafana of a running test
+  kubectl frisbee eval test demo --metrics 'avg() of query(summary/82/Avg, 1m, now) is below(200)'`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case (options.State == "") == (options.Metrics == ""):
+				ui.Failf("Pass either a state (--state) or a metrics (--metrics) expression.")
+			case options.Snapshot == "" && len(args) != 1:
+				ui.Failf("Pass Test name as argument, or a saved snapshot (--snapshot).")
+			case options.Snapshot != "" && len(args) != 0:
+				ui.Failf("Test name and snapshot (--snapshot) are mutually exclusive.")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			var snapshot *common.Snapshot
+
+			if options.Snapshot != "" {
+				loaded, err := common.LoadSnapshot(options.Snapshot)
+				ui.ExitOnError("Loading snapshot", err)
+
+				snapshot = loaded
+			} else {
+				if !common.CRDsExist(common.Scenarios) {
+					ui.Failf("Frisbee is not installed on the kubernetes cluster.")
+				}
+
+				captured, err := common.CaptureSnapshot(cmd.Context(), args[0])
+				ui.ExitOnError("Getting test information", err)
+
+				snapshot = captured
+			}
+
+			if options.Save != "" {
+				err := snapshot.Save(options.Save)
+				ui.ExitOnError("Saving snapshot to: "+options.Save, err)
+
+				env.Default.Hint("To evaluate other expressions against the snapshot use",
+					"kubectl frisbee eval test --snapshot", options.Save)
+			}
+
+			if options.State != "" {
+				evalState(snapshot, v1alpha1.ExprState(options.State), v1alpha1.ExprSyntax(options.Syntax))
+			} else {
+				evalMetrics(cmd, snapshot, v1alpha1.ExprMetrics(options.Metrics), options.Snapshot != "")
+			}
+		},
+	}
+
+	PopulateEvalTestFlags(cmd, &options)
+
+	return cmd
+}
+
+// evalState prints the steps for evaluating the state expression, as the scenario controller does.
+func evalState(snapshot *common.Snapshot, expr v1alpha1.ExprState, syntax v1alpha1.ExprSyntax) {
+	ui.ExitOnError("Validating syntax", syntax.Validate())
+
+	view := snapshot.View()
+	variables := snapshot.Scenario.GetVariables()
+
+	ui.NL()
+	ui.Info("Test:", snapshot.Scenario.GetName(), "at", snapshot.Time.UTC().String())
+	ui.Info("Jobs:", view.ListAll())
+
+	ui.Info("Expression:", string(expr))
+
+	if syntax == v1alpha1.ExprSyntaxCEL {
+		_, err := expr.CompileCEL()
+		ui.ExitOnError("Type-checking CEL expression", err)
+
+		ui.Info("Expansion:", "none (CEL expressions are not templates)")
+	} else {
+		expanded := string(expr)
+
+		if v1alpha1.HasVariables(expanded) {
+			var err error
+
+			expanded, err = v1alpha1.ExpandVariables(expanded, variables)
+			ui.ExitOnError("Expanding variables", err)
+
+			ui.Info("Variables:", expanded)
+		}
+
+		out, err := v1alpha1.ExprState(expanded).Evaluate(view)
+		ui.ExitOnError("Expanding template", err)
+
+		ui.Info("Expansion:", out)
+	}
+
+	pass, err := expr.Valuate(syntax, view, variables)
+	ui.ExitOnError("Evaluating expression", err)
+
+	ui.NL()
+	ui.Success("Result:", strconv.FormatBool(pass))
+}
+
+// evalMetrics prints the parsed alert rule, and evaluates it once against the Grafana of the test.
+func evalMetrics(cmd *cobra.Command, snapshot *common.Snapshot, expr v1alpha1.ExprMetrics, offline bool) {
+	rule, err := grafana.ParseAlertExpr(expr)
+	ui.ExitOnError("Parsing metrics expression", err)
+
+	ui.NL()
+	ui.Info("Expression:", string(expr))
+	ui.Info("Alert Rule:")
+	ui.Info("  Dashboard:", rule.DashboardUID)
+	ui.Info("  Panel:", fmt.Sprint(rule.PanelID))
+	ui.Info("  Metric:", rule.MetricName)
+	ui.Info("  Reducer:", rule.Reducer.Type)
+	ui.Info("  Evaluator:", rule.Evaluator.Type, fmt.Sprint(rule.Evaluator.Params))
+	ui.Info("  Range:", rule.FromTime, "to", rule.ToTime)
+	ui.Info("  For:", rule.Duration)
+	ui.Info("  Every:", rule.Frequency)
+
+	switch {
+	case offline:
+		ui.Warn("Metrics expressions are evaluated by Grafana. They cannot be evaluated against a snapshot.")
+
+		return
+	case snapshot.Scenario.Status.GrafanaEndpoint == "":
+		ui.Failf("Telemetry is not enabled for this test.")
+	}
+
+	grafanaClient, err := grafana.New(cmd.Context(), grafana.WithHTTP(snapshot.Scenario.Status.GrafanaEndpoint))
+	ui.ExitOnError("unable to connect to Grafana: err", err)
+
+	eval, err := grafanaClient.EvaluateAlert(cmd.Context(), rule, snapshot.Time)
+	ui.ExitOnError("Evaluating alert rule", err)
+
+	ui.Info("Query:", eval.From.UTC().String(), "to", eval.To.UTC().String())
+
+	if eval.HasValue {
+		ui.Info("Value:", strconv.FormatFloat(eval.Value, 'g', -1, 64))
+	} else {
+		ui.Info("Value:", "no values")
+	}
+
+	ui.Info("Fires:", strconv.FormatBool(eval.Fires), "(ignoring 'for' and 'every')")
+
+	// a fired alert means that the condition is violated.
+	ui.NL()
+	ui.Success("Result:", strconv.FormatBool(!eval.Fires))
+}
@@ -41,6 +41,7 @@ type InspectTestCmdOptions struct {
 	NoOverview, Events, ExternalResources, Templates bool
 	Deep                                             bool
 	Shell                                            string
+	Graph                                            string
 
 	Logs     []string
 	Loglines int
@@ -62,6 +63,10 @@ func InspectTestCmdFlags(cmd *cobra.Command, options *InspectTestCmdOptions) {
 		log.Fatal(err)
 	}
 
+	// graph
+	cmd.Flags().StringVar(&options.Graph, "graph", "", "render the dependency graph of the test, colored by phase (dot or mermaid)")
+	cmd.Flags().Lookup("graph").NoOptDefVal = common.GraphDOT
+
 	// logs
 	cmd.Flags().StringSliceVarP(&options.Logs, "logs", "l", nil, "show logs output from executor pod (if unsure, use 'all')")
 
@@ -108,6 +113,20 @@ func NewInspectTestCmd() *cobra.Command {
 				return
 			}
 
+			// Graph is exclusive
+			if options.Graph != "" {
+				// color the actions by the phase of their jobs, as classified by the controller.
+				snapshot, err := common.CaptureSnapshot(cmd.Context(), testName)
+				ui.ExitOnError("Getting Test Information", err)
+
+				test := snapshot.Scenario
+
+				err = common.RenderGraph(test, common.ActionPhases(test, snapshot.View()), options.Graph, os.Stdout)
+				ui.ExitOnError("Rendering graph", err)
+
+				return
+			}
+
 			// Always-on functions
 
 			if (!options.NoOverview) || options.Deep {
@@ -126,6 +145,24 @@ func NewInspectTestCmd() *cobra.Command {
 					ui.NL()
 					err = common.RenderList(&test.Status, os.Stdout)
 					ui.ExitOnError("== Scenario Status ==", err)
+
+					if timeline := test.Status.Timeline; len(timeline) > 0 {
+						ui.NL()
+						err = common.RenderList(timeline, os.Stdout)
+						ui.ExitOnError("== Action Timeline ==", err)
+					}
+
+					if trials := test.Status.Trials; trials != nil {
+						ui.NL()
+						err = common.RenderList(trials, os.Stdout)
+						ui.ExitOnError("== Scenario Trials ==", err)
+
+						if trials.Summary != nil {
+							ui.NL()
+							err = common.RenderList(trials.Summary, os.Stdout)
+							ui.ExitOnError("== Trials Summary ==", err)
+						}
+					}
 				}
 
 				ui.Success("== Scenario Overview ==")
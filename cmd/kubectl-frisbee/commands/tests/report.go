@@ -350,6 +350,11 @@ func FindTimeline(scenario *v1alpha1.Scenario) (from int64, to int64) {
 		if assert != nil {
 			return from, assert.LastTransitionTime.Time.Add(GraceMonitoringPeriod).UnixMilli()
 		}
+
+		deadline := meta.FindStatusCondition(scenario.Status.Conditions, v1alpha1.ConditionDeadlineExceeded.String())
+		if deadline != nil {
+			return from, deadline.LastTransitionTime.Time.Add(GraceMonitoringPeriod).UnixMilli()
+		}
 	}
 
 	// return a few second in the future to compensate for tardy events
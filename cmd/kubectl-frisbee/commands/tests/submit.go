@@ -19,6 +19,7 @@ package tests
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
 	"github.com/kubeshop/testkube/pkg/ui"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +53,8 @@ type SubmitTestCmdOptions struct {
 	Timeout                                   string
 
 	Logs []string
+
+	Set []string
 }
 
 func SubmitTestCmdFlags(cmd *cobra.Command, options *SubmitTestCmdOptions) {
@@ -64,6 +68,8 @@ func SubmitTestCmdFlags(cmd *cobra.Command, options *SubmitTestCmdOptions) {
 	cmd.Flags().BoolVar(&options.ExpectFailure, "expect-failure", false, "wait for the scenario to fail ungracefully.")
 	cmd.Flags().BoolVar(&options.ExpectError, "expect-error", false, "wait for the scenario to abort due to an assertion error.")
 	cmd.Flags().StringVarP(&options.Timeout, "timeout", "t", "1m", "wait for the scenario to complete or to fail.")
+
+	cmd.Flags().StringArrayVar(&options.Set, "set", nil, "override the scenario parameters (e.g, --set servers=8).")
 }
 
 func NewSubmitTestCmd() *cobra.Command {
@@ -82,6 +88,8 @@ func NewSubmitTestCmd() *cobra.Command {
   kubectl frisbee submit test --watch my-wf.yaml
 # Submit and tail logs until completion:
   kubectl frisbee submit test --log my-wf.yaml
+# Submit with overridden scenario parameters:
+  kubectl frisbee submit test --set servers=8 my-wf.yaml
 `,
 		ValidArgsFunction: SubmitTestCmdCompletion,
 
@@ -123,7 +131,7 @@ func NewSubmitTestCmd() *cobra.Command {
 			// This allows us to filter-out some poorly written scenarios before interacting with the server.
 			// More complex validation is performed on the server side (using admission webhooks) during
 			// the actual submission.
-			err := common.RunTest(testName, testFile, common.ValidationClient)
+			err := runTest(testName, testFile, options.Set, common.ValidationClient)
 			ui.ExitOnError("Validating testfile: "+testFile, err)
 			ui.Success("Scenario Validated:", testFile)
 
@@ -170,7 +178,7 @@ func NewSubmitTestCmd() *cobra.Command {
 			/*---------------------------------------------------
 			 * Submit Scenario
 			 *---------------------------------------------------*/
-			err = common.RunTest(testName, testFile, common.ValidationNone)
+			err = runTest(testName, testFile, options.Set, common.ValidationNone)
 			ui.ExitOnError("Starting test-case execution ", err)
 			ui.Success("Scenario submitted.")
 
@@ -184,6 +192,21 @@ func NewSubmitTestCmd() *cobra.Command {
 	return cmd
 }
 
+// runTest runs the test file, after overriding the parameters of its scenarios. The overridden documents are
+// stored in a temporary file, which is removed once the test has run.
+func runTest(testName string, testFile string, overrides []string, mode common.ValidationMode) error {
+	overridden, err := common.OverrideParameters(testName, testFile, overrides)
+	if err != nil {
+		return errors.Wrapf(err, "cannot override parameters")
+	}
+
+	if overridden != testFile {
+		defer os.Remove(overridden)
+	}
+
+	return common.RunTest(testName, overridden, mode)
+}
+
 func ControlOutput(ctx context.Context, testName string, options *SubmitTestCmdOptions) {
 	switch {
 	case options.ExpectSuccess:
@@ -35,7 +35,8 @@ func NewValidateTestCmd() *cobra.Command {
 		Use:     "test <Scenario>",
 		Aliases: []string{"tests", "t"},
 		Short:   "Validate a new test",
-		Long:    `Validate run the scenario in a dry-run mode`,
+		Long: `Validate analyzes the dependency graph of the scenario (cycles, undefined or
+short-lived dependencies, unreachable actions), and then runs the scenario in a dry-run mode`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				ui.Failf("Pass Scenario File or Scenario Dir")
@@ -101,6 +102,11 @@ func NewValidateTestCmd() *cobra.Command {
 func validateScenario(filepath string) error {
 	ui.Info("Validating Scenarios ... ", filepath)
 
+	// catch errors in the dependency graph before reaching the admission webhook.
+	if err := common.ValidateDependencies(filepath); err != nil {
+		return err
+	}
+
 	return common.RunTest("", filepath, common.ValidationServer)
 }
 
@@ -102,6 +102,7 @@ func NewUninstallCmd() *cobra.Command {
 					common.Chaos, common.Cascades,
 					common.VirtualObjects, common.Calls,
 					common.Templates, common.Scenarios,
+					common.Sweeps,
 				)
 
 				if err != nil && !common.ErrNotFound(out) {
@@ -17,6 +17,8 @@ limitations under the License.
 package commands
 
 import (
+	"os"
+
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/common"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/commands/tests"
 	"github.com/carv-ics-forth/frisbee/cmd/kubectl-frisbee/env"
@@ -25,23 +27,56 @@ import (
 )
 
 func NewValidateCmd() *cobra.Command {
+	var graph string
+
 	cmd := &cobra.Command{
 		Use:     "validate <resourceName>",
 		Aliases: []string{"check"},
 		Short:   "Validate a new test",
+		Example: `# Render the dependency graph of a scenario, without contacting the cluster
+  kubectl frisbee validate --graph my-wf.yaml
+
+  # Render the dependency graph in Mermaid format
+  kubectl frisbee validate --graph=mermaid my-wf.yaml`,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			env.Logo()
 			ui.SetVerbose(env.Default.Debug)
 
+			// the graph is rendered offline, and the output is kept clean for piping it to other tools.
+			if graph != "" {
+				return
+			}
+
+			env.Logo()
+
 			if !common.CRDsExist(common.Scenarios) {
 				ui.Failf("Frisbee is not installed on the kubernetes cluster.")
 			}
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			ui.PrintOnError("Displaying help", cmd.Help())
+			if graph == "" || len(args) == 0 {
+				ui.PrintOnError("Displaying help", cmd.Help())
+
+				return
+			}
+
+			testFile := args[0]
+
+			err := common.ValidateDependencies(testFile)
+			ui.ExitOnError("Validating dependencies", err)
+
+			scenarios, err := common.LoadScenarios(testFile)
+			ui.ExitOnError("Loading scenarios", err)
+
+			for i := range scenarios {
+				err := common.RenderGraph(&scenarios[i], nil, graph, os.Stdout)
+				ui.ExitOnError("Rendering graph", err)
+			}
 		},
 	}
 
+	cmd.Flags().StringVar(&graph, "graph", "", "render the dependency graph of the scenarios in the given file (dot or mermaid)")
+	cmd.Flags().Lookup("graph").NoOptDefVal = common.GraphDOT
+
 	cmd.AddCommand(tests.NewValidateTestCmd())
 
 	return cmd
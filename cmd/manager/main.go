@@ -27,6 +27,7 @@ import (
 	"github.com/carv-ics-forth/frisbee/controllers/cluster"
 	"github.com/carv-ics-forth/frisbee/controllers/scenario"
 	"github.com/carv-ics-forth/frisbee/controllers/service"
+	"github.com/carv-ics-forth/frisbee/controllers/sweep"
 	"github.com/carv-ics-forth/frisbee/controllers/template"
 	"github.com/pkg/errors"
 	"go.uber.org/zap/zapcore"
@@ -69,6 +70,10 @@ func main() {
 
 		enableChaos bool
 
+		applyKinds string
+
+		helmServiceAccounts string
+
 		// logger
 		verbose int
 	)
@@ -77,6 +82,14 @@ func main() {
 
 	flag.BoolVar(&enableChaos, "enable-chaos", true, "Enable Chaos controllers.")
 
+	flag.StringVar(&applyKinds, "apply-kinds", frisbeev1alpha1.DefaultApplyKinds,
+		"Comma-separated list of the kinds (Kind.group) that the Apply action can create. "+
+			"The operator must be granted the permissions for these kinds.")
+
+	flag.StringVar(&helmServiceAccounts, "helm-service-accounts", frisbeev1alpha1.DefaultHelmServiceAccounts,
+		"Comma-separated list of the ServiceAccounts that Helm actions can use. "+
+			"The operator must be allowed to impersonate these ServiceAccounts.")
+
 	// flag.StringVar(&namespace, "namespace", "default", "Restricts the manager's cache to watch objects in this namespace ")
 
 	// If set to "0" the metrics serving is disabled (otherwise, :8080).
@@ -101,6 +114,16 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if err := frisbeev1alpha1.SetApplyKinds(applyKinds); err != nil {
+		setupLog.Error(err, "invalid apply kinds", "kinds", applyKinds)
+		os.Exit(1)
+	}
+
+	if err := frisbeev1alpha1.SetHelmServiceAccounts(helmServiceAccounts); err != nil {
+		setupLog.Error(err, "invalid helm service accounts", "serviceAccounts", helmServiceAccounts)
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		WebhookServer: webhook.NewServer(webhook.Options{
@@ -164,6 +187,12 @@ func main() {
 
 			os.Exit(1)
 		}
+
+		if err := sweep.NewController(mgr, setupLog); err != nil {
+			utilruntime.HandleError(errors.Wrapf(err, "cannot create Sweep controller"))
+
+			os.Exit(1)
+		}
 	}
 
 	{
@@ -203,6 +232,12 @@ func main() {
 
 			os.Exit(1)
 		}
+
+		if err = (&frisbeev1alpha1.Sweep{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "cannot create webhook", "webhook", "Sweep")
+
+			os.Exit(1)
+		}
 	}
 
 	// +kubebuilder:scaffold:builder
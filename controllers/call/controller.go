@@ -42,6 +42,8 @@ import (
 // +kubebuilder:rbac:groups=frisbee.dev,resources=calls/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=frisbee.dev,resources=calls/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=frisbee.dev,resources=scenarios,verbs=get;list;watch
+
 // +kubebuilder:rbac:groups=frisbee.dev,resources=virtualobjects,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=frisbee.dev,resources=virtualobjects/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=frisbee.dev,resources=virtualobjects/finalizers,verbs=update
@@ -55,11 +57,12 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	*common.Environment
 
 	view *lifecycle.Classifier
 
 	// executor is used to run commands directly into containers
-	executor kubexec.Executor
+	executor kubexec.Interface
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -106,7 +109,11 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		The Update serves as "journaling" for the upcoming operations,
 		and as a roadblock for stall (queued) requests.
 	*/
-	if r.updateLifecycle(&call) {
+	// The captured values are collected before the lifecycle is updated, as the successful jobs are removed
+	// once the call is completed.
+	capturesChanged := r.updateCaptures(&call)
+
+	if r.updateLifecycle(&call) || capturesChanged {
 		if err := common.UpdateStatus(ctx, r, &call); err != nil {
 			// due to the multiple updates, it is possible for this function to
 			// be in conflict. We fix this issue by re-queueing the request.
@@ -149,6 +156,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// Check if the conditions are right to spawn a new job.
 		hasJob, nextTick, err := scheduler.Schedule(log, &call, scheduler.Parameters{
 			State:            *r.view,
+			Env:              r.GetEnvironment(),
 			LastScheduleTime: call.Status.LastScheduleTime,
 			ScheduleSpec:     call.Spec.Schedule,
 			ExpectedTimeline: call.Status.ExpectedTimeline,
@@ -160,13 +168,19 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		if !hasJob {
+			// the suspension is evaluated on every cycle. PromQL and time-dependent conditions are not watched, and must be polled.
+			if next := expressions.NextPolledEvaluation(r.Now(), call.Spec.SuspendWhen); !next.IsZero() &&
+				(nextTick.IsZero() || next.Before(nextTick)) {
+				nextTick = next
+			}
+
 			// nothing to schedule
 			if nextTick.IsZero() {
 				return common.Stop(r, req)
 			}
 
 			// sleep until next tick
-			return common.RequeueAfter(r, req, time.Until(nextTick))
+			return common.RequeueAfter(r, req, r.Until(nextTick))
 		}
 
 		// Fetch the next job from the queuing list, and submit it to Kubernetes.
@@ -190,7 +204,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 		// Update the scheduling information
 		call.Status.ScheduledJobs = nextJobIndex
-		call.Status.LastScheduleTime = metav1.Time{Time: time.Now()}
+		call.Status.LastScheduleTime = metav1.Time{Time: r.Now()}
 
 		return lifecycle.Pending(ctx, r, &call, fmt.Sprintf("Scheduled jobs: '%d/%d'",
 			call.Status.ScheduledJobs+1, len(call.Spec.Services)))
@@ -232,14 +246,12 @@ func (r *Controller) Initialize(ctx context.Context, call *v1alpha1.Call) error
 	call.Status.ScheduledJobs = -1
 
 	// Metrics-driven execution requires to set alerts on Grafana.
-	if until := call.Spec.SuspendWhen; until != nil && until.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, call, until.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.suspendWhen")
-		}
+	if err := expressions.SetAlerts(ctx, call, call.Spec.SuspendWhen); err != nil {
+		return errors.Wrapf(err, "spec.suspendWhen")
 	}
 
-	if schedule := call.Spec.Schedule; schedule != nil && schedule.Event.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, call, schedule.Event.Metrics); err != nil {
+	if schedule := call.Spec.Schedule; schedule != nil {
+		if err := expressions.SetAlerts(ctx, call, schedule.Event); err != nil {
 			return errors.Wrapf(err, "spec.schedule")
 		}
 	}
@@ -353,13 +365,22 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
-	reconciler := &Controller{
-		Manager:  mgr,
-		Logger:   logger.WithName("call"),
-		view:     &lifecycle.Classifier{},
-		executor: kubexec.NewExecutor(mgr.GetConfig()),
+// NewReconciler instantiates the controller with the given executor, without registering it to the manager.
+// It is used for driving the reconciliation outside the manager, e.g, for simulating the execution of scenarios.
+func NewReconciler(mgr ctrl.Manager, logger logr.Logger, executor kubexec.Interface, env *common.Environment) *Controller {
+	return &Controller{
+		Manager:     mgr,
+		Logger:      logger.WithName("call"),
+		Environment: env,
+		view:        lifecycle.NewClassifier(env.Clock),
+		executor:    executor,
 	}
+}
+
+func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+	executor := kubexec.NewExecutor(mgr.GetConfig())
+
+	reconciler := NewReconciler(mgr, logger, &executor, common.DefaultEnvironment())
 
 	gvk := v1alpha1.GroupVersion.WithKind("Call")
 
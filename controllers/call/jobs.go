@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/call/utils"
@@ -53,12 +56,15 @@ func (r *Controller) runJob(ctx context.Context, caller *v1alpha1.Call, jobIndex
 	// Call normally does not return anything. This however would break all the pipeline for
 	// managing dependencies between jobs. For that, we return a dummy virtual object without dedicated controller.
 	// FIXME: if the call fails, this object will be re-created, and the call will fail with an "existing object" error.
+	// The callback may run in the background, while the reconciliation cycle keeps updating the caller.
+	call := caller.DeepCopy()
+
 	return lifecycle.CreateVirtualJob(ctx, r, caller, jobName, func(task *v1alpha1.VirtualObject) error {
-		r.Info("-> Caller", "caller", caller.GetName(), "target", t)
-		defer r.Info("<- Caller", "caller", caller.GetName(), "target", t)
+		r.Info("-> Caller", "caller", call.GetName(), "target", t)
+		defer r.Info("<- Caller", "caller", call.GetName(), "target", t)
 
 		pod := types.NamespacedName{
-			Namespace: caller.GetNamespace(),
+			Namespace: call.GetNamespace(),
 			Name:      t.Service,
 		}
 
@@ -70,26 +76,24 @@ func (r *Controller) runJob(ctx context.Context, caller *v1alpha1.Call, jobIndex
 			"stderr", res.Stderr,
 		)
 
-		defer func() {
-			// Use the virtual object to store the remote execution logs.
-			task.Status.Data = map[string]string{
-				"info":   t.String(),
-				"stdout": res.Stdout,
-				"stderr": res.Stderr,
-			}
-		}()
+		// Use the virtual object to store the remote execution logs, and the captured values.
+		task.Status.Data = map[string]string{
+			"info":   t.String(),
+			"stdout": res.Stdout,
+			"stderr": res.Stderr,
+		}
 
 		if err != nil {
 			return errors.Wrapf(err, "call '%s' has failed", t.String())
 		}
 
-		if caller.Spec.Expect != nil {
+		if call.Spec.Expect != nil {
 			r.Logger.Info("AssertCall",
 				"job", jobName,
-				"expect", caller.Spec.Expect,
+				"expect", call.Spec.Expect,
 			)
 
-			expect := caller.Spec.Expect[jobIndex]
+			expect := call.Spec.Expect[jobIndex]
 
 			if expect.Stdout != nil {
 				matchStdout, err := regexp.MatchString(*expect.Stdout, res.Stdout)
@@ -114,14 +118,82 @@ func (r *Controller) runJob(ctx context.Context, caller *v1alpha1.Call, jobIndex
 			}
 		}
 
+		for _, capture := range call.Spec.Capture {
+			value, err := capture.Extract(res.Stdout, res.Stderr)
+			if err != nil {
+				return errors.Wrapf(err, "cannot capture '%s'", capture.Name)
+			}
+
+			task.Status.Data[capturePrefix+capture.Name] = value
+		}
+
 		return nil
 	})
 }
 
+// capturePrefix distinguishes the captured values from the rest of the data of a call job.
+const capturePrefix = "capture."
+
+// updateCaptures collects the values captured by the successful call jobs. If multiple jobs capture the same
+// variable, the latest job wins. It returns true if any captured value is changed.
+func (r *Controller) updateCaptures(call *v1alpha1.Call) bool {
+	if len(call.Spec.Capture) == 0 {
+		return false
+	}
+
+	jobs := r.view.GetSuccessfulJobs()
+
+	// order the jobs by their index, as the names are generated by common.GenerateName.
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobIndex(jobs[i].GetName()) < jobIndex(jobs[j].GetName())
+	})
+
+	changed := false
+
+	for _, job := range jobs {
+		vobj, ok := job.(*v1alpha1.VirtualObject)
+		if !ok {
+			continue
+		}
+
+		for _, capture := range call.Spec.Capture {
+			value, exists := vobj.Status.Data[capturePrefix+capture.Name]
+			if !exists {
+				continue
+			}
+
+			if current, exists := call.Status.Captured[capture.Name]; exists && current == value {
+				continue
+			}
+
+			if call.Status.Captured == nil {
+				call.Status.Captured = make(map[string]string)
+			}
+
+			call.Status.Captured[capture.Name] = value
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// jobIndex returns the index of a job, as encoded in its name (e.g, call-3).
+func jobIndex(name string) int {
+	index, err := strconv.Atoi(name[strings.LastIndex(name, "-")+1:])
+	if err != nil {
+		return -1
+	}
+
+	return index
+}
+
 // buildJobQueue creates a list of job templates that will be scheduled throughout execution.
 func (r *Controller) buildJobQueue(ctx context.Context, call *v1alpha1.Call) ([]v1alpha1.Callable, error) {
 	specs := make([]v1alpha1.Callable, len(call.Spec.Services))
 
+	var variables map[string]string
+
 	for i, serviceName := range call.Spec.Services {
 		var service v1alpha1.Service
 
@@ -159,7 +231,7 @@ func (r *Controller) buildJobQueue(ctx context.Context, call *v1alpha1.Call) ([]
 		}
 
 		// retry to until we get information about the service.
-		if err := wait.ExponentialBackoffWithContext(ctx, common.DefaultBackoffForServiceEndpoint, retryCond); err != nil {
+		if err := wait.ExponentialBackoffWithContext(ctx, r.BackoffForServiceEndpoint, retryCond); err != nil {
 			return nil, errors.Wrapf(err, "cannot get info for service %s", serviceName)
 		}
 
@@ -170,6 +242,35 @@ func (r *Controller) buildJobQueue(ctx context.Context, call *v1alpha1.Call) ([]
 				call.Spec.Callable, serviceName, structure.SortedMapKeys(service.Spec.Callables))
 		}
 
+		// expand the variables captured by previous calls of the scenario.
+		command := make([]string, len(callable.Command))
+
+		for j, arg := range callable.Command {
+			if !v1alpha1.HasVariables(arg) {
+				command[j] = arg
+
+				continue
+			}
+
+			if variables == nil {
+				scenarioVars, err := r.scenarioVariables(ctx, call)
+				if err != nil {
+					return nil, errors.Wrapf(err, "cannot get variables")
+				}
+
+				variables = scenarioVars
+			}
+
+			expanded, err := v1alpha1.ExpandVariables(arg, variables)
+			if err != nil {
+				return nil, errors.Wrapf(err, "callable '%s/%s'", call.Spec.Callable, serviceName)
+			}
+
+			command[j] = expanded
+		}
+
+		callable.Command = command
+
 		specs[i] = callable
 	}
 
@@ -177,3 +278,20 @@ func (r *Controller) buildJobQueue(ctx context.Context, call *v1alpha1.Call) ([]
 
 	return specs, nil
 }
+
+// scenarioVariables returns the variables captured by the scenario that the call belongs to.
+func (r *Controller) scenarioVariables(ctx context.Context, call *v1alpha1.Call) (map[string]string, error) {
+	var scenario v1alpha1.Scenario
+
+	key := client.ObjectKey{Namespace: call.GetNamespace(), Name: call.GetLabels()[v1alpha1.LabelScenario]}
+
+	if key.Name == "" {
+		return nil, errors.Errorf("call '%s' does not belong to a scenario", call.GetName())
+	}
+
+	if err := r.GetClient().Get(ctx, key, &scenario); err != nil {
+		return nil, errors.Wrapf(err, "cannot get scenario '%s'", key)
+	}
+
+	return scenario.Status.Variables, nil
+}
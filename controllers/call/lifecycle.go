@@ -53,17 +53,18 @@ func (r *Controller) updateLifecycle(call *v1alpha1.Call) bool {
 		return lifecycle.GroupedJobs(totalJobs, r.view, &call.Status.Lifecycle, call.Spec.Tolerate)
 	}
 
-	eval := expressions.Condition{Expr: call.Spec.SuspendWhen}
+	eval := expressions.Condition{Expr: call.Spec.SuspendWhen, Env: r.GetEnvironment()}
 	if eval.IsTrue(r.view, call) {
 		call.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
 		call.Status.Lifecycle.Reason = "UntilCondition"
 		call.Status.Lifecycle.Message = eval.Info
 
 		meta.SetStatusCondition(&call.Status.Lifecycle.Conditions, metav1.Condition{
-			Type:    v1alpha1.ConditionAllJobsAreScheduled.String(),
-			Status:  metav1.ConditionTrue,
-			Reason:  "UntilCondition",
-			Message: eval.Info,
+			Type:               v1alpha1.ConditionAllJobsAreScheduled.String(),
+			Status:             metav1.ConditionTrue,
+			Reason:             "UntilCondition",
+			Message:            eval.Info,
+			LastTransitionTime: r.MetaNow(),
 		})
 
 		// prevent the parent from spawning new jobs.
@@ -88,10 +89,11 @@ func (r *Controller) updateLifecycle(call *v1alpha1.Call) bool {
 		call.Status.Lifecycle.Message = msg
 
 		meta.SetStatusCondition(&call.Status.Lifecycle.Conditions, metav1.Condition{
-			Type:    v1alpha1.ConditionJobUnexpectedTermination.String(),
-			Status:  metav1.ConditionTrue,
-			Reason:  "MaxInstancesReached",
-			Message: msg,
+			Type:               v1alpha1.ConditionJobUnexpectedTermination.String(),
+			Status:             metav1.ConditionTrue,
+			Reason:             "MaxInstancesReached",
+			Message:            msg,
+			LastTransitionTime: r.MetaNow(),
 		})
 
 		return true
@@ -45,6 +45,7 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	*common.Environment
 
 	view *lifecycle.Classifier
 }
@@ -137,6 +138,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// Check if the conditions are right to spawn a new job.
 		hasJob, nextTick, err := scheduler.Schedule(log, &cascade, scheduler.Parameters{
 			State:            *r.view,
+			Env:              r.GetEnvironment(),
 			ScheduleSpec:     cascade.Spec.Schedule,
 			LastScheduleTime: cascade.Status.LastScheduleTime,
 			ExpectedTimeline: cascade.Status.ExpectedTimeline,
@@ -148,13 +150,19 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		if !hasJob {
+			// the suspension is evaluated on every cycle. PromQL and time-dependent conditions are not watched, and must be polled.
+			if next := expressions.NextPolledEvaluation(r.Now(), cascade.Spec.SuspendWhen); !next.IsZero() &&
+				(nextTick.IsZero() || next.Before(nextTick)) {
+				nextTick = next
+			}
+
 			// nothing to schedule
 			if nextTick.IsZero() {
 				return common.Stop(r, req)
 			}
 
 			// sleep until next tick
-			return common.RequeueAfter(r, req, time.Until(nextTick))
+			return common.RequeueAfter(r, req, r.Until(nextTick))
 		}
 
 		// Fetch the next job from the queuing list, and submit it to Kubernetes.
@@ -178,7 +186,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 		// Update the scheduling information
 		cascade.Status.ScheduledJobs = nextJobIndex
-		cascade.Status.LastScheduleTime = metav1.Time{Time: time.Now()}
+		cascade.Status.LastScheduleTime = metav1.Time{Time: r.Now()}
 
 		return lifecycle.Pending(ctx, r, &cascade, fmt.Sprintf("Scheduled jobs: '%d/%d'",
 			cascade.Status.ScheduledJobs+1, cascade.Spec.MaxInstances))
@@ -220,14 +228,12 @@ func (r *Controller) Initialize(ctx context.Context, cascade *v1alpha1.Cascade)
 	cascade.Status.ScheduledJobs = -1
 
 	// Metrics-driven execution requires to set alerts on Grafana.
-	if until := cascade.Spec.SuspendWhen; until != nil && until.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, cascade, until.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.suspendWhen")
-		}
+	if err := expressions.SetAlerts(ctx, cascade, cascade.Spec.SuspendWhen); err != nil {
+		return errors.Wrapf(err, "spec.suspendWhen")
 	}
 
-	if schedule := cascade.Spec.Schedule; schedule != nil && schedule.Event.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, cascade, schedule.Event.Metrics); err != nil {
+	if schedule := cascade.Spec.Schedule; schedule != nil {
+		if err := expressions.SetAlerts(ctx, cascade, schedule.Event); err != nil {
 			return errors.Wrapf(err, "spec.schedule")
 		}
 	}
@@ -337,12 +343,19 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
-	controller := &Controller{
-		Manager: mgr,
-		Logger:  logger.WithName("cascade"),
-		view:    &lifecycle.Classifier{},
+// NewReconciler instantiates the controller, without registering it to the manager.
+// It is used for driving the reconciliation outside the manager, e.g, for simulating the execution of scenarios.
+func NewReconciler(mgr ctrl.Manager, logger logr.Logger, env *common.Environment) *Controller {
+	return &Controller{
+		Manager:     mgr,
+		Logger:      logger.WithName("cascade"),
+		Environment: env,
+		view:        lifecycle.NewClassifier(env.Clock),
 	}
+}
+
+func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+	controller := NewReconciler(mgr, logger, common.DefaultEnvironment())
 
 	gvk := v1alpha1.GroupVersion.WithKind("Cascade")
 
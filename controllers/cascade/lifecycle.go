@@ -42,17 +42,18 @@ func (r *Controller) updateLifecycle(cr *v1alpha1.Cascade) bool {
 			return lifecycle.GroupedJobs(totalJobs, r.view, &cr.Status.Lifecycle, nil)
 		}
 
-		eval := expressions.Condition{Expr: cr.Spec.SuspendWhen}
+		eval := expressions.Condition{Expr: cr.Spec.SuspendWhen, Env: r.GetEnvironment()}
 		if eval.IsTrue(r.view, cr) {
 			cr.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
 			cr.Status.Lifecycle.Reason = "UntilCondition"
 			cr.Status.Lifecycle.Message = eval.Info
 
 			meta.SetStatusCondition(&cr.Status.Lifecycle.Conditions, metav1.Condition{
-				Type:    v1alpha1.ConditionAllJobsAreScheduled.String(),
-				Status:  metav1.ConditionTrue,
-				Reason:  "UntilCondition",
-				Message: eval.Info,
+				Type:               v1alpha1.ConditionAllJobsAreScheduled.String(),
+				Status:             metav1.ConditionTrue,
+				Reason:             "UntilCondition",
+				Message:            eval.Info,
+				LastTransitionTime: r.MetaNow(),
 			})
 
 			// prevent the parent from spawning new jobs.
@@ -77,10 +78,11 @@ func (r *Controller) updateLifecycle(cr *v1alpha1.Cascade) bool {
 			cr.Status.Lifecycle.Message = msg
 
 			meta.SetStatusCondition(&cr.Status.Lifecycle.Conditions, metav1.Condition{
-				Type:    v1alpha1.ConditionJobUnexpectedTermination.String(),
-				Status:  metav1.ConditionTrue,
-				Reason:  "MaxInstancesReached",
-				Message: msg,
+				Type:               v1alpha1.ConditionJobUnexpectedTermination.String(),
+				Status:             metav1.ConditionTrue,
+				Reason:             "MaxInstancesReached",
+				Message:            msg,
+				LastTransitionTime: r.MetaNow(),
 			})
 
 			return true
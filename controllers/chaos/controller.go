@@ -46,6 +46,7 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	*common.Environment
 
 	view *lifecycle.Classifier
 }
@@ -119,7 +120,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		// Update the scheduling information
-		chaos.Status.LastScheduleTime = &metav1.Time{Time: time.Now()}
+		chaos.Status.LastScheduleTime = &metav1.Time{Time: r.Now()}
 
 		return lifecycle.Pending(ctx, r, &chaos, "injecting fault")
 
@@ -272,10 +273,13 @@ func (r *Controller) Finalize(obj client.Object) error {
 */
 
 func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+	env := common.DefaultEnvironment()
+
 	controller := &Controller{
-		Manager: mgr,
-		Logger:  logger.WithName("chaos"),
-		view:    &lifecycle.Classifier{},
+		Manager:     mgr,
+		Logger:      logger.WithName("chaos"),
+		Environment: env,
+		view:        lifecycle.NewClassifier(env.Clock),
 	}
 
 	gvk := v1alpha1.GroupVersion.WithKind("Chaos")
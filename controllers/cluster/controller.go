@@ -29,6 +29,7 @@ import (
 	"github.com/carv-ics-forth/frisbee/pkg/expressions"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
 	"github.com/carv-ics-forth/frisbee/pkg/scheduler"
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -46,6 +47,7 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	*common.Environment
 
 	view *lifecycle.Classifier
 }
@@ -84,7 +86,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		2: Load CR's children and classify their current state (view)
 		------------------------------------------------------------------
 	*/
-	if err := r.PopulateView(ctx, req.NamespacedName); err != nil {
+	if err := r.PopulateView(ctx, req.NamespacedName, cluster.Status.RemovedJobs); err != nil {
 		return lifecycle.Failed(ctx, r, &cluster, errors.Wrapf(err, "cannot populate view for '%s'", req))
 	}
 
@@ -94,7 +96,9 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		The Update serves as "journaling" for the upcoming operations,
 		and as a roadblock for stall (queued) requests.
 	*/
-	if r.updateLifecycle(&cluster) {
+	restartsChanged := r.updateRestarts(&cluster)
+
+	if r.updateLifecycle(&cluster) || restartsChanged {
 		if err := common.UpdateStatus(ctx, r, &cluster); err != nil {
 			// due to the multiple updates, it is possible for this function to
 			// be in conflict. We fix this issue by re-queueing the request.
@@ -107,6 +111,15 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		------------------------------------------------------------------
 	*/
 
+	// Align the jobs with the instances, if they have been changed while the cluster is active.
+	if needsScaling(&cluster) {
+		if err := r.scale(ctx, &cluster); err != nil {
+			return lifecycle.Failed(ctx, r, &cluster, errors.Wrapf(err, "scaling error"))
+		}
+
+		return r.progress(ctx, req, &cluster, fmt.Sprintf("Scaled to '%d' instances", cluster.Spec.MaxInstances))
+	}
+
 	if cluster.Spec.Suspend != nil && *cluster.Spec.Suspend {
 		// If this object is suspended, we don't want to run any jobs, so we'll stop now.
 		// This is useful if something's broken with the job we're running, and we want to
@@ -126,10 +139,11 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 		return lifecycle.Pending(ctx, r, &cluster, "ready to start creating jobs.")
 
-	case v1alpha1.PhasePending:
+	case v1alpha1.PhasePending, v1alpha1.PhaseRunning:
+		// A Running cluster may have new jobs to schedule, if it has been scaled out.
 		//	If all jobs are scheduled but are not in the Running phase, they may be in the Pending phase.
 		//	In both cases, we have nothing else to do but waiting for the next reconciliation cycle.
-		if r.view.Count() >= len(cluster.Status.QueuedJobs) {
+		if r.view.Count()+len(cluster.Status.RemovedJobs) >= len(cluster.Status.QueuedJobs) {
 			r.Logger.Info("All jobs have been scheduled. Nothing else to do. ")
 
 			return common.Stop(r, req)
@@ -138,6 +152,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// Check if the conditions are right to spawn a new job.
 		hasJob, nextTick, err := scheduler.Schedule(log, &cluster, scheduler.Parameters{
 			State:            *r.view,
+			Env:              r.GetEnvironment(),
 			ScheduleSpec:     cluster.Spec.Schedule,
 			LastScheduleTime: cluster.Status.LastScheduleTime,
 			ExpectedTimeline: cluster.Status.ExpectedTimeline,
@@ -149,13 +164,19 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		if !hasJob {
+			// the suspension is evaluated on every cycle. PromQL and time-dependent conditions are not watched, and must be polled.
+			if next := expressions.NextPolledEvaluation(r.Now(), cluster.Spec.SuspendWhen); !next.IsZero() &&
+				(nextTick.IsZero() || next.Before(nextTick)) {
+				nextTick = next
+			}
+
 			// nothing to schedule
 			if nextTick.IsZero() {
 				return common.Stop(r, req)
 			}
 
 			// sleep until next tick
-			return common.RequeueAfter(r, req, time.Until(nextTick))
+			return common.RequeueAfter(r, req, r.Until(nextTick))
 		}
 
 		// Fetch the next job from the queuing list, and submit it to Kubernetes.
@@ -179,15 +200,11 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 		// Update the scheduling information
 		cluster.Status.ScheduledJobs = nextJobIndex
-		cluster.Status.LastScheduleTime = metav1.Time{Time: time.Now()}
+		cluster.Status.LastScheduleTime = metav1.Time{Time: r.Now()}
 
-		return lifecycle.Pending(ctx, r, &cluster, fmt.Sprintf("Scheduled jobs: '%d/%d'",
+		return r.progress(ctx, req, &cluster, fmt.Sprintf("Scheduled jobs: '%d/%d'",
 			cluster.Status.ScheduledJobs+1, cluster.Spec.MaxInstances))
 
-	case v1alpha1.PhaseRunning:
-		// Nothing to do. Just wait for something to happen.
-		return common.Stop(r, req)
-
 	case v1alpha1.PhaseSuccess:
 		if err := r.HasSucceed(ctx, &cluster); err != nil {
 			return common.RequeueAfter(r, req, time.Second)
@@ -206,6 +223,22 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	panic(errors.New("This should never happen"))
 }
 
+// progress reports the progress of the scheduling, without changing the phase of a Running cluster.
+// Otherwise, the cluster is regarded as Pending.
+func (r *Controller) progress(ctx context.Context, req ctrl.Request, cluster *v1alpha1.Cluster, msg string) (ctrl.Result, error) {
+	if !cluster.Status.Phase.Is(v1alpha1.PhaseRunning) {
+		return lifecycle.Pending(ctx, r, cluster, msg)
+	}
+
+	cluster.Status.Message = msg
+
+	if err := common.UpdateStatus(ctx, r, cluster); err != nil {
+		return common.RequeueAfter(r, req, time.Second)
+	}
+
+	return common.Stop(r, req)
+}
+
 func (r *Controller) Initialize(ctx context.Context, cluster *v1alpha1.Cluster) error {
 	/*
 		calculate any top-level distribution. this distribution will be respected during the construction of the jobs.
@@ -228,14 +261,12 @@ func (r *Controller) Initialize(ctx context.Context, cluster *v1alpha1.Cluster)
 	cluster.Status.ScheduledJobs = -1
 
 	// Metrics-driven execution requires to set alerts on Grafana.
-	if until := cluster.Spec.SuspendWhen; until != nil && until.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, cluster, until.Metrics); err != nil {
-			return errors.Wrapf(err, "spec.suspendWhen")
-		}
+	if err := expressions.SetAlerts(ctx, cluster, cluster.Spec.SuspendWhen); err != nil {
+		return errors.Wrapf(err, "spec.suspendWhen")
 	}
 
-	if schedule := cluster.Spec.Schedule; schedule != nil && schedule.Event.HasMetricsExpr() {
-		if err := expressions.SetAlert(ctx, cluster, schedule.Event.Metrics); err != nil {
+	if schedule := cluster.Spec.Schedule; schedule != nil {
+		if err := expressions.SetAlerts(ctx, cluster, schedule.Event); err != nil {
 			return errors.Wrapf(err, "spec.schedule")
 		}
 	}
@@ -247,7 +278,7 @@ func (r *Controller) Initialize(ctx context.Context, cluster *v1alpha1.Cluster)
 	return nil
 }
 
-func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName) error {
+func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName, removedJobs []string) error {
 	r.view.Reset()
 
 	var serviceJobs v1alpha1.ServiceList
@@ -257,6 +288,11 @@ func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName)
 		}
 
 		for i, job := range serviceJobs.Items {
+			// services removed by scaling are not accounted.
+			if structure.ContainsStrings(removedJobs, job.GetName()) {
+				continue
+			}
+
 			r.view.Classify(job.GetName(), &serviceJobs.Items[i])
 		}
 	}
@@ -349,12 +385,19 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
-func NewController(mgr ctrl.Manager, logger logr.Logger) error {
-	controller := &Controller{
-		Manager: mgr,
-		Logger:  logger.WithName("cluster"),
-		view:    &lifecycle.Classifier{},
+// NewReconciler instantiates the controller, without registering it to the manager.
+// It is used for driving the reconciliation outside the manager, e.g, for simulating the execution of scenarios.
+func NewReconciler(mgr ctrl.Manager, logger logr.Logger, env *common.Environment) *Controller {
+	return &Controller{
+		Manager:     mgr,
+		Logger:      logger.WithName("cluster"),
+		Environment: env,
+		view:        lifecycle.NewClassifier(env.Clock),
 	}
+}
+
+func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+	controller := NewReconciler(mgr, logger, common.DefaultEnvironment())
 
 	gvk := v1alpha1.GroupVersion.WithKind("Cluster")
 
@@ -43,17 +43,18 @@ func (r *Controller) updateLifecycle(cr *v1alpha1.Cluster) bool {
 			return lifecycle.GroupedJobs(totalJobs, r.view, &cr.Status.Lifecycle, cr.Spec.Tolerate)
 		}
 
-		eval := expressions.Condition{Expr: cr.Spec.SuspendWhen}
+		eval := expressions.Condition{Expr: cr.Spec.SuspendWhen, Env: r.GetEnvironment()}
 		if eval.IsTrue(r.view, cr) {
 			cr.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
 			cr.Status.Lifecycle.Reason = "UntilCondition"
 			cr.Status.Lifecycle.Message = eval.Info
 
 			meta.SetStatusCondition(&cr.Status.Lifecycle.Conditions, metav1.Condition{
-				Type:    v1alpha1.ConditionAllJobsAreScheduled.String(),
-				Status:  metav1.ConditionTrue,
-				Reason:  "UntilCondition",
-				Message: eval.Info,
+				Type:               v1alpha1.ConditionAllJobsAreScheduled.String(),
+				Status:             metav1.ConditionTrue,
+				Reason:             "UntilCondition",
+				Message:            eval.Info,
+				LastTransitionTime: r.MetaNow(),
 			})
 
 			// prevent the parent from spawning new jobs.
@@ -78,10 +79,11 @@ func (r *Controller) updateLifecycle(cr *v1alpha1.Cluster) bool {
 			cr.Status.Lifecycle.Message = msg
 
 			meta.SetStatusCondition(&cr.Status.Lifecycle.Conditions, metav1.Condition{
-				Type:    v1alpha1.ConditionJobUnexpectedTermination.String(),
-				Status:  metav1.ConditionTrue,
-				Reason:  "MaxInstancesReached",
-				Message: msg,
+				Type:               v1alpha1.ConditionJobUnexpectedTermination.String(),
+				Status:             metav1.ConditionTrue,
+				Reason:             "MaxInstancesReached",
+				Message:            msg,
+				LastTransitionTime: r.MetaNow(),
 			})
 
 			return true
@@ -97,8 +99,25 @@ func (r *Controller) updateLifecycle(cr *v1alpha1.Cluster) bool {
 		return true
 	}
 
-	// Step 4. Check if scheduling goes as expected.
-	totalJobs := len(cr.Status.QueuedJobs)
+	// Step 4. Check if scheduling goes as expected. Services removed by scaling are not expected to complete.
+	totalJobs := cr.Status.ExpectedJobs()
 
 	return lifecycle.GroupedJobs(totalJobs, r.view, &cr.Status.Lifecycle, cr.Spec.Tolerate)
 }
+
+// updateRestarts aggregates the restarts of the services into the status of the cluster, so that the restarts
+// can be asserted by the parent. It returns true if the restarts are changed.
+func (r *Controller) updateRestarts(cr *v1alpha1.Cluster) bool {
+	if cr.Status.Lifecycle.Phase.Is(v1alpha1.PhaseUninitialized, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		return false
+	}
+
+	restarts := int32(r.view.TotalRestarts())
+	if restarts == cr.Status.Restarts {
+		return false
+	}
+
+	cr.Status.Restarts = restarts
+
+	return true
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/distributions"
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// needsScaling returns true if the instances of the cluster have been changed after its initialization.
+// Clusters whose instances are driven by conditions (i.e, SuspendWhen) are not scalable.
+func needsScaling(cluster *v1alpha1.Cluster) bool {
+	if !cluster.Status.Phase.Is(v1alpha1.PhasePending, v1alpha1.PhaseRunning) || !cluster.Spec.SuspendWhen.IsZero() {
+		return false
+	}
+
+	return cluster.Spec.MaxInstances != cluster.Status.ExpectedJobs()
+}
+
+// scale aligns the queued jobs of the cluster with the requested instances.
+// Growing appends new jobs to the queue. Shrinking drops jobs that are not yet scheduled, and then removes
+// active services, in the requested order.
+func (r *Controller) scale(ctx context.Context, cluster *v1alpha1.Cluster) error {
+	expected := cluster.Status.ExpectedJobs()
+	instances := cluster.Spec.MaxInstances
+
+	if instances > expected {
+		// the distribution must cover the new instances.
+		if distName := cluster.Spec.DefaultDistributionSpec; distName != nil {
+			cluster.Status.DefaultDistribution = distributions.GenerateProbabilitySliceFromSpec(int64(instances), distName)
+		}
+
+		jobList, err := r.buildJobQueue(ctx, cluster)
+		if err != nil {
+			return errors.Wrapf(err, "building joblist")
+		}
+
+		cluster.Status.QueuedJobs = append(cluster.Status.QueuedJobs, jobList[expected:instances]...)
+
+		r.GetEventRecorderFor(cluster.GetName()).Event(cluster, corev1.EventTypeNormal, "ScaleOut",
+			fmt.Sprintf("from '%d' to '%d' instances", expected, instances))
+
+		return nil
+	}
+
+	drop, victims, err := planScaleIn(cluster, append(r.view.GetPendingJobs(), r.view.GetRunningJobs()...))
+	if err != nil {
+		return err
+	}
+
+	cluster.Status.QueuedJobs = cluster.Status.QueuedJobs[:len(cluster.Status.QueuedJobs)-drop]
+
+	// the removed services are recorded before the deletion, so that their termination is not regarded as failure.
+	for _, job := range victims {
+		if !structure.ContainsStrings(cluster.Status.RemovedJobs, job.GetName()) {
+			cluster.Status.RemovedJobs = append(cluster.Status.RemovedJobs, job.GetName())
+		}
+	}
+
+	if err := common.UpdateStatus(ctx, r, cluster); err != nil {
+		return errors.Wrapf(err, "cannot record removed jobs")
+	}
+
+	for _, job := range victims {
+		common.Delete(ctx, r, job)
+	}
+
+	r.GetEventRecorderFor(cluster.GetName()).Event(cluster, corev1.EventTypeNormal, "ScaleIn",
+		fmt.Sprintf("from '%d' to '%d' instances. Removed: '%s'", expected, instances, cluster.Status.RemovedJobs))
+
+	return nil
+}
+
+// planScaleIn returns the number of jobs to drop from the tail of the queue, and the active services to remove,
+// for shrinking the cluster to the requested instances. Jobs that are not yet scheduled are dropped first.
+func planScaleIn(cluster *v1alpha1.Cluster, active []client.Object) (drop int, victims []client.Object, err error) {
+	remove := cluster.Status.ExpectedJobs() - cluster.Spec.MaxInstances
+
+	if unscheduled := len(cluster.Status.QueuedJobs) - (cluster.Status.ScheduledJobs + 1); unscheduled > 0 {
+		drop = unscheduled
+		if remove < drop {
+			drop = remove
+		}
+
+		remove -= drop
+	}
+
+	if len(active) < remove {
+		return 0, nil, errors.Errorf("cannot remove '%d' services. Only '%d' are active", remove, len(active))
+	}
+
+	return drop, orderForRemoval(cluster, active)[:remove], nil
+}
+
+// orderForRemoval sorts the jobs in the order they should be removed. The jobs are named after the cluster
+// and their position in the queue, which reflects the order of their creation.
+func orderForRemoval(cluster *v1alpha1.Cluster, jobs []client.Object) []client.Object {
+	sorted := make([]client.Object, len(jobs))
+	copy(sorted, jobs)
+
+	position := func(job client.Object) int {
+		index, _ := strconv.Atoi(strings.TrimPrefix(job.GetName(), cluster.GetName()+"-"))
+
+		return index
+	}
+
+	switch cluster.Spec.RemovalOrder {
+	case v1alpha1.ScaleRandom:
+		rand.Shuffle(len(sorted), func(i, j int) {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		})
+
+	case v1alpha1.ScaleOldest:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return position(sorted[i]) < position(sorted[j])
+		})
+
+	default: // ScaleNewest
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return position(sorted[i]) > position(sorted[j])
+		})
+	}
+
+	return sorted
+}
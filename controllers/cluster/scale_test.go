@@ -0,0 +1,222 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newCluster returns a cluster with the given instances, queued, and scheduled jobs.
+func newCluster(instances, queued, scheduled int, order v1alpha1.ScaleOrder) *v1alpha1.Cluster {
+	var cluster v1alpha1.Cluster
+
+	cluster.SetName("clients")
+	cluster.Spec.MaxInstances = instances
+	cluster.Spec.RemovalOrder = order
+	cluster.Status.Phase = v1alpha1.PhaseRunning
+	cluster.Status.QueuedJobs = make([]v1alpha1.ServiceSpec, queued)
+	cluster.Status.ScheduledJobs = scheduled - 1
+
+	return &cluster
+}
+
+// services returns services named after the cluster and the given positions.
+func services(names ...string) []client.Object {
+	jobs := make([]client.Object, 0, len(names))
+
+	for _, name := range names {
+		var job v1alpha1.Service
+
+		job.SetName(name)
+
+		jobs = append(jobs, &job)
+	}
+
+	return jobs
+}
+
+func namesOf(jobs []client.Object) []string {
+	var names []string
+
+	for _, job := range jobs {
+		names = append(names, job.GetName())
+	}
+
+	return names
+}
+
+func TestNeedsScaling(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster func() *v1alpha1.Cluster
+		want    bool
+	}{
+		{
+			name:    "unchanged",
+			cluster: func() *v1alpha1.Cluster { return newCluster(3, 3, 3, "") },
+			want:    false,
+		},
+		{
+			name:    "scale out",
+			cluster: func() *v1alpha1.Cluster { return newCluster(5, 3, 3, "") },
+			want:    true,
+		},
+		{
+			name:    "scale in",
+			cluster: func() *v1alpha1.Cluster { return newCluster(1, 3, 3, "") },
+			want:    true,
+		},
+		{
+			name: "scaled in already",
+			cluster: func() *v1alpha1.Cluster {
+				cluster := newCluster(1, 3, 3, "")
+				cluster.Status.RemovedJobs = []string{"clients-3", "clients-2"}
+
+				return cluster
+			},
+			want: false,
+		},
+		{
+			name: "completed",
+			cluster: func() *v1alpha1.Cluster {
+				cluster := newCluster(5, 3, 3, "")
+				cluster.Status.Phase = v1alpha1.PhaseSuccess
+
+				return cluster
+			},
+			want: false,
+		},
+		{
+			name: "driven by conditions",
+			cluster: func() *v1alpha1.Cluster {
+				cluster := newCluster(5, 3, 3, "")
+				cluster.Spec.SuspendWhen = &v1alpha1.ConditionalExpr{State: "true"}
+
+				return cluster
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsScaling(tt.cluster()); got != tt.want {
+				t.Errorf("needsScaling() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderForRemoval(t *testing.T) {
+	active := services("clients-2", "clients-10", "clients-1", "clients-3")
+
+	tests := []struct {
+		order v1alpha1.ScaleOrder
+		want  []string
+	}{
+		{order: "", want: []string{"clients-10", "clients-3", "clients-2", "clients-1"}},
+		{order: v1alpha1.ScaleNewest, want: []string{"clients-10", "clients-3", "clients-2", "clients-1"}},
+		{order: v1alpha1.ScaleOldest, want: []string{"clients-1", "clients-2", "clients-3", "clients-10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			got := namesOf(orderForRemoval(newCluster(0, 0, 0, tt.order), active))
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderForRemoval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// a random order is a permutation of the jobs.
+	got := namesOf(orderForRemoval(newCluster(0, 0, 0, v1alpha1.ScaleRandom), active))
+	sort.Strings(got)
+
+	if want := []string{"clients-1", "clients-10", "clients-2", "clients-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("orderForRemoval() = %v, want a permutation of %v", got, want)
+	}
+
+	if namesOf(active)[0] != "clients-2" {
+		t.Errorf("orderForRemoval() has modified its input")
+	}
+}
+
+func TestPlanScaleIn(t *testing.T) {
+	tests := []struct {
+		name        string
+		cluster     *v1alpha1.Cluster
+		active      []client.Object
+		wantDrop    int
+		wantVictims []string
+		wantErr     bool
+	}{
+		{
+			name:        "remove the newest",
+			cluster:     newCluster(1, 3, 3, v1alpha1.ScaleNewest),
+			active:      services("clients-1", "clients-2", "clients-3"),
+			wantVictims: []string{"clients-3", "clients-2"},
+		},
+		{
+			name:        "remove the oldest",
+			cluster:     newCluster(1, 3, 3, v1alpha1.ScaleOldest),
+			active:      services("clients-1", "clients-2", "clients-3"),
+			wantVictims: []string{"clients-1", "clients-2"},
+		},
+		{
+			name:     "drop unscheduled jobs first",
+			cluster:  newCluster(2, 4, 2, v1alpha1.ScaleOldest),
+			active:   services("clients-1", "clients-2"),
+			wantDrop: 2,
+		},
+		{
+			name:        "drop unscheduled jobs, and remove the rest",
+			cluster:     newCluster(1, 4, 2, v1alpha1.ScaleOldest),
+			active:      services("clients-1", "clients-2"),
+			wantDrop:    2,
+			wantVictims: []string{"clients-1"},
+		},
+		{
+			name:    "not enough active services",
+			cluster: newCluster(1, 3, 3, v1alpha1.ScaleOldest),
+			active:  services("clients-3"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drop, victims, err := planScaleIn(tt.cluster, tt.active)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("planScaleIn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if drop != tt.wantDrop {
+				t.Errorf("planScaleIn() drop = %d, want %d", drop, tt.wantDrop)
+			}
+
+			if got := namesOf(victims); !reflect.DeepEqual(got, tt.wantVictims) {
+				t.Errorf("planScaleIn() victims = %v, want %v", got, tt.wantVictims)
+			}
+		})
+	}
+}
@@ -86,6 +86,9 @@ type Reconciler interface {
 
 	Logger
 
+	// GetEnvironment returns the environment that the controller runs in.
+	GetEnvironment() *Environment
+
 	// Finalizer returns a list of finalizers associated with the controller.
 	Finalizer() string
 
@@ -149,7 +152,7 @@ func Reconcile(parentCtx context.Context, r Reconciler, req ctrl.Request, obj cl
 				return true, nil
 			}
 
-			if err := wait.ExponentialBackoffWithContext(parentCtx, DefaultBackoffForK8sEndpoint, retryCond); err != nil {
+			if err := wait.ExponentialBackoffWithContext(parentCtx, r.GetEnvironment().BackoffForK8sEndpoint, retryCond); err != nil {
 				logger.Error(err, "Abort retrying to add finalizer. Requeue the request")
 
 				return RequeueWithError(r, req, err)
@@ -194,7 +197,7 @@ func Reconcile(parentCtx context.Context, r Reconciler, req ctrl.Request, obj cl
 					return true, nil
 				}
 
-				if err := wait.ExponentialBackoffWithContext(parentCtx, DefaultBackoffForK8sEndpoint, retryCond); err != nil {
+				if err := wait.ExponentialBackoffWithContext(parentCtx, r.GetEnvironment().BackoffForK8sEndpoint, retryCond); err != nil {
 					logger.Error(err, "Abort retrying to remove finalizer. Requeue the request")
 
 					return RequeueWithError(r, req, err)
@@ -291,6 +294,38 @@ func Create(ctx context.Context, reconciler Reconciler, parent, child client.Obj
 	return nil
 }
 
+// FieldOwner identifies the operator as the manager of the fields it applies.
+const FieldOwner = "frisbee"
+
+// Apply creates or updates the child using server-side apply. Unlike Create, an existing child is not silently
+// ignored: fields that are managed by others, or a child that is controlled by another owner, result in a conflict.
+func Apply(ctx context.Context, reconciler Reconciler, parent, child client.Object) error {
+	if reconciler == nil || parent == nil || child == nil {
+		panic(errors.Errorf("empty parameters.  Reconciler:%t Parent:%t Child:%t",
+			reconciler == nil, parent == nil, child == nil))
+	}
+
+	// Create a searchable link between the parent and the children.
+	v1alpha1.SetCreatedByLabel(child, parent)
+
+	child.SetNamespace(parent.GetNamespace())
+
+	if err := controllerutil.SetControllerReference(parent, child, reconciler.GetClient().Scheme()); err != nil {
+		return errors.Wrapf(err, "set controller reference")
+	}
+
+	reconciler.Info("++ Apply",
+		"kind", reflect.TypeOf(child),
+		"obj", client.ObjectKeyFromObject(child),
+	)
+
+	if err := reconciler.GetClient().Patch(ctx, child, client.Apply, client.FieldOwner(FieldOwner)); err != nil {
+		return errors.Wrapf(err, "apply error")
+	}
+
+	return nil
+}
+
 func ListChildren(ctx context.Context, cli client.Client, childJobs client.ObjectList, req types.NamespacedName) error {
 	filters := []client.ListOption{
 		client.InNamespace(req.Namespace),
@@ -304,6 +339,80 @@ func ListChildren(ctx context.Context, cli client.Client, childJobs client.Objec
 	return nil
 }
 
+// ListJobs returns the child jobs of the scenario, as classified by the scenario view.
+// It is shared by the scenario controller and the clients that inspect the scenario (e.g, snapshots).
+func ListJobs(ctx context.Context, cli client.Client, req types.NamespacedName) ([]client.Object, error) {
+	var jobs []client.Object
+
+	var serviceJobs v1alpha1.ServiceList
+	{
+		if err := ListChildren(ctx, cli, &serviceJobs, req); err != nil {
+			return nil, errors.Wrapf(err, "cannot list child services for '%s'", req)
+		}
+
+		for i := range serviceJobs.Items {
+			jobs = append(jobs, &serviceJobs.Items[i])
+		}
+	}
+
+	var clusterJobs v1alpha1.ClusterList
+	{
+		if err := ListChildren(ctx, cli, &clusterJobs, req); err != nil {
+			return nil, errors.Wrapf(err, "cannot list child clusters for '%s'", req)
+		}
+
+		for i := range clusterJobs.Items {
+			jobs = append(jobs, &clusterJobs.Items[i])
+		}
+	}
+
+	var chaosJobs v1alpha1.ChaosList
+	{
+		if err := ListChildren(ctx, cli, &chaosJobs, req); err != nil {
+			return nil, errors.Wrapf(err, "cannot list child chaos for '%s'", req)
+		}
+
+		for i := range chaosJobs.Items {
+			jobs = append(jobs, &chaosJobs.Items[i])
+		}
+	}
+
+	var cascadeJobs v1alpha1.CascadeList
+	{
+		if err := ListChildren(ctx, cli, &cascadeJobs, req); err != nil {
+			return nil, errors.Wrapf(err, "cannot list child cascades for '%s'", req)
+		}
+
+		for i := range cascadeJobs.Items {
+			jobs = append(jobs, &cascadeJobs.Items[i])
+		}
+	}
+
+	var virtualJobs v1alpha1.VirtualObjectList
+	{
+		if err := ListChildren(ctx, cli, &virtualJobs, req); err != nil {
+			return nil, errors.Wrapf(err, "cannot list child virtualobjects for '%s'", req)
+		}
+
+		for i := range virtualJobs.Items {
+			jobs = append(jobs, &virtualJobs.Items[i])
+		}
+	}
+
+	var callJobs v1alpha1.CallList
+	{
+		if err := ListChildren(ctx, cli, &callJobs, req); err != nil {
+			return nil, errors.Wrapf(err, "cannot list child calls for '%s'", req)
+		}
+
+		for i := range callJobs.Items {
+			jobs = append(jobs, &callJobs.Items[i])
+		}
+	}
+
+	return jobs, nil
+}
+
 // Delete removes a Kubernetes object, ignoring the NotFound error. If any error exists,
 // it is recorded in the reconciler's logger.
 func Delete(ctx context.Context, reconciler Reconciler, obj client.Object) {
@@ -27,6 +27,8 @@ const (
 	// DefaultPrometheusName should be a fixed name because it is used within the Grafana configuration.
 	// Otherwise, we should find a way to replace the value.
 	DefaultPrometheusName = "prometheus"
+
+	DefaultPrometheusPort = int64(9090)
 )
 
 // Grafana Section
@@ -0,0 +1,85 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilclock "k8s.io/utils/clock"
+)
+
+// Environment holds the dependencies of the controllers on the world outside the API server: the source of time,
+// the execution of virtual jobs, the retries of the remote endpoints, and the queries to Prometheus.
+// The controllers run with the DefaultEnvironment, unless the environment is emulated (e.g, by the simulator).
+type Environment struct {
+	// Clock is the source of time.
+	Clock utilclock.PassiveClock
+
+	// RunAsync runs the callbacks of the virtual jobs.
+	RunAsync func(callback func())
+
+	// BackoffForK8sEndpoint is the backoff for controller-to-k8s communication.
+	BackoffForK8sEndpoint wait.Backoff
+
+	// BackoffForServiceEndpoint is the backoff for controller-to-pod communication.
+	BackoffForServiceEndpoint wait.Backoff
+
+	// QueryPrometheus runs an instant query against the Prometheus of the scenario that the job belongs to.
+	// If nil, the Prometheus of the scenario is queried over the network.
+	QueryPrometheus func(ctx context.Context, job metav1.Object, query string) (model.Value, error)
+}
+
+// DefaultEnvironment returns the environment of the controllers that run within a Kubernetes cluster.
+func DefaultEnvironment() *Environment {
+	return &Environment{
+		Clock: utilclock.RealClock{},
+		RunAsync: func(callback func()) {
+			go callback()
+		},
+		BackoffForK8sEndpoint:     DefaultBackoffForK8sEndpoint,
+		BackoffForServiceEndpoint: DefaultBackoffForServiceEndpoint,
+	}
+}
+
+// GetEnvironment returns the environment itself. It allows the controllers to expose an embedded environment.
+func (env *Environment) GetEnvironment() *Environment {
+	return env
+}
+
+// Now returns the current time.
+func (env *Environment) Now() time.Time {
+	return env.Clock.Now()
+}
+
+// MetaNow returns the current time as a metav1.Time.
+func (env *Environment) MetaNow() metav1.Time {
+	return metav1.NewTime(env.Clock.Now())
+}
+
+// Since returns the time elapsed since t.
+func (env *Environment) Since(t time.Time) time.Duration {
+	return env.Clock.Since(t)
+}
+
+// Until returns the duration until t.
+func (env *Environment) Until(t time.Time) time.Duration {
+	return t.Sub(env.Clock.Now())
+}
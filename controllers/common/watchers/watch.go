@@ -22,6 +22,7 @@ import (
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
@@ -115,7 +116,8 @@ func (w *simpleWatch) watchUpdate(reconciler common.Reconciler, gvk schema.Group
 		latestPhase := latest.GetReconcileStatus().Phase
 
 		// a controller never initiates a phase change, and so is never asleep waiting for the same.
-		if prevPhase == latestPhase {
+		// The exception are the alerts, which are dispatched to objects (e.g, waits) without changing their phase.
+		if prevPhase == latestPhase && !expressions.AlertIsDispatched(event.ObjectOld, event.ObjectNew) {
 			reconciler.Info("Ignore Update", "obj", client.ObjectKeyFromObject(event.ObjectNew))
 
 			return false
@@ -133,7 +133,8 @@ func (w *watchWithPointAnnotation) watchUpdate(reconciler common.Reconciler, gvk
 		latestPhase := latest.GetReconcileStatus().Phase
 
 		// a controller never initiates a phase change, and so is never asleep waiting for the same.
-		if prevPhase == latestPhase {
+		// The exception are the restarts of services, which are aggregated by the parent without changing the phase.
+		if prevPhase == latestPhase && !restartsChanged(event.ObjectOld, event.ObjectNew) {
 			reconciler.Info("Ignore Update", "obj", client.ObjectKeyFromObject(event.ObjectNew))
 
 			return false
@@ -239,3 +240,11 @@ func (w *watchWithPointAnnotation) watchGeneric() GenericFunc {
 		return true
 	}
 }
+
+// restartsChanged returns true if the restarts of a service have changed since its previous version.
+func restartsChanged(prev, latest client.Object) bool {
+	prevService, prevOK := prev.(*v1alpha1.Service)
+	latestService, latestOK := latest.(*v1alpha1.Service)
+
+	return prevOK && latestOK && prevService.Status.Restarts != latestService.Status.Restarts
+}
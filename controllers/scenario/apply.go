@@ -0,0 +1,188 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	scenarioutils "github.com/carv-ics-forth/frisbee/controllers/scenario/utils"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// applyPollInterval is the interval for tracking the applied objects. The objects are not watched by the controller,
+// and therefore their progress is periodically polled.
+const applyPollInterval = 5 * time.Second
+
+// apply creates the objects of the manifests, and a virtual job that represents them. The objects are owned by the
+// virtual job, which records their references. Deleting the virtual job, or the scenario, garbage-collects them.
+// The objects are server-side applied. Existing objects are therefore adopted, unless they are controlled by
+// another owner, or their fields are managed by others, in which case the apply fails.
+func (r *Controller) apply(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	spec, err := scenarioutils.GetManifestSpec(ctx, r.GetClient(), scenario, *action.Apply)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get manifests")
+	}
+
+	objects, err := spec.Objects()
+	if err != nil {
+		return errors.Wrapf(err, "invalid manifests")
+	}
+
+	job := r.placeholder(scenario, action)
+
+	if err := common.Create(ctx, r, scenario, job); err != nil {
+		return errors.Wrapf(err, "cannot create apply")
+	}
+
+	refs := make(map[string]string, len(objects))
+
+	for _, obj := range objects {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+
+		labels[v1alpha1.LabelScenario] = scenario.GetName()
+		labels[v1alpha1.LabelAction] = action.Name
+		obj.SetLabels(labels)
+
+		if err := common.Apply(ctx, r, job, obj); err != nil {
+			return errors.Wrapf(err, "cannot apply '%s'", v1alpha1.ManifestKey(obj))
+		}
+
+		refs[v1alpha1.ManifestKey(obj)] = obj.GetAPIVersion()
+	}
+
+	job.Status.Data = refs
+	job.Status.Lifecycle = v1alpha1.Lifecycle{
+		Phase:   v1alpha1.PhasePending,
+		Reason:  "Applied",
+		Message: fmt.Sprintf("applied objects: %d", len(refs)),
+	}
+
+	return common.UpdateStatus(ctx, r, job)
+}
+
+// updateApplies resolves the lifecycle of the virtual jobs of the scheduled applies, given the lifecycle of
+// the applied objects. It returns true if any virtual job is updated.
+func (r *Controller) updateApplies(ctx context.Context, scenario *v1alpha1.Scenario) (bool, error) {
+	updated := false
+
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		action := getActionOrDie(scenario, actionName)
+
+		if action.ActionType != v1alpha1.ActionApply ||
+			!(r.view.IsPending(actionName) || r.view.IsRunning(actionName)) {
+			continue
+		}
+
+		var job v1alpha1.VirtualObject
+
+		key := client.ObjectKey{Namespace: scenario.GetNamespace(), Name: actionName}
+
+		if err := r.GetClient().Get(ctx, key, &job); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+
+		view, err := r.classifyApplied(ctx, &job)
+		if err != nil {
+			return false, errors.Wrapf(err, "cannot classify the objects of '%s'", actionName)
+		}
+
+		if !lifecycle.GroupedJobs(len(job.Status.Data), view, &job.Status.Lifecycle, nil) {
+			continue
+		}
+
+		if err := common.UpdateStatus(ctx, r, &job); err != nil {
+			return false, errors.Wrapf(err, "cannot update apply '%s'", actionName)
+		}
+
+		switch job.Status.Phase {
+		case v1alpha1.PhaseSuccess:
+			r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "ApplySuccess", job.Status.Message)
+		case v1alpha1.PhaseFailed:
+			r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeWarning, "ApplyFailed", job.Status.Message)
+		}
+
+		updated = true
+	}
+
+	return updated, nil
+}
+
+// classifyApplied classifies the objects referenced by the virtual job, using the convertors of their kinds.
+// Objects that no longer exist are regarded as failed.
+func (r *Controller) classifyApplied(ctx context.Context, job *v1alpha1.VirtualObject) (*lifecycle.Classifier, error) {
+	view := lifecycle.NewClassifier(r.Clock)
+
+	view.Reset()
+
+	for _, ref := range structure.SortedMapKeys(job.Status.Data) {
+		kind, name, ok := strings.Cut(ref, ".")
+		if !ok {
+			return nil, errors.Errorf("invalid reference '%s'", ref)
+		}
+
+		var obj unstructured.Unstructured
+
+		obj.SetAPIVersion(job.Status.Data[ref])
+		obj.SetKind(kind)
+
+		key := client.ObjectKey{Namespace: job.GetNamespace(), Name: name}
+
+		if err := r.GetClient().Get(ctx, key, &obj); err != nil {
+			if !k8errors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "cannot get '%s'", ref)
+			}
+
+			view.ClassifyExternal(ref, &obj, func(client.Object) v1alpha1.Lifecycle {
+				return v1alpha1.Lifecycle{Phase: v1alpha1.PhaseFailed, Reason: "NotFound", Message: "object is removed"}
+			})
+
+			continue
+		}
+
+		view.ClassifyExternal(ref, &obj, lifecycle.ConvertExternal)
+	}
+
+	return view, nil
+}
+
+// nextApply returns the next time for polling the applied objects. If there are no applies in progress, it returns zero.
+func (r *Controller) nextApply(scenario *v1alpha1.Scenario) time.Time {
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		action := getActionOrDie(scenario, actionName)
+
+		if action.ActionType == v1alpha1.ActionApply &&
+			(r.view.IsPending(actionName) || r.view.IsRunning(actionName)) {
+			return r.Now().Add(applyPollInterval)
+		}
+	}
+
+	return time.Time{}
+}
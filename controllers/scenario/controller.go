@@ -54,9 +54,21 @@ import (
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=nodes/status,verbs=get
 
+// The Apply action creates objects of the kinds set by v1alpha1.SetApplyKinds. Their permissions are granted by the
+// frisbee-apply ClusterRole of the chart (operator.apply.kinds), rather than by the markers.
+
+// Helm actions without a serviceAccountName store the releases in secrets, with the permissions of the controller.
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Helm actions with a serviceAccountName are operated with the permissions of that ServiceAccount. The permission to
+// impersonate it is limited to the allowed ServiceAccounts, by the frisbee-impersonate ClusterRole of the chart
+// (operator.helm.serviceAccounts). The admission webhook reviews whether the creator of the scenario may impersonate it.
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	*common.Environment
 
 	view *lifecycle.Classifier
 
@@ -99,6 +111,9 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(err, "cannot populate view for '%s'", req))
 	}
 
+	// the time in phase of the actions is measured from their recorded transitions.
+	r.view.SetTransitions(scenario.Status.Transitions)
+
 	/* Check if all the SYS services are running. If they are terminated (Failed/Success), we have nothing else to do,
 	and we abort the experiment. If they are still being created (Uninitialized, Pending), we sleep and retry */
 	if abort, sysErr := r.view.SystemState(); sysErr != nil {
@@ -110,6 +125,33 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return common.Stop(r, req)
 	}
 
+	// Roll-up the lifecycle of included actions to their includes, resolve the waits, and track the applied objects
+	// and the scaled clusters.
+	// The update of the virtual jobs triggers a new reconciliation cycle, where the lifecycle of the scenario is updated.
+	includesChanged, includeErr := r.updateIncludes(ctx, &scenario)
+	if includeErr != nil {
+		return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(includeErr, "include error"))
+	}
+
+	waitsChanged, waitErr := r.updateWaits(ctx, &scenario)
+	if waitErr != nil {
+		return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(waitErr, "wait error"))
+	}
+
+	appliesChanged, applyErr := r.updateApplies(ctx, &scenario)
+	if applyErr != nil {
+		return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(applyErr, "apply error"))
+	}
+
+	scalesChanged, scaleErr := r.updateScales(ctx, &scenario)
+	if scaleErr != nil {
+		return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(scaleErr, "scale error"))
+	}
+
+	if includesChanged || waitsChanged || appliesChanged || scalesChanged {
+		return common.Stop(r, req)
+	}
+
 	/*
 		3: Use the view to update the CR's lifecycle.
 		------------------------------------------------------------------
@@ -125,7 +167,35 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	/*
-		4: Make the world matching what we want in our spec.
+		3.1: Evaluate the post-conditions, once the scenario (or the running trial) reaches a terminal phase.
+		------------------------------------------------------------------
+		The post-conditions are evaluated before the jobs are removed, so that the view reflects the final state.
+	*/
+	if postConditionsInProgress(&scenario) {
+		return r.PostConditions(ctx, req, &scenario)
+	}
+
+	/*
+		4: Start the next trial, once a trial of a repeated scenario reaches a terminal phase.
+		------------------------------------------------------------------
+		The finally actions, and the cleanup of the scenario, are deferred until the last trial.
+	*/
+	if trialsInProgress(&scenario) {
+		return r.NextTrial(ctx, req, &scenario)
+	}
+
+	/*
+		5: Run the finally actions, once the scenario reaches a terminal phase.
+		------------------------------------------------------------------
+		The finally actions run before the cleanup of the scenario (HasSucceed, HasFailed),
+		so that they can still interact with the running services.
+	*/
+	if finallyInProgress(&scenario) {
+		return r.Finally(ctx, req, &scenario)
+	}
+
+	/*
+		6: Make the world matching what we want in our spec.
 		------------------------------------------------------------------
 	*/
 
@@ -151,18 +221,31 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return lifecycle.Pending(ctx, r, &scenario, "Initializing the testing environment")
 
 	case v1alpha1.PhasePending:
-		nextActionList, nextRun, err := r.NextJobs(&scenario)
+		nextActionList, skipActionList, nextRun, err := r.NextJobs(&scenario)
 		if err != nil {
 			return lifecycle.Failed(ctx, r, &scenario, errors.Wrapf(err, "scheduling error"))
 		}
 
+		// Skipped jobs are recorded for the lifecycle to be able to reach completion.
+		// The update will trigger a new reconciliation cycle, where actions that depend on the skipped jobs are handled.
+		scenario.Status.SkippedJobs = append(scenario.Status.SkippedJobs, skipActionList...)
+
 		if len(nextActionList) == 0 {
-			if nextRun.IsZero() {
+			if len(skipActionList) > 0 {
+				return lifecycle.Pending(ctx, r, &scenario, fmt.Sprintf("Skipped jobs: '%d/%d'",
+					len(scenario.Status.SkippedJobs), len(scenario.Spec.Actions)))
+			}
+
+			// wake up either for the next timeout, for the nearest deadline, for the nearest wait, for polling the applies
+			// and the scales, or for re-evaluating the PromQL expressions.
+			wakeup := earliest(nextRun, r.nextDeadline(&scenario), r.nextWait(&scenario), r.nextApply(&scenario),
+				r.nextScale(&scenario), r.nextEvaluation(&scenario))
+			if wakeup.IsZero() {
 				// nothing to do on this cycle. wait the next cycle trigger by watchers.
 				return common.Stop(r, req)
 			}
 
-			return common.RequeueAfter(r, req, time.Until(nextRun))
+			return common.RequeueAfter(r, req, r.Until(wakeup))
 		}
 
 		if err := r.RunActions(ctx, &scenario, nextActionList); err != nil {
@@ -170,10 +253,16 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		return lifecycle.Pending(ctx, r, &scenario, fmt.Sprintf("Scheduled jobs: '%d/%d'",
-			len(scenario.Status.ScheduledJobs), len(scenario.Spec.Actions)))
+			len(scenario.Status.ScheduledJobs), scenario.Spec.NumExpectedJobs(&scenario.Status)))
 
 	case v1alpha1.PhaseRunning:
-		// Nothing to do. Just wait for something to happen.
+		// Nothing to do. Just wait for something to happen, for the nearest deadline or wait to expire,
+		// for polling the applies and the scales, or for re-evaluating the PromQL expressions.
+		if wakeup := earliest(r.nextDeadline(&scenario), r.nextWait(&scenario), r.nextApply(&scenario),
+			r.nextScale(&scenario), r.nextEvaluation(&scenario)); !wakeup.IsZero() {
+			return common.RequeueAfter(r, req, r.Until(wakeup))
+		}
+
 		return common.Stop(r, req)
 
 	case v1alpha1.PhaseSuccess:
@@ -204,6 +293,24 @@ func (r *Controller) Initialize(ctx context.Context, scenario *v1alpha1.Scenario
 	/* FIXME: we set the configuration be global here. is there any better way ? */
 	configuration.SetGlobal(sysconf)
 
+	// expand the included scenarios. The expanded actions are persisted in the spec of the scenario, so that
+	// they are handled as any other action.
+	includes, modified, err := r.expandIncludes(ctx, scenario)
+	if err != nil {
+		return errors.Wrapf(err, "include error")
+	}
+
+	if modified {
+		// the update refreshes the scenario, including its status.
+		if err := common.Update(ctx, r, scenario); err != nil {
+			return errors.Wrapf(err, "cannot update expanded scenario")
+		}
+	}
+
+	if len(includes) > 0 {
+		scenario.Status.Includes = includes
+	}
+
 	// load the templates required by the scenario.
 	if errValidate := scenarioutils.LoadTemplates(ctx, r.GetClient(), scenario); errValidate != nil {
 		return errors.Wrapf(errValidate, "template error")
@@ -214,6 +321,9 @@ func (r *Controller) Initialize(ctx context.Context, scenario *v1alpha1.Scenario
 		return errors.Wrapf(errTelemetry, "telemetry error")
 	}
 
+	// Start the first trial of repeated scenarios.
+	r.initializeTrials(scenario)
+
 	r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "Initialized", "Start scheduling jobs")
 
 	meta.SetStatusCondition(&scenario.Status.Conditions, metav1.Condition{
@@ -233,70 +343,13 @@ active, successful, and failed jobs.
 func (r *Controller) PopulateView(ctx context.Context, req types.NamespacedName) error {
 	r.view.Reset()
 
-	var serviceJobs v1alpha1.ServiceList
-	{
-		if err := common.ListChildren(ctx, r.GetClient(), &serviceJobs, req); err != nil {
-			return errors.Wrapf(err, "cannot list child services for '%s'", req)
-		}
-
-		for i, job := range serviceJobs.Items {
-			r.view.Classify(job.GetName(), &serviceJobs.Items[i])
-		}
-	}
-
-	var clusterJobs v1alpha1.ClusterList
-	{
-		if err := common.ListChildren(ctx, r.GetClient(), &clusterJobs, req); err != nil {
-			return errors.Wrapf(err, "cannot list child clusters for '%s'", req)
-		}
-
-		for i, job := range clusterJobs.Items {
-			r.view.Classify(job.GetName(), &clusterJobs.Items[i])
-		}
-	}
-
-	var chaosJobs v1alpha1.ChaosList
-	{
-		if err := common.ListChildren(ctx, r.GetClient(), &chaosJobs, req); err != nil {
-			return errors.Wrapf(err, "cannot list child chaos for '%s'", req)
-		}
-
-		for i, job := range chaosJobs.Items {
-			r.view.Classify(job.GetName(), &chaosJobs.Items[i])
-		}
-	}
-
-	var cascadeJobs v1alpha1.CascadeList
-	{
-		if err := common.ListChildren(ctx, r.GetClient(), &cascadeJobs, req); err != nil {
-			return errors.Wrapf(err, "cannot list child cascades for '%s'", req)
-		}
-
-		for i, job := range cascadeJobs.Items {
-			r.view.Classify(job.GetName(), &cascadeJobs.Items[i])
-		}
-	}
-
-	var virtualJobs v1alpha1.VirtualObjectList
-	{
-		if err := common.ListChildren(ctx, r.GetClient(), &virtualJobs, req); err != nil {
-			return errors.Wrapf(err, "cannot list child virtualobjects for '%s'", req)
-		}
-
-		for i, job := range virtualJobs.Items {
-			r.view.Classify(job.GetName(), &virtualJobs.Items[i])
-		}
+	jobs, err := common.ListJobs(ctx, r.GetClient(), req)
+	if err != nil {
+		return err
 	}
 
-	var callJobs v1alpha1.CallList
-	{
-		if err := common.ListChildren(ctx, r.GetClient(), &callJobs, req); err != nil {
-			return errors.Wrapf(err, "cannot list child calls for '%s'", req)
-		}
-
-		for i, job := range callJobs.Items {
-			r.view.Classify(job.GetName(), &callJobs.Items[i])
-		}
+	for _, job := range jobs {
+		r.view.Classify(job.GetName(), job)
 	}
 
 	return nil
@@ -319,6 +372,17 @@ func (r *Controller) HasSucceed(ctx context.Context, scenario *v1alpha1.Scenario
 		// common.Delete(ctx, r, job)
 	}
 
+	// If the success is declared by SuccessWhen, some jobs may still be active. Remove them.
+	for _, job := range r.view.GetPendingJobs() {
+		expressions.UnsetAlert(ctx, job)
+		common.Delete(ctx, r, job)
+	}
+
+	for _, job := range r.view.GetRunningJobs() {
+		expressions.UnsetAlert(ctx, job)
+		common.Delete(ctx, r, job)
+	}
+
 	if scenario.GetDeletionTimestamp().IsZero() {
 		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "Completed", scenario.Status.Lifecycle.Message)
 	}
@@ -379,15 +443,38 @@ func (r *Controller) RunActions(ctx context.Context, scenario *v1alpha1.Scenario
 		}
 	}
 
-	for _, action := range nextActionList {
-		if action.Assert.HasMetricsExpr() {
-			// Assert belong to the top-level workflow. Not to the job
-			if err := expressions.SetAlert(ctx, scenario, action.Assert.Metrics); err != nil {
-				return errors.Wrapf(err, "cannot set assertions for action '%s'", action.Name)
+	// Metrics-based conditions must be armed from the beginning of the scenario, in order to capture
+	// any alert that is fired before the dependencies of the guarded action are met.
+	if len(scenario.Status.ScheduledJobs) == 0 {
+		for _, action := range scenario.Spec.Actions {
+			if err := expressions.SetAlerts(ctx, scenario, action.When); err != nil {
+				return errors.Wrapf(err, "cannot set condition for action '%s'", action.Name)
+			}
+		}
+
+		for rule, expr := range map[string]*v1alpha1.ConditionalExpr{
+			v1alpha1.VerdictSuccessWhen: scenario.Spec.SuccessWhen,
+			v1alpha1.VerdictFailWhen:    scenario.Spec.FailWhen,
+		} {
+			if err := expressions.SetAlerts(ctx, scenario, expr); err != nil {
+				return errors.Wrapf(err, "cannot set %s", rule)
 			}
 		}
+	}
+
+	for _, action := range nextActionList {
+		// Assert belong to the top-level workflow. Not to the job
+		if err := expressions.SetAlerts(ctx, scenario, action.Assert); err != nil {
+			return errors.Wrapf(err, "cannot set assertions for action '%s'", action.Name)
+		}
+
+		// expand the variables captured by the previous calls.
+		expanded, err := action.ExpandVariables(scenario.Status.Variables)
+		if err != nil {
+			return errors.Wrapf(err, "cannot expand variables")
+		}
 
-		if err := r.RunAction(ctx, scenario, action); err != nil {
+		if err := r.RunAction(ctx, scenario, *expanded); err != nil {
 			return errors.Wrapf(err, "cannot run action '%s'", action.Name)
 		}
 
@@ -402,6 +489,13 @@ func (r *Controller) RunActions(ctx context.Context, scenario *v1alpha1.Scenario
 			So, we need to use the job name as a lock to prevent us from making the job twice.
 		*/
 		scenario.Status.ScheduledJobs = append(scenario.Status.ScheduledJobs, action.Name)
+
+		scenario.Status.Timeline = append(scenario.Status.Timeline, v1alpha1.ActionRecord{
+			Name:        action.Name,
+			ActionType:  action.ActionType,
+			ScheduledAt: r.MetaNow(),
+			Phase:       v1alpha1.PhasePending,
+		})
 	}
 
 	return nil
@@ -437,13 +531,20 @@ func (r *Controller) Finalize(obj client.Object) error {
 	deleted, etc.
 */
 
+// NewReconciler instantiates the controller, without registering it to the manager and without the alerting service.
+// It is used for driving the reconciliation outside the manager, e.g, for simulating the execution of scenarios.
+func NewReconciler(mgr ctrl.Manager, logger logr.Logger, env *common.Environment) *Controller {
+	return &Controller{
+		Manager:     mgr,
+		Logger:      logger.WithName("scenario"),
+		Environment: env,
+		view:        lifecycle.NewClassifier(env.Clock),
+	}
+}
+
 func NewController(mgr ctrl.Manager, logger logr.Logger) error {
 	// instantiate the controller
-	controller := &Controller{
-		Manager: mgr,
-		Logger:  logger.WithName("scenario"),
-		view:    &lifecycle.Classifier{},
-	}
+	controller := NewReconciler(mgr, logger, common.DefaultEnvironment())
 
 	// initiate the alerting service
 	if err := NewAlertingProxy(context.Background(), controller); err != nil {
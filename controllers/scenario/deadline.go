@@ -0,0 +1,101 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// activeDeadline is the point in time after which an action, or the whole scenario, is regarded as hung.
+type activeDeadline struct {
+	// action is the name of the action that defines the deadline. It is empty for the scenario-wide deadline.
+	action string
+
+	// limit is the maximum duration the action (or the scenario) may be active.
+	limit time.Duration
+
+	// deadline is the point in time that the limit expires.
+	deadline time.Time
+}
+
+// activeDeadlines returns the deadlines of the scenario, and of the actions whose jobs are still active (i.e, pending
+// or running). Completed actions do not have active deadlines.
+func (r *Controller) activeDeadlines(scenario *v1alpha1.Scenario) []activeDeadline {
+	var deadlines []activeDeadline
+
+	if limit := scenario.Spec.ActiveDeadline; limit != nil {
+		deadlines = append(deadlines, activeDeadline{
+			limit:    limit.Duration,
+			deadline: startOf(scenario).Add(limit.Duration),
+		})
+	}
+
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		action := getActionOrDie(scenario, actionName)
+
+		if action.ActiveDeadline == nil {
+			continue
+		}
+
+		var job client.Object
+
+		switch {
+		case r.view.IsPending(actionName):
+			job = r.view.GetPendingJobs(actionName)[0]
+		case r.view.IsRunning(actionName):
+			job = r.view.GetRunningJobs(actionName)[0]
+		default:
+			continue
+		}
+
+		deadlines = append(deadlines, activeDeadline{
+			action:   actionName,
+			limit:    action.ActiveDeadline.Duration,
+			deadline: job.GetCreationTimestamp().Add(action.ActiveDeadline.Duration),
+		})
+	}
+
+	return deadlines
+}
+
+// nextDeadline returns the nearest deadline, so that the controller can wake up on time.
+// If there are no active deadlines, it returns zero.
+func (r *Controller) nextDeadline(scenario *v1alpha1.Scenario) time.Time {
+	var next time.Time
+
+	for _, d := range r.activeDeadlines(scenario) {
+		next = earliest(next, d.deadline)
+	}
+
+	return next
+}
+
+// earliest returns the earliest of the non-zero times. If all times are zero, it returns zero.
+func earliest(times ...time.Time) time.Time {
+	var next time.Time
+
+	for _, t := range times {
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateLifecycle_Deadlines(t *testing.T) {
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	tests := []struct {
+		name           string
+		deadline       *metav1.Duration
+		actionDeadline *metav1.Duration
+		phase          v1alpha1.Phase
+		now            time.Duration
+		wantExceeded   bool
+		wantMessage    string
+	}{
+		{
+			name:     "scenario within its deadline",
+			deadline: duration(5 * time.Minute),
+			phase:    v1alpha1.PhaseRunning,
+			now:      4 * time.Minute,
+		},
+		{
+			name:         "scenario exceeded its deadline",
+			deadline:     duration(5 * time.Minute),
+			phase:        v1alpha1.PhaseRunning,
+			now:          6 * time.Minute,
+			wantExceeded: true,
+			wantMessage:  "scenario exceeded its deadline of '5m0s'",
+		},
+		{
+			name:           "action within its deadline",
+			actionDeadline: duration(2 * time.Minute),
+			phase:          v1alpha1.PhaseRunning,
+			now:            2 * time.Minute,
+		},
+		{
+			name:           "action exceeded its deadline",
+			actionDeadline: duration(2 * time.Minute),
+			phase:          v1alpha1.PhaseRunning,
+			now:            4 * time.Minute,
+			wantExceeded:   true,
+			wantMessage:    "action 'server' exceeded its deadline of '2m0s'",
+		},
+		{
+			name:           "pending action exceeded its deadline",
+			actionDeadline: duration(2 * time.Minute),
+			phase:          v1alpha1.PhasePending,
+			now:            4 * time.Minute,
+			wantExceeded:   true,
+			wantMessage:    "action 'server' exceeded its deadline of '2m0s'",
+		},
+		{
+			name:           "completed action has no deadline",
+			actionDeadline: duration(2 * time.Minute),
+			phase:          v1alpha1.PhaseSuccess,
+			now:            4 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scenario v1alpha1.Scenario
+
+			scenario.SetCreationTimestamp(metav1.NewTime(start))
+			scenario.Spec.ActiveDeadline = tt.deadline
+			scenario.Spec.Actions = []v1alpha1.Action{{Name: "server", ActiveDeadline: tt.actionDeadline}}
+			scenario.Status.ScheduledJobs = []string{"server"}
+			scenario.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+
+			// the job of the action is created a minute after the scenario.
+			var server v1alpha1.Service
+
+			server.SetName("server")
+			server.SetCreationTimestamp(metav1.NewTime(start.Add(time.Minute)))
+			v1alpha1.SetComponentLabel(&server.ObjectMeta, v1alpha1.ComponentSUT)
+			server.Status.Lifecycle.Phase = tt.phase
+
+			env := fakeEnvironment(start.Add(tt.now))
+
+			view := lifecycle.NewClassifier(env.Clock)
+
+			view.Reset()
+			view.Classify(server.GetName(), &server)
+
+			r := &Controller{Logger: logr.Discard(), Environment: env, view: view}
+
+			r.updateLifecycle(&scenario)
+
+			exceeded := meta.IsStatusConditionTrue(scenario.Status.Conditions, v1alpha1.ConditionDeadlineExceeded.String())
+			if exceeded != tt.wantExceeded {
+				t.Fatalf("deadline exceeded = %v, want %v (%s)", exceeded, tt.wantExceeded, scenario.Status.Message)
+			}
+
+			if !tt.wantExceeded {
+				return
+			}
+
+			if scenario.Status.Phase != v1alpha1.PhaseFailed || scenario.Status.Reason != v1alpha1.ReasonDeadlineExceeded {
+				t.Errorf("lifecycle = %s/%s, want %s/%s", scenario.Status.Phase, scenario.Status.Reason,
+					v1alpha1.PhaseFailed, v1alpha1.ReasonDeadlineExceeded)
+			}
+
+			if !strings.Contains(scenario.Status.Message, tt.wantMessage) {
+				t.Errorf("message = %s, want %s", scenario.Status.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// finallyInProgress returns true if the scenario has reached a terminal phase, and the finally actions
+// are not yet completed.
+func finallyInProgress(scenario *v1alpha1.Scenario) bool {
+	if len(scenario.Spec.Finally) == 0 {
+		return false
+	}
+
+	if !scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		return false
+	}
+
+	return scenario.Status.Finally == nil || !scenario.Status.Finally.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed)
+}
+
+// Finally schedules the finally actions, and tracks their outcome until they are completed.
+// The outcome is recorded in the status of the scenario, without affecting the verdict of the scenario.
+func (r *Controller) Finally(ctx context.Context, req ctrl.Request, scenario *v1alpha1.Scenario) (ctrl.Result, error) {
+	/*---------------------------------------------------
+	 * Schedule the finally actions
+	 *---------------------------------------------------*/
+	if scenario.Status.Finally == nil {
+		scenario.Status.Finally = &v1alpha1.FinallyStatus{}
+
+		if err := r.runFinally(ctx, scenario); err != nil {
+			scenario.Status.Finally.Lifecycle = v1alpha1.Lifecycle{
+				Phase:   v1alpha1.PhaseFailed,
+				Reason:  "FinallyError",
+				Message: err.Error(),
+			}
+		} else {
+			scenario.Status.Finally.Lifecycle = v1alpha1.Lifecycle{
+				Phase:   v1alpha1.PhasePending,
+				Reason:  "FinallyScheduled",
+				Message: fmt.Sprintf("Scheduled jobs: '%d/%d'", len(scenario.Status.Finally.ScheduledJobs), len(scenario.Spec.Finally)),
+			}
+		}
+
+		// Every finally action may have been skipped.
+		if len(scenario.Status.Finally.ScheduledJobs) == 0 && scenario.Status.Finally.Phase.Is(v1alpha1.PhasePending) {
+			scenario.Status.Finally.Lifecycle = v1alpha1.Lifecycle{
+				Phase:   v1alpha1.PhaseSuccess,
+				Reason:  "FinallySkipped",
+				Message: fmt.Sprintf("Skipped jobs: '%d/%d'", len(scenario.Status.Finally.SkippedJobs), len(scenario.Spec.Finally)),
+			}
+		}
+
+		if err := common.UpdateStatus(ctx, r, scenario); err != nil {
+			return common.RequeueAfter(r, req, time.Second)
+		}
+
+		return common.Stop(r, req)
+	}
+
+	/*---------------------------------------------------
+	 * Track the outcome of the finally actions
+	 *---------------------------------------------------*/
+	finally := scenario.Status.Finally
+
+	view := lifecycle.NewClassifier(r.Clock)
+
+	view.Reset()
+
+	for _, job := range r.view.GetPendingJobs(finally.ScheduledJobs...) {
+		view.Classify(job.GetName(), job)
+	}
+
+	for _, job := range r.view.GetRunningJobs(finally.ScheduledJobs...) {
+		view.Classify(job.GetName(), job)
+	}
+
+	for _, job := range r.view.GetSuccessfulJobs(finally.ScheduledJobs...) {
+		view.Classify(job.GetName(), job)
+	}
+
+	for _, job := range r.view.GetFailedJobs(finally.ScheduledJobs...) {
+		view.Classify(job.GetName(), job)
+	}
+
+	if !lifecycle.GroupedJobs(len(finally.ScheduledJobs), view, &finally.Lifecycle, nil) {
+		// nothing has changed. wait the next cycle trigger by watchers.
+		return common.Stop(r, req)
+	}
+
+	if finally.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		eventType := corev1.EventTypeNormal
+		if finally.Phase.Is(v1alpha1.PhaseFailed) {
+			eventType = corev1.EventTypeWarning
+		}
+
+		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, eventType, "Finally", finally.Message)
+	}
+
+	if err := common.UpdateStatus(ctx, r, scenario); err != nil {
+		return common.RequeueAfter(r, req, time.Second)
+	}
+
+	return common.Stop(r, req)
+}
+
+// runFinally runs the finally actions whose When condition is true.
+func (r *Controller) runFinally(ctx context.Context, scenario *v1alpha1.Scenario) error {
+	finally := scenario.Status.Finally
+
+	for _, action := range scenario.Spec.Finally {
+		if !action.When.IsZero() {
+			eval := expressions.Condition{Expr: action.When, Env: r.GetEnvironment()}
+
+			if !eval.IsTrue(r.view, scenario) {
+				finally.SkippedJobs = append(finally.SkippedJobs, action.Name)
+
+				continue
+			}
+		}
+
+		if err := r.RunAction(ctx, scenario, action); err != nil {
+			return errors.Wrapf(err, "cannot run finally action '%s'", action.Name)
+		}
+
+		finally.ScheduledJobs = append(finally.ScheduledJobs, action.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/simulator"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const finallyTemplate = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Template
+metadata:
+  name: whalesay
+spec:
+  service:
+    containers:
+      - name: main
+        image: docker/whalesay
+`
+
+const finallyScenario = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: finally
+spec:
+  actions:
+    - action: Service
+      name: server
+      service:
+        templateRef: whalesay
+
+    - action: Cluster
+      name: clients
+      depends: { running: [ server ] }
+      cluster:
+        templateRef: whalesay
+        instances: 2
+
+    - action: Delete
+      name: teardown
+      depends: { running: [ server ], success: [ clients ] }
+      delete:
+        jobs: [ server ]
+
+  finally:
+    - action: Service
+      name: on-failure
+      when: { state: '{{.IsFailed "clients"}}' }
+      service:
+        templateRef: whalesay
+
+    - action: Service
+      name: on-success
+      when: { state: '{{.IsSuccessful "clients"}}' }
+      service:
+        templateRef: whalesay
+
+    - action: Service
+      name: always
+      service:
+        templateRef: whalesay
+`
+
+func TestFinally(t *testing.T) {
+	var template v1alpha1.Template
+
+	if err := yaml.Unmarshal([]byte(finallyTemplate), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		outcome       v1alpha1.Phase
+		wantPhase     v1alpha1.Phase
+		wantScheduled []string
+		wantSkipped   []string
+	}{
+		{
+			name:          "after success",
+			outcome:       v1alpha1.PhaseSuccess,
+			wantPhase:     v1alpha1.PhaseSuccess,
+			wantScheduled: []string{"on-success", "always"},
+			wantSkipped:   []string{"on-failure"},
+		},
+		{
+			name:          "after failure",
+			outcome:       v1alpha1.PhaseFailed,
+			wantPhase:     v1alpha1.PhaseFailed,
+			wantScheduled: []string{"on-failure", "always"},
+			wantSkipped:   []string{"on-success"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scenario v1alpha1.Scenario
+
+			if err := yaml.Unmarshal([]byte(finallyScenario), &scenario); err != nil {
+				t.Fatal(err)
+			}
+
+			mocks := simulator.Mocks{Jobs: []simulator.MockJob{
+				{Match: "server"},
+				{Match: "clients-*", Duration: &metav1.Duration{Duration: time.Minute}, Outcome: tt.outcome},
+			}}
+
+			result, err := simulator.Run(context.Background(), &scenario, []v1alpha1.Template{template}, simulator.Options{Mocks: mocks})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			status := result.Scenario.Status
+
+			if status.Phase != tt.wantPhase {
+				t.Errorf("phase = %v, want %v (%s)", status.Phase, tt.wantPhase, status.Message)
+			}
+
+			if status.Finally == nil {
+				t.Fatalf("finally actions have not run")
+			}
+
+			if !status.Finally.Phase.Is(v1alpha1.PhaseSuccess) {
+				t.Errorf("finally phase = %v, want %v (%s)", status.Finally.Phase, v1alpha1.PhaseSuccess, status.Finally.Message)
+			}
+
+			if !reflect.DeepEqual(status.Finally.ScheduledJobs, tt.wantScheduled) {
+				t.Errorf("finally scheduled = %v, want %v", status.Finally.ScheduledJobs, tt.wantScheduled)
+			}
+
+			if !reflect.DeepEqual(status.Finally.SkippedJobs, tt.wantSkipped) {
+				t.Errorf("finally skipped = %v, want %v", status.Finally.SkippedJobs, tt.wantSkipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxIncludeDepth bounds the nesting of included scenarios, in order to break cyclic inclusions.
+const maxIncludeDepth = 8
+
+// expandIncludes replaces the Scenario actions with the actions of the referenced fragments. Included fragments
+// may include other fragments, which are expanded in subsequent passes. It returns the actions instantiated by
+// every include, and whether the spec of the scenario is modified.
+func (r *Controller) expandIncludes(ctx context.Context, scenario *v1alpha1.Scenario) (map[string][]string, bool, error) {
+	includes := make(map[string][]string)
+	modified := false
+
+	for depth := 0; ; depth++ {
+		expanded := false
+
+		for i := 0; i < len(scenario.Spec.Actions); i++ {
+			action := scenario.Spec.Actions[i]
+
+			if action.ActionType != v1alpha1.ActionScenario {
+				continue
+			}
+
+			if _, exists := includes[action.Name]; exists {
+				continue
+			}
+
+			if depth >= maxIncludeDepth {
+				return nil, false, errors.Errorf("include '%s' exceeds the maximum depth of '%d'. Possibly cyclic",
+					action.Name, maxIncludeDepth)
+			}
+
+			fragment, err := r.getFragment(ctx, scenario, action.Scenario)
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "include '%s'", action.Name)
+			}
+
+			members, err := action.Scenario.Instantiate(action.Name, fragment)
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "include '%s'", action.Name)
+			}
+
+			names := make([]string, len(members))
+			existing := 0
+
+			for j, member := range members {
+				names[j] = member.Name
+
+				for _, match := range scenario.Spec.Actions {
+					if match.Name == member.Name {
+						existing++
+
+						break
+					}
+				}
+			}
+
+			switch existing {
+			case 0:
+				// place the members right after the include.
+				tail := append(members, scenario.Spec.Actions[i+1:]...)
+				scenario.Spec.Actions = append(scenario.Spec.Actions[:i+1], tail...)
+
+				modified = true
+			case len(members):
+				// the include is already expanded, e.g, before a restart of the controller.
+			default:
+				return nil, false, errors.Errorf("include '%s' conflicts with existing actions", action.Name)
+			}
+
+			includes[action.Name] = names
+			expanded = true
+		}
+
+		if !expanded {
+			break
+		}
+	}
+
+	if modified {
+		scenario.Default()
+
+		if _, err := v1alpha1.BuildDependencyGraph(scenario); err != nil {
+			return nil, false, errors.Wrapf(err, "invalid expansion")
+		}
+	}
+
+	return includes, modified, nil
+}
+
+// getFragment returns the scenario fragment of the referenced template, with the given parameters.
+func (r *Controller) getFragment(ctx context.Context, scenario *v1alpha1.Scenario, include *v1alpha1.IncludeSpec) (*v1alpha1.ScenarioSpec, error) {
+	var template v1alpha1.Template
+
+	key := client.ObjectKey{
+		Namespace: scenario.GetNamespace(),
+		Name:      include.TemplateRef,
+	}
+
+	if err := r.GetClient().Get(ctx, key, &template); err != nil {
+		return nil, errors.Wrapf(err, "cannot get template '%s'", key)
+	}
+
+	if template.Spec.EmbedSpecs == nil || template.Spec.Scenario == nil {
+		return nil, errors.Errorf("template '%s' does not define a scenario", key)
+	}
+
+	return v1alpha1.GenerateScenarioSpec(template.Spec.Scenario.Raw, include.Parameters)
+}
+
+// include creates a virtual job that represents the included fragment. The job remains running until
+// the actions of the fragment are completed.
+func (r *Controller) include(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	return common.Create(ctx, r, scenario, r.placeholder(scenario, action))
+}
+
+// updateIncludes rolls-up the lifecycle of the included actions to the virtual jobs of the scheduled includes.
+// It returns true if any virtual job is updated.
+func (r *Controller) updateIncludes(ctx context.Context, scenario *v1alpha1.Scenario) (bool, error) {
+	updated := false
+
+	for _, include := range structure.SortedMapKeys(scenario.Status.Includes) {
+		if !structure.ContainsStrings(scenario.Status.ScheduledJobs, include) ||
+			r.view.IsSuccessful(include) || r.view.IsFailed(include) {
+			continue
+		}
+
+		var job v1alpha1.VirtualObject
+
+		key := client.ObjectKey{Namespace: scenario.GetNamespace(), Name: include}
+
+		if err := r.GetClient().Get(ctx, key, &job); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+
+		phase, msg := r.includeLifecycle(scenario, scenario.Status.Includes[include])
+		if job.Status.Phase == phase {
+			continue
+		}
+
+		job.Status.Lifecycle.Phase = phase
+		job.Status.Lifecycle.Reason = "Included"
+		job.Status.Lifecycle.Message = msg
+
+		if err := common.UpdateStatus(ctx, r, &job); err != nil {
+			return false, errors.Wrapf(err, "cannot update include '%s'", include)
+		}
+
+		switch phase {
+		case v1alpha1.PhaseSuccess:
+			r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "IncludeSuccess", msg)
+		case v1alpha1.PhaseFailed:
+			r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeWarning, "IncludeFailed", msg)
+		}
+
+		updated = true
+	}
+
+	return updated, nil
+}
+
+// includeLifecycle returns the phase of an include, given the phase of its members.
+func (r *Controller) includeLifecycle(scenario *v1alpha1.Scenario, members []string) (v1alpha1.Phase, string) {
+	awaited := make(map[string]struct{})
+	exported := make(map[string]struct{})
+	deleted := make(map[string]struct{})
+
+	for _, action := range scenario.Spec.Actions {
+		if deps := action.DependsOn; deps != nil {
+			for _, dep := range append(append([]string{}, deps.Failed...), deps.Completed...) {
+				awaited[dep] = struct{}{}
+			}
+
+			for _, dep := range deps.Running {
+				exported[dep] = struct{}{}
+			}
+		}
+
+		if action.ActionType == v1alpha1.ActionDelete &&
+			structure.ContainsStrings(scenario.Status.ScheduledJobs, action.Name) {
+			for _, job := range action.Delete.Jobs {
+				deleted[job] = struct{}{}
+			}
+		}
+	}
+
+	for _, member := range members {
+		_, isAwaited := awaited[member]
+		_, isExported := exported[member]
+		_, isDeleted := deleted[member]
+
+		switch {
+		case structure.ContainsStrings(scenario.Status.SkippedJobs, member):
+			continue
+
+		case !structure.ContainsStrings(scenario.Status.ScheduledJobs, member):
+			return v1alpha1.PhaseRunning, fmt.Sprintf("action '%s' is not yet scheduled", member)
+
+		case r.view.IsFailed(member):
+			if isAwaited {
+				continue
+			}
+
+			return v1alpha1.PhaseFailed, fmt.Sprintf("action '%s' has failed", member)
+
+		case r.view.IsSuccessful(member):
+			continue
+
+		case r.view.IsRunning(member) && isExported:
+			continue
+
+		case isDeleted:
+			continue
+
+		default:
+			return v1alpha1.PhaseRunning, fmt.Sprintf("action '%s' is in progress", member)
+		}
+	}
+
+	return v1alpha1.PhaseSuccess, fmt.Sprintf("'%d' actions are completed", len(members))
+}
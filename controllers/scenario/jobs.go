@@ -19,12 +19,15 @@ package scenario
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	chaosutils "github.com/carv-ics-forth/frisbee/controllers/chaos/utils"
 	"github.com/carv-ics-forth/frisbee/controllers/common"
 	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/carv-ics-forth/frisbee/pkg/process"
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -70,6 +73,33 @@ func (r *Controller) RunAction(ctx context.Context, scenario *v1alpha1.Scenario,
 		// Some jobs are virtual and do not require something to be created.
 		return nil
 
+	case v1alpha1.ActionScenario:
+		return r.include(ctx, scenario, action)
+
+	case v1alpha1.ActionWait:
+		return r.wait(ctx, scenario, action)
+
+	case v1alpha1.ActionApply:
+		if err := r.apply(ctx, scenario, action); err != nil {
+			return errors.Wrapf(err, "apply action '%s' has failed", action.Name)
+		}
+
+		return nil
+
+	case v1alpha1.ActionHelm:
+		if err := r.helm(ctx, scenario, action); err != nil {
+			return errors.Wrapf(err, "helm action '%s' has failed", action.Name)
+		}
+
+		return nil
+
+	case v1alpha1.ActionScale:
+		if err := r.scale(ctx, scenario, action); err != nil {
+			return errors.Wrapf(err, "scale action '%s' has failed", action.Name)
+		}
+
+		return nil
+
 	default:
 		panic("should never happen")
 	}
@@ -191,6 +221,22 @@ func (r *Controller) call(scenario *v1alpha1.Scenario, action v1alpha1.Action) *
 	return &job
 }
 
+// placeholder returns a virtual job for actions whose lifecycle is resolved by the scenario controller.
+func (r *Controller) placeholder(scenario *v1alpha1.Scenario, action v1alpha1.Action) *v1alpha1.VirtualObject {
+	var job v1alpha1.VirtualObject
+
+	// Metadata
+	job.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("VirtualObject"))
+	job.SetNamespace(scenario.GetNamespace())
+	job.SetName(action.Name)
+
+	v1alpha1.SetScenarioLabel(&job.ObjectMeta, scenario.GetName())
+	v1alpha1.SetActionLabel(&job.ObjectMeta, action.Name)
+	v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+	return &job
+}
+
 func (r *Controller) delete(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
 	r.Info("-> Delete", "obj", action.Name, "targets", action.Delete.Jobs)
 	defer r.Info("<- Delete", "obj", action.Name, "targets", action.Delete.Jobs)
@@ -208,6 +254,11 @@ func (r *Controller) delete(ctx context.Context, scenario *v1alpha1.Scenario, ac
 
 			continue
 
+		case structure.ContainsStrings(scenario.Status.SkippedJobs, refJob):
+			r.Logger.Info("Ignore skipped job", "job", refJob)
+
+			continue
+
 		case r.view.IsPending(refJob):
 			job := r.view.GetPendingJobs(refJob)[0]
 
@@ -225,6 +276,12 @@ func (r *Controller) delete(ctx context.Context, scenario *v1alpha1.Scenario, ac
 			}
 
 			jobsToDelete = append(jobsToDelete, job)
+		case scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed):
+			// finally actions may refer to jobs that have already been removed.
+			r.Logger.Info("Ignore removed job", "job", refJob)
+
+			continue
+
 		default:
 			return errors.Errorf("service '%s' is not yet scheduled. Check your conditions", refJob)
 		}
@@ -240,6 +297,10 @@ func (r *Controller) delete(ctx context.Context, scenario *v1alpha1.Scenario, ac
 	// However, since there is no dedicated controller, we need to create a virtual object that represents
 	// the Delete action.
 	deleteActionName := action.Name
+
+	// the callback may run in the background, while the reconciliation cycle keeps updating the scenario.
+	parent := scenario.DeepCopy()
+
 	return lifecycle.CreateVirtualJob(ctx, r, scenario, deleteActionName, func(_ *v1alpha1.VirtualObject) error {
 		for i := range jobsToDelete {
 			job := jobsToDelete[i]
@@ -251,7 +312,7 @@ func (r *Controller) delete(ctx context.Context, scenario *v1alpha1.Scenario, ac
 			// For the entry we use a descriptive name that makes it easy to follow the deletion flow from the cli.
 			jobToDelete := fmt.Sprintf("%s-%s", action.Name, job.GetName())
 
-			err := lifecycle.CreateVirtualJob(ctx, r, scenario, jobToDelete, func(_ *v1alpha1.VirtualObject) error {
+			err := lifecycle.CreateVirtualJob(ctx, r, parent, jobToDelete, func(_ *v1alpha1.VirtualObject) error {
 				common.Delete(ctx, r, job)
 
 				return nil
@@ -264,3 +325,47 @@ func (r *Controller) delete(ctx context.Context, scenario *v1alpha1.Scenario, ac
 		return nil
 	})
 }
+
+// helmBinary is the helm executable, as found in the PATH of the controller.
+const helmBinary = "helm"
+
+// helmGracePeriod is the time given to helm, beyond the timeout of the release, before it is killed.
+// Helm is expected to respect its own timeout, and the grace period only guards against hanging processes.
+const helmGracePeriod = time.Minute
+
+func (r *Controller) helm(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	release := action.Helm
+
+	r.Info("-> Helm", "obj", action.Name, "operation", release.GetOperation(), "release", release.Release)
+	defer r.Info("<- Helm", "obj", action.Name, "operation", release.GetOperation(), "release", release.Release)
+
+	// Context of Helm Action
+	//
+	// The release is operated by the helm binary of the controller, which waits until the resources
+	// of the release are ready. Until then, the virtual job is running.
+	namespace := scenario.GetNamespace()
+
+	return lifecycle.CreateVirtualJob(ctx, r, scenario, action.Name, func(vobj *v1alpha1.VirtualObject) error {
+		vobj.Status.Lifecycle = v1alpha1.Lifecycle{
+			Phase:   v1alpha1.PhaseRunning,
+			Reason:  "HelmInProgress",
+			Message: fmt.Sprintf("%s release '%s'", release.GetOperation(), release.Release),
+		}
+
+		if err := common.UpdateStatus(ctx, r, vobj); err != nil {
+			return errors.Wrapf(err, "cannot update status")
+		}
+
+		execCtx, cancel := context.WithTimeout(ctx, release.GetTimeout()+helmGracePeriod)
+		defer cancel()
+
+		out, err := process.ExecuteContext(execCtx, helmBinary, release.Args(namespace)...)
+		if err != nil {
+			return errors.Wrapf(err, "%s release '%s'", release.GetOperation(), release.Release)
+		}
+
+		r.Info("Helm", "obj", action.Name, "output", string(out))
+
+		return nil
+	})
+}
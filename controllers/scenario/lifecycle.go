@@ -18,6 +18,8 @@ package scenario
 
 import (
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/carv-ics-forth/frisbee/pkg/expressions"
@@ -25,6 +27,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // getActionOrDie returns the spec of the referenced action.
@@ -49,9 +52,10 @@ func (r *Controller) updateLifecycle(scenario *v1alpha1.Scenario) bool {
 		action := getActionOrDie(scenario, actionName)
 
 		if !action.Assert.IsZero() {
-			eval := expressions.Condition{Expr: action.Assert}
+			eval := expressions.Condition{Expr: action.Assert, Env: r.GetEnvironment()}
 
-			if !eval.IsTrue(r.view, scenario) {
+			// inconclusive assertions (e.g, PromQL queries without data) are re-evaluated on the next cycle.
+			if !eval.IsTrue(r.view, scenario) && !eval.Inconclusive {
 				scenario.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
 				scenario.Status.Lifecycle.Reason = "AssertError"
 				scenario.Status.Lifecycle.Message = fmt.Sprintf("action '%s' failed due to:'%s'", action.Name, eval.Info)
@@ -68,8 +72,335 @@ func (r *Controller) updateLifecycle(scenario *v1alpha1.Scenario) bool {
 		}
 	}
 
+	// Step 3. Check if the scenario, or any of the active actions, has exceeded its deadline.
+	for _, d := range r.activeDeadlines(scenario) {
+		if r.Now().After(d.deadline) {
+			var msg string
+
+			if d.action == "" {
+				msg = fmt.Sprintf("scenario exceeded its deadline of '%s'", d.limit)
+			} else {
+				msg = fmt.Sprintf("action '%s' exceeded its deadline of '%s'", d.action, d.limit)
+			}
+
+			scenario.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
+			scenario.Status.Lifecycle.Reason = v1alpha1.ReasonDeadlineExceeded
+			scenario.Status.Lifecycle.Message = msg
+
+			meta.SetStatusCondition(&scenario.Status.Lifecycle.Conditions, metav1.Condition{
+				Type:    v1alpha1.ConditionDeadlineExceeded.String(),
+				Status:  metav1.ConditionTrue,
+				Reason:  v1alpha1.ReasonDeadlineExceeded,
+				Message: msg,
+			})
+
+			return true
+		}
+	}
+
+	// Step 3.1. Check if the verdict expressions declare the outcome of the scenario.
+	if r.declareVerdict(scenario) {
+		return true
+	}
+
 	// Step 4. Check if scheduling goes as expected.
-	totalJobs := len(scenario.Spec.Actions)
+	// Skipped jobs will never run, and therefore they are not expected to complete.
+	totalJobs := scenario.Spec.NumExpectedJobs(&scenario.Status)
+
+	// Failures of jobs that are awaited by Failed or Completed dependencies are expected, and therefore tolerated.
+	lifecycleChanged := lifecycle.GroupedJobs(totalJobs, r.view, &scenario.Status.Lifecycle, r.expectedFailures(scenario))
+
+	// Step 5. Record the transitions of the scheduled jobs.
+	transitionsChanged := r.recordTransitions(scenario)
+
+	// Step 6. Record the variables captured by the scheduled calls.
+	variablesChanged := r.recordVariables(scenario)
+
+	// Step 7. Record the progress of the scheduled jobs into the timeline.
+	timelineChanged := r.recordTimeline(scenario)
+
+	return lifecycleChanged || transitionsChanged || variablesChanged || timelineChanged
+}
+
+// declareVerdict evaluates the FailWhen and SuccessWhen expressions of the scenario, in that order.
+// If an expression is met, the scenario transitions to the respective phase, and the deciding expression is
+// recorded in the status. The remaining jobs are removed once the scenario reaches the terminal phase.
+// It returns true if the outcome is declared.
+func (r *Controller) declareVerdict(scenario *v1alpha1.Scenario) bool {
+	verdicts := []struct {
+		rule      string
+		expr      *v1alpha1.ConditionalExpr
+		phase     v1alpha1.Phase
+		condition v1alpha1.ConditionType
+	}{
+		{v1alpha1.VerdictFailWhen, scenario.Spec.FailWhen, v1alpha1.PhaseFailed, v1alpha1.ConditionAssertionError},
+		{v1alpha1.VerdictSuccessWhen, scenario.Spec.SuccessWhen, v1alpha1.PhaseSuccess, v1alpha1.ConditionAllJobsAreCompleted},
+	}
+
+	for _, verdict := range verdicts {
+		if verdict.expr.IsZero() {
+			continue
+		}
+
+		met, info := r.verdictIsMet(scenario, verdict.expr)
+		if !met {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s is met. %s", verdict.rule, info)
+
+		scenario.Status.Lifecycle.Phase = verdict.phase
+		scenario.Status.Lifecycle.Reason = verdict.rule
+		scenario.Status.Lifecycle.Message = msg
+
+		meta.SetStatusCondition(&scenario.Status.Lifecycle.Conditions, metav1.Condition{
+			Type:    verdict.condition.String(),
+			Status:  metav1.ConditionTrue,
+			Reason:  verdict.rule,
+			Message: msg,
+		})
+
+		scenario.Status.Verdict = &v1alpha1.VerdictStatus{
+			Rule: verdict.rule,
+			Expr: *verdict.expr,
+			Time: r.MetaNow(),
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// verdictIsMet returns true if the verdict expression is met, along with the outcome of the evaluation.
+// Metrics expressions, including those within compositions, are met once their alert is fired.
+func (r *Controller) verdictIsMet(scenario *v1alpha1.Scenario, expr *v1alpha1.ConditionalExpr) (bool, string) {
+	eval := expressions.Condition{Expr: expr, Env: r.GetEnvironment(), MetOnAlert: true}
+
+	return eval.IsTrue(r.view, scenario), eval.Info
+}
+
+// nextEvaluation returns the time for re-evaluating the polled expressions of the verdict, of the assertions
+// of the scheduled actions, and of the running waits. If there are no such expressions, it returns zero.
+func (r *Controller) nextEvaluation(scenario *v1alpha1.Scenario) time.Time {
+	exprs := []*v1alpha1.ConditionalExpr{scenario.Spec.SuccessWhen, scenario.Spec.FailWhen}
+
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		action := getActionOrDie(scenario, actionName)
+
+		exprs = append(exprs, action.Assert)
+
+		if action.ActionType == v1alpha1.ActionWait && r.view.IsRunning(actionName) {
+			exprs = append(exprs, action.Wait.Until)
+		}
+	}
+
+	return expressions.NextPolledEvaluation(r.Now(), exprs...)
+}
+
+// recordTransitions records the time a scheduled job has transitioned to the Running, Success, or Failed phase.
+// The time is taken from the lifecycle conditions of the job. If the job does not record its transitions
+// (e.g, external resources), the time it is first observed in the phase is used instead.
+// Since the timestamps are persisted in the status, they survive controller restarts.
+// It returns true if a new transition is recorded.
+func (r *Controller) recordTransitions(scenario *v1alpha1.Scenario) bool {
+	now := r.MetaNow()
+	anyChanged := false
+
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		transitions := scenario.Status.Transitions[actionName]
+		changed := false
+
+		// A completed job must have been running, even if we have not observed it.
+		if transitions.Running == nil &&
+			(r.view.IsRunning(actionName) || r.view.IsSuccessful(actionName) || r.view.IsFailed(actionName)) {
+			transitions.Running = r.transitionTime(actionName, v1alpha1.PhaseRunning, now)
+			changed = true
+		}
+
+		if transitions.Success == nil && r.view.IsSuccessful(actionName) {
+			transitions.Success = r.transitionTime(actionName, v1alpha1.PhaseSuccess, now)
+			changed = true
+		}
+
+		if transitions.Failed == nil && r.view.IsFailed(actionName) {
+			transitions.Failed = r.transitionTime(actionName, v1alpha1.PhaseFailed, now)
+			changed = true
+		}
+
+		if changed {
+			if scenario.Status.Transitions == nil {
+				scenario.Status.Transitions = make(map[string]v1alpha1.ActionTransitions)
+			}
+
+			scenario.Status.Transitions[actionName] = transitions
+			anyChanged = true
+		}
+	}
+
+	return anyChanged
+}
+
+// transitionTime returns the time the job has transitioned to the given phase, according to its lifecycle.
+// If the transition is not recorded in the lifecycle, it returns the given fallback.
+func (r *Controller) transitionTime(jobName string, phase v1alpha1.Phase, fallback metav1.Time) *metav1.Time {
+	jobs := r.view.GetRunningJobs(jobName)
+	jobs = append(jobs, r.view.GetSuccessfulJobs(jobName)...)
+	jobs = append(jobs, r.view.GetFailedJobs(jobName)...)
+
+	for _, job := range jobs {
+		if statusAware, ok := job.(v1alpha1.ReconcileStatusAware); ok {
+			if at := lifecycle.TransitionTime(statusAware.GetReconcileStatus(), phase); at != nil {
+				return at
+			}
+		}
+	}
+
+	return &fallback
+}
+
+// recordTimeline updates the execution records of the scheduled jobs, with the transitions and the latest
+// phase of every job. The records of completed jobs are final, and are not affected by the deletion of the jobs.
+// It returns true if any record is changed.
+func (r *Controller) recordTimeline(scenario *v1alpha1.Scenario) bool {
+	changed := false
+
+	for i := range scenario.Status.Timeline {
+		record := &scenario.Status.Timeline[i]
+
+		if record.CompletedAt != nil {
+			continue
+		}
+
+		updated := *record
+
+		transitions := scenario.Status.Transitions[record.Name]
+
+		updated.RunningAt = transitions.Running
+
+		switch {
+		case transitions.Failed != nil:
+			updated.CompletedAt = transitions.Failed
+		case transitions.Success != nil:
+			updated.CompletedAt = transitions.Success
+		}
+
+		var job client.Object
+
+		switch {
+		case r.view.IsFailed(record.Name):
+			updated.Phase, job = v1alpha1.PhaseFailed, r.view.GetFailedJobs(record.Name)[0]
+		case r.view.IsSuccessful(record.Name):
+			updated.Phase, job = v1alpha1.PhaseSuccess, r.view.GetSuccessfulJobs(record.Name)[0]
+		case r.view.IsRunning(record.Name):
+			updated.Phase, job = v1alpha1.PhaseRunning, r.view.GetRunningJobs(record.Name)[0]
+		case r.view.IsPending(record.Name):
+			updated.Phase, job = v1alpha1.PhasePending, r.view.GetPendingJobs(record.Name)[0]
+		}
+
+		// deleted jobs retain their latest observed state.
+		if job != nil {
+			if statusAware, ok := job.(v1alpha1.ReconcileStatusAware); ok {
+				updated.Reason = statusAware.GetReconcileStatus().Reason
+			}
+
+			updated.Children = countChildren(scenario, record.Name, job)
+		}
+
+		if !reflect.DeepEqual(updated, *record) {
+			*record = updated
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// countChildren returns the number of jobs created by the job of an action.
+func countChildren(scenario *v1alpha1.Scenario, actionName string, job client.Object) int {
+	if members, isInclude := scenario.Status.Includes[actionName]; isInclude {
+		return len(members)
+	}
+
+	// ScheduledJobs is the index of the latest scheduled job.
+	switch job := job.(type) {
+	case *v1alpha1.Service, *v1alpha1.Chaos:
+		return 1
+	case *v1alpha1.Cluster:
+		return job.Status.ScheduledJobs + 1
+	case *v1alpha1.Cascade:
+		return job.Status.ScheduledJobs + 1
+	case *v1alpha1.Call:
+		return job.Status.ScheduledJobs + 1
+	default:
+		return 0
+	}
+}
+
+// recordVariables copies the values captured by the scheduled calls into the variables of the scenario.
+// If multiple calls capture the same variable, the latest scheduled call wins.
+// It returns true if any variable is changed.
+func (r *Controller) recordVariables(scenario *v1alpha1.Scenario) bool {
+	captured := make(map[string]string)
+
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		var jobs []client.Object
+
+		jobs = append(jobs, r.view.GetRunningJobs(actionName)...)
+		jobs = append(jobs, r.view.GetSuccessfulJobs(actionName)...)
+		jobs = append(jobs, r.view.GetFailedJobs(actionName)...)
+
+		for _, job := range jobs {
+			if call, ok := job.(*v1alpha1.Call); ok {
+				for name, value := range call.Status.Captured {
+					captured[name] = value
+				}
+			}
+		}
+	}
+
+	changed := false
+
+	for name, value := range captured {
+		if current, exists := scenario.Status.Variables[name]; exists && current == value {
+			continue
+		}
+
+		if scenario.Status.Variables == nil {
+			scenario.Status.Variables = make(map[string]string)
+		}
+
+		scenario.Status.Variables[name] = value
+		changed = true
+	}
+
+	return changed
+}
+
+// expectedFailures returns a toleration for the failed jobs that are awaited by Failed or Completed dependencies.
+// If no action awaits failures, it returns nil.
+func (r *Controller) expectedFailures(scenario *v1alpha1.Scenario) *v1alpha1.TolerateSpec {
+	awaited := make(map[string]struct{})
+
+	for _, action := range scenario.Spec.Actions {
+		if deps := action.DependsOn; deps != nil {
+			for _, dep := range append(append([]string{}, deps.Failed...), deps.Completed...) {
+				awaited[dep] = struct{}{}
+			}
+		}
+	}
+
+	if len(awaited) == 0 {
+		return nil
+	}
+
+	var tolerate v1alpha1.TolerateSpec
+
+	for job := range awaited {
+		if r.view.IsFailed(job) {
+			tolerate.FailedJobs++
+		}
+	}
 
-	return lifecycle.GroupedJobs(totalJobs, r.view, &scenario.Status.Lifecycle, nil)
+	return &tolerate
 }
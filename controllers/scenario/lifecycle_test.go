@@ -0,0 +1,271 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecordTransitions(t *testing.T) {
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Minute)
+
+	condition := func(condType v1alpha1.ConditionType, at time.Duration) metav1.Condition {
+		return metav1.Condition{
+			Type:               condType.String(),
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(start.Add(at)),
+		}
+	}
+
+	view := newView(map[string]v1alpha1.Phase{
+		"server":  v1alpha1.PhaseRunning,
+		"pending": v1alpha1.PhasePending,
+	})
+
+	var clients v1alpha1.Cluster
+
+	clients.SetName("clients")
+	v1alpha1.SetComponentLabel(&clients.ObjectMeta, v1alpha1.ComponentSUT)
+
+	clients.Status.Lifecycle = v1alpha1.Lifecycle{
+		Phase: v1alpha1.PhaseSuccess,
+		Conditions: []metav1.Condition{
+			condition(v1alpha1.ConditionAllJobsAreScheduled, time.Minute),
+			condition(v1alpha1.ConditionAllJobsAreCompleted, 3*time.Minute),
+		},
+	}
+
+	view.Classify(clients.GetName(), &clients)
+
+	var scenario v1alpha1.Scenario
+
+	scenario.Status.ScheduledJobs = []string{"server", "clients", "pending"}
+
+	r := &Controller{Logger: logr.Discard(), Environment: fakeEnvironment(now), view: view}
+
+	if !r.recordTransitions(&scenario) {
+		t.Fatal("recordTransitions() = false, want true")
+	}
+
+	nowMeta := metav1.NewTime(now)
+	expected := map[string]v1alpha1.ActionTransitions{
+		// the service does not record its transitions, so the time it is observed is used.
+		"server": {Running: &nowMeta},
+		"clients": {
+			Running: &metav1.Time{Time: start.Add(time.Minute)},
+			Success: &metav1.Time{Time: start.Add(3 * time.Minute)},
+		},
+	}
+
+	if len(scenario.Status.Transitions) != len(expected) {
+		t.Fatalf("Transitions = %v, want %v", scenario.Status.Transitions, expected)
+	}
+
+	for name, want := range expected {
+		got := scenario.Status.Transitions[name]
+
+		for _, phase := range []v1alpha1.Phase{v1alpha1.PhaseRunning, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed} {
+			if !got.Get(phase).Equal(want.Get(phase)) {
+				t.Errorf("'%s' %s transition = %v, want %v", name, phase, got.Get(phase), want.Get(phase))
+			}
+		}
+	}
+
+	if r.recordTransitions(&scenario) {
+		t.Error("recordTransitions() = true on unchanged jobs, want false")
+	}
+}
+
+func TestRecordTimeline_Children(t *testing.T) {
+	view := newView(map[string]v1alpha1.Phase{"server": v1alpha1.PhaseRunning})
+
+	// cluster records the index of the latest scheduled job, starting from -1.
+	cluster := func(name string, phase v1alpha1.Phase, scheduled int) *v1alpha1.Cluster {
+		var job v1alpha1.Cluster
+
+		job.SetName(name)
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+		job.Status.Lifecycle.Phase = phase
+		job.Status.QueuedJobs = make([]v1alpha1.ServiceSpec, 3)
+		job.Status.ScheduledJobs = scheduled
+
+		return &job
+	}
+
+	view.Classify("clients", cluster("clients", v1alpha1.PhaseRunning, 2))
+	view.Classify("partial", cluster("partial", v1alpha1.PhasePending, 0))
+	view.Classify("queued", cluster("queued", v1alpha1.PhasePending, -1))
+
+	var faults v1alpha1.Cascade
+
+	faults.SetName("faults")
+	v1alpha1.SetComponentLabel(&faults.ObjectMeta, v1alpha1.ComponentSUT)
+	faults.Status.Lifecycle.Phase = v1alpha1.PhaseSuccess
+	faults.Status.ScheduledJobs = 1
+
+	view.Classify(faults.GetName(), &faults)
+
+	var fragment v1alpha1.VirtualObject
+
+	fragment.SetName("fragment")
+	v1alpha1.SetComponentLabel(&fragment.ObjectMeta, v1alpha1.ComponentSUT)
+	fragment.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+
+	view.Classify(fragment.GetName(), &fragment)
+
+	var scenario v1alpha1.Scenario
+
+	scenario.Status.Includes = map[string][]string{"fragment": {"fragment-a", "fragment-b"}}
+
+	want := map[string]int{"server": 1, "clients": 3, "partial": 1, "queued": 0, "faults": 2, "fragment": 2}
+
+	for _, name := range []string{"server", "clients", "partial", "queued", "faults", "fragment"} {
+		scenario.Status.Timeline = append(scenario.Status.Timeline, v1alpha1.ActionRecord{Name: name})
+	}
+
+	r := &Controller{Logger: logr.Discard(), view: view}
+
+	if !r.recordTimeline(&scenario) {
+		t.Fatal("recordTimeline() = false, want true")
+	}
+
+	for _, record := range scenario.Status.Timeline {
+		if record.Children != want[record.Name] {
+			t.Errorf("'%s' children = %d, want %d", record.Name, record.Children, want[record.Name])
+		}
+	}
+}
+
+func TestDeclareVerdict_MetricsLeaf(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	metrics := v1alpha1.ConditionalExpr{Metrics: "A2EjFbsMk/86/Average"}
+
+	// the metrics leaf of the composition is met once its alert is fired, as the bare metrics expression.
+	tests := []struct {
+		name     string
+		failWhen *v1alpha1.ConditionalExpr
+	}{
+		{name: "bare", failWhen: &metrics},
+		{name: "allOf", failWhen: &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{metrics}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Controller{Logger: logr.Discard(), Environment: fakeEnvironment(now), view: newView(nil)}
+
+			var scenario v1alpha1.Scenario
+
+			scenario.Spec.FailWhen = tt.failWhen
+			scenario.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+
+			if r.declareVerdict(&scenario) {
+				t.Fatalf("declareVerdict() = true without a fired alert. Message: %s", scenario.Status.Lifecycle.Message)
+			}
+
+			scenario.SetAnnotations(map[string]string{
+				"alert.frisbee.dev/name":      "default/Scenario/scenario",
+				"alert.frisbee.dev/state":     "alerting",
+				"alert.frisbee.dev/timestamp": now.Format(time.RFC3339),
+				"alert.frisbee.dev/details":   "{}",
+			})
+
+			if !r.declareVerdict(&scenario) {
+				t.Fatal("declareVerdict() = false with a fired alert, want true")
+			}
+
+			if scenario.Status.Lifecycle.Phase != v1alpha1.PhaseFailed {
+				t.Errorf("Phase = %s, want %s", scenario.Status.Lifecycle.Phase, v1alpha1.PhaseFailed)
+			}
+		})
+	}
+}
+
+func TestMetricsGuard_WithAssertion(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	newScenario := func(assert string) *v1alpha1.Scenario {
+		spec := `{"actions": [
+			{"action": "Service", "name": "servers", "service": {"templateRef": "server"}, "assert": ` + assert + `},
+			{"action": "Delete", "name": "guarded", "depends": {"running": ["servers"]},
+				"when": {"metrics": "avg() of query(wpFnYRwGk/2/bitrate, 1m, now) is above(200)"},
+				"delete": {"jobs": ["servers"]}}
+			], "successWhen": {"state": "{{.NumRunningJobs}} >= 1"}}`
+
+		var scenario v1alpha1.Scenario
+
+		scenario.SetName("scenario")
+
+		if err := json.Unmarshal([]byte(spec), &scenario.Spec); err != nil {
+			t.Fatalf("cannot decode spec: %v", err)
+		}
+
+		return &scenario
+	}
+
+	// the alert of the guard is set on the scenario, and would be mistaken for the alert of the assertion.
+	if _, err := newScenario(`{"metrics": "avg() of query(wpFnYRwGk/2/bitrate, 1m, now) is below(100)"}`).ValidateCreate(); err == nil {
+		t.Fatal("ValidateCreate() of metrics guard with metrics assertion = nil, want error")
+	}
+
+	scenario := newScenario(`{"state": "{{.IsRunning \"servers\"}} == true"}`)
+
+	if _, err := scenario.ValidateCreate(); err != nil {
+		t.Fatalf("ValidateCreate() of metrics guard with state assertion = %v, want nil", err)
+	}
+
+	scenario.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+	scenario.Status.ScheduledJobs = []string{"servers"}
+	scenario.SetAnnotations(map[string]string{
+		"alert.frisbee.dev/name":      "default/Scenario/scenario",
+		"alert.frisbee.dev/state":     "alerting",
+		"alert.frisbee.dev/timestamp": now.Format(time.RFC3339),
+		"alert.frisbee.dev/details":   "{}",
+	})
+
+	r := &Controller{
+		Logger:      logr.Discard(),
+		Environment: fakeEnvironment(now),
+		view:        newView(map[string]v1alpha1.Phase{"servers": v1alpha1.PhaseRunning}),
+	}
+
+	// the fired alert skips the guarded action.
+	runNext, skipNext, _, err := r.NextJobs(scenario)
+	if err != nil {
+		t.Fatalf("NextJobs() error = %v", err)
+	}
+
+	if len(runNext) != 0 || !reflect.DeepEqual(skipNext, []string{"guarded"}) {
+		t.Errorf("NextJobs() = %v, %v, want guarded to be skipped", runNext, skipNext)
+	}
+
+	// the fired alert does not fail the assertion.
+	r.updateLifecycle(scenario)
+
+	if scenario.Status.Lifecycle.Phase.Is(v1alpha1.PhaseFailed) {
+		t.Errorf("Phase = %s, want not failed. Message: %s", scenario.Status.Lifecycle.Phase, scenario.Status.Lifecycle.Message)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// postConditionsInProgress returns true if the scenario (or the running trial) has reached a terminal phase,
+// and the post-conditions are not yet evaluated.
+func postConditionsInProgress(scenario *v1alpha1.Scenario) bool {
+	if len(scenario.Spec.PostConditions) == 0 {
+		return false
+	}
+
+	if !scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		return false
+	}
+
+	return scenario.Status.PostConditions == nil
+}
+
+// PostConditions evaluates the post-conditions once, and records the outcome of every post-condition in the status.
+// If any post-condition is not met, a successful scenario is declared failed. Failed scenarios remain failed.
+func (r *Controller) PostConditions(ctx context.Context, req ctrl.Request, scenario *v1alpha1.Scenario) (ctrl.Result, error) {
+	scenario.Status.PostConditions = r.evaluatePostConditions(ctx, scenario)
+
+	var failed []string
+
+	for _, result := range scenario.Status.PostConditions {
+		if !result.Passed {
+			failed = append(failed, result.Name)
+		}
+	}
+
+	if len(failed) == 0 {
+		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "PostConditions",
+			fmt.Sprintf("Post-conditions are met: '%d/%d'", len(scenario.Status.PostConditions), len(scenario.Spec.PostConditions)))
+	} else {
+		msg := fmt.Sprintf("Post-conditions are not met: '%s'", strings.Join(failed, ","))
+
+		if scenario.Status.Phase.Is(v1alpha1.PhaseSuccess) {
+			scenario.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
+			scenario.Status.Lifecycle.Reason = "PostConditionFailed"
+			scenario.Status.Lifecycle.Message = msg
+
+			meta.SetStatusCondition(&scenario.Status.Lifecycle.Conditions, metav1.Condition{
+				Type:    v1alpha1.ConditionAllJobsAreCompleted.String(),
+				Status:  metav1.ConditionFalse,
+				Reason:  "PostConditionFailed",
+				Message: msg,
+			})
+
+			meta.SetStatusCondition(&scenario.Status.Lifecycle.Conditions, metav1.Condition{
+				Type:    v1alpha1.ConditionAssertionError.String(),
+				Status:  metav1.ConditionTrue,
+				Reason:  "PostConditionFailed",
+				Message: msg,
+			})
+		}
+
+		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeWarning, "PostConditions", msg)
+	}
+
+	if err := common.UpdateStatus(ctx, r, scenario); err != nil {
+		return common.RequeueAfter(r, req, time.Second)
+	}
+
+	return common.Stop(r, req)
+}
+
+// evaluatePostConditions evaluates the state post-conditions over the final state of the jobs, and the metrics
+// post-conditions over the duration of the scenario (or of the running trial). Post-conditions that cannot be
+// evaluated are not met, and the errors are reported in their message.
+func (r *Controller) evaluatePostConditions(ctx context.Context, scenario *v1alpha1.Scenario) []v1alpha1.PostConditionResult {
+	from, to := startOf(scenario).Time, r.Now()
+
+	var (
+		connected    bool
+		telemetryErr error
+	)
+
+	results := make([]v1alpha1.PostConditionResult, 0, len(scenario.Spec.PostConditions))
+
+	for _, postCondition := range scenario.Spec.PostConditions {
+		result := v1alpha1.PostConditionResult{Name: postCondition.Name}
+
+		if postCondition.State != "" {
+			passed, err := r.evaluateState(scenario, postCondition)
+			if err != nil {
+				result.Message = err.Error()
+			} else {
+				result.Passed = passed
+				result.Message = fmt.Sprintf("State '%s' is %t", postCondition.State, passed)
+			}
+
+			results = append(results, result)
+
+			continue
+		}
+
+		// connect to Grafana once, on the first metrics post-condition.
+		if !connected {
+			connected = true
+
+			if scenario.Status.GrafanaEndpoint == "" {
+				telemetryErr = errors.New("telemetry is disabled")
+			} else if err := r.connectToGrafana(ctx, scenario, r.alertingProxy); err != nil {
+				telemetryErr = errors.Wrapf(err, "connect to grafana")
+			}
+		}
+
+		if telemetryErr != nil {
+			result.Message = telemetryErr.Error()
+			results = append(results, result)
+
+			continue
+		}
+
+		value, err := r.queryValue(ctx, scenario, postCondition.Value, from, to)
+		if err != nil {
+			result.Message = err.Error()
+			results = append(results, result)
+
+			continue
+		}
+
+		result.Value = &value
+
+		passed, err := postCondition.Is.Contains(value)
+		if err != nil {
+			result.Message = err.Error()
+		} else {
+			result.Passed = passed
+			result.Message = fmt.Sprintf("Value '%g' in '%s' is %t", value, postCondition.Is, passed)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// evaluateState evaluates the state expression over the current view, with the captured variables.
+func (r *Controller) evaluateState(scenario *v1alpha1.Scenario, postCondition v1alpha1.PostCondition) (bool, error) {
+	return postCondition.State.Valuate(postCondition.Syntax, r.view, scenario.GetVariables())
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// scalePollInterval is the interval for tracking the progress of the scaled clusters. The scenario is only notified
+// about the phase changes of the clusters, and therefore their progress is periodically polled.
+const scalePollInterval = time.Second
+
+// scaleInstancesKey is the key of the virtual job's data that holds the target instances of the cluster.
+const scaleInstancesKey = "instances"
+
+func (r *Controller) scale(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	r.Info("-> Scale", "obj", action.Name, "target", action.Scale.Cluster, "by", action.Scale.By.String())
+	defer r.Info("<- Scale", "obj", action.Name, "target", action.Scale.Cluster, "by", action.Scale.By.String())
+
+	job := r.placeholder(scenario, action)
+
+	if err := common.Create(ctx, r, scenario, job); err != nil {
+		return errors.Wrapf(err, "cannot create scale")
+	}
+
+	// Context of Scale Action
+	//
+	// The instances of the cluster are changed by updating its spec, and the cluster controller
+	// aligns the services to the new instances. The scale is completed once the cluster has scheduled
+	// all of its instances, as tracked by updateScales.
+	key := client.ObjectKey{Namespace: scenario.GetNamespace(), Name: action.Scale.Cluster}
+
+	var instances int
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var cluster v1alpha1.Cluster
+
+		if err := r.GetClient().Get(ctx, key, &cluster); err != nil {
+			return err
+		}
+
+		if !cluster.Status.Phase.Is(v1alpha1.PhasePending, v1alpha1.PhaseRunning) {
+			return errors.Errorf("cluster '%s' is not active. Phase: '%s'", key, cluster.Status.Phase)
+		}
+
+		next, err := action.Scale.Instances(cluster.Spec.MaxInstances)
+		if err != nil {
+			return err
+		}
+
+		instances = next
+
+		cluster.Spec.MaxInstances = instances
+		cluster.Spec.RemovalOrder = action.Scale.Order
+
+		return r.GetClient().Update(ctx, &cluster)
+	}); err != nil {
+		// a failed scale is reflected in the virtual job, and is captured by the lifecycle of the scenario.
+		job.Status.Lifecycle = v1alpha1.Lifecycle{
+			Phase:   v1alpha1.PhaseFailed,
+			Reason:  "ScaleFailed",
+			Message: errors.Wrapf(err, "cannot scale cluster '%s'", key).Error(),
+		}
+
+		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeWarning, "ScaleFailed", job.Status.Message)
+
+		return common.UpdateStatus(ctx, r, job)
+	}
+
+	job.Status.Data = map[string]string{scaleInstancesKey: strconv.Itoa(instances)}
+	job.Status.Lifecycle = v1alpha1.Lifecycle{
+		Phase:   v1alpha1.PhaseRunning,
+		Reason:  "Scaling",
+		Message: fmt.Sprintf("scaling cluster '%s' to %d instances", key, instances),
+	}
+
+	return common.UpdateStatus(ctx, r, job)
+}
+
+// updateScales resolves the lifecycle of the virtual jobs of the scheduled scales, given the progress of the
+// scaled clusters. It returns true if any virtual job is updated.
+func (r *Controller) updateScales(ctx context.Context, scenario *v1alpha1.Scenario) (bool, error) {
+	updated := false
+
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		action := getActionOrDie(scenario, actionName)
+
+		if action.ActionType != v1alpha1.ActionScale || !r.view.IsRunning(actionName) {
+			continue
+		}
+
+		var job v1alpha1.VirtualObject
+
+		key := client.ObjectKey{Namespace: scenario.GetNamespace(), Name: actionName}
+
+		if err := r.GetClient().Get(ctx, key, &job); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+
+		phase, msg, err := r.scaleLifecycle(ctx, scenario, action.Scale, &job)
+		if err != nil {
+			return false, errors.Wrapf(err, "cannot track scale '%s'", actionName)
+		}
+
+		if job.Status.Phase == phase {
+			continue
+		}
+
+		job.Status.Lifecycle.Phase = phase
+		job.Status.Lifecycle.Reason = "Scale"
+		job.Status.Lifecycle.Message = msg
+
+		if err := common.UpdateStatus(ctx, r, &job); err != nil {
+			return false, errors.Wrapf(err, "cannot update scale '%s'", actionName)
+		}
+
+		switch phase {
+		case v1alpha1.PhaseSuccess:
+			r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "ScaleSuccess", msg)
+		case v1alpha1.PhaseFailed:
+			r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeWarning, "ScaleFailed", msg)
+		}
+
+		updated = true
+	}
+
+	return updated, nil
+}
+
+// scaleLifecycle returns the phase of a scale. The scale is running until the cluster has scheduled the target
+// instances, and it fails if the cluster fails, or is removed.
+func (r *Controller) scaleLifecycle(ctx context.Context, scenario *v1alpha1.Scenario, scale *v1alpha1.ScaleSpec,
+	job *v1alpha1.VirtualObject,
+) (v1alpha1.Phase, string, error) {
+	instances, err := strconv.Atoi(job.Status.Data[scaleInstancesKey])
+	if err != nil {
+		return v1alpha1.PhaseFailed, fmt.Sprintf("invalid target instances '%s'", job.Status.Data[scaleInstancesKey]), nil
+	}
+
+	var cluster v1alpha1.Cluster
+
+	key := client.ObjectKey{Namespace: scenario.GetNamespace(), Name: scale.Cluster}
+
+	if err := r.GetClient().Get(ctx, key, &cluster); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return "", "", err
+		}
+
+		return v1alpha1.PhaseFailed, fmt.Sprintf("cluster '%s' is removed", key), nil
+	}
+
+	if cluster.Status.Phase.Is(v1alpha1.PhaseFailed) {
+		return v1alpha1.PhaseFailed, fmt.Sprintf("cluster '%s' has failed", key), nil
+	}
+
+	scheduled := cluster.Status.ScheduledJobs+1 >= len(cluster.Status.QueuedJobs)
+
+	if cluster.Status.ExpectedJobs() != instances || !scheduled {
+		return v1alpha1.PhaseRunning, fmt.Sprintf("scaling cluster '%s' to %d instances", key, instances), nil
+	}
+
+	return v1alpha1.PhaseSuccess, fmt.Sprintf("cluster '%s' is scaled to %d instances", key, instances), nil
+}
+
+// nextScale returns the next time for polling the scaled clusters. If there are no scales in progress, it returns zero.
+func (r *Controller) nextScale(scenario *v1alpha1.Scenario) time.Time {
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		action := getActionOrDie(scenario, actionName)
+
+		if action.ActionType == v1alpha1.ActionScale && r.view.IsRunning(actionName) {
+			return r.Now().Add(scalePollInterval)
+		}
+	}
+
+	return time.Time{}
+}
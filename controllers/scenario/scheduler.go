@@ -17,9 +17,12 @@ limitations under the License.
 package scenario
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
 	"github.com/carv-ics-forth/frisbee/pkg/structure"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,11 +36,22 @@ import (
 // However, if there are no actions, the workflow will call the reconciliation cycle, and we will miss the
 // next timeout. To handle this scenario, we have to requeue the request with the given duration.
 // In this case, the given duration is the nearest expected timeout.
-func (r *Controller) NextJobs(scenario *v1alpha1.Scenario) (runNext []v1alpha1.Action, nextCycle time.Time, err error) {
+//
+// Additionally, it returns a list of actions that will never run, either because their When condition is false,
+// or because they belong to a conditional branch that was not taken, or because they wait for skipped actions
+// to be running. Skipped actions satisfy Success dependencies, so that the alternative branches can be joined.
+func (r *Controller) NextJobs(scenario *v1alpha1.Scenario) (runNext []v1alpha1.Action, skipNext []string, nextCycle time.Time, err error) {
 	timeOK := func(deps *v1alpha1.WaitSpec) bool {
 		if dur := deps.After; dur != nil {
-			cur := metav1.Now()
-			deadline := scenario.GetCreationTimestamp().Add(dur.Duration)
+			anchor := getAnchor(scenario, deps.Since)
+			if anchor == nil {
+				// the referenced transition has not yet happened.
+				// once it happens, the watchers will trigger a new reconciliation cycle.
+				return false
+			}
+
+			cur := r.MetaNow()
+			deadline := anchor.Add(dur.Duration)
 
 			// the deadline has expired.
 			// FIXME: this condition is susceptible to time skew on the machine
@@ -61,34 +75,204 @@ func (r *Controller) NextJobs(scenario *v1alpha1.Scenario) (runNext []v1alpha1.A
 	// check what actions are eligible for execution in this cycle.
 	all := scenario.Spec.Actions
 	scheduled := scenario.Status.ScheduledJobs
+	skipped := scenario.Status.SkippedJobs
 
 	for _, action := range all {
-		// ignore scheduled jobs
-		if structure.ContainsStrings(scheduled, action.Name) {
+		// ignore scheduled and skipped jobs
+		if structure.ContainsStrings(scheduled, action.Name) || structure.ContainsStrings(skipped, action.Name) {
 			continue
 		}
 
+		// an alternative branch is held until the condition of its guard is evaluated.
+		if guard := getGuardOf(scenario, action.Name); guard != "" {
+			switch {
+			case structure.ContainsStrings(scheduled, guard):
+				// the guard has been taken. the alternative branch will never run.
+				skipNext = append(skipNext, action.Name)
+
+				continue
+			case structure.ContainsStrings(skipped, guard):
+				// the guard has been skipped. proceed with the alternative branch.
+			default:
+				// the guard is not yet evaluated.
+				continue
+			}
+		}
+
 		// a job is eligible for scheduling if there are no dependencies, or if defined dependencies are satisfied.
 		deps := action.DependsOn
-		if deps == nil {
-			runNext = append(runNext, action)
-		} else {
+		if deps != nil {
+			// a job that waits for skipped jobs to be running or failed will never have its dependencies satisfied.
+			if dep := getSkippedDependency(append(append([]string{}, deps.Running...), deps.Failed...), skipped); dep != "" {
+				r.Logger.Info("Skip action due to skipped dependency", "action", action.Name, "dependency", dep)
+
+				skipNext = append(skipNext, action.Name)
+
+				continue
+			}
+
+			// a job that waits for successful jobs to fail will never have its dependencies satisfied.
+			if dep := getSuccessfulDependency(r.view, deps.Failed); dep != "" {
+				r.Logger.Info("Skip action due to successful dependency", "action", action.Name, "dependency", dep)
+
+				skipNext = append(skipNext, action.Name)
+
+				continue
+			}
+
+			// a job that is anchored to a transition that will never happen will never have its time constraint satisfied.
+			if reason := getUnreachableAnchor(r.view, scenario, deps.Since); reason != "" {
+				r.Logger.Info("Skip action due to unreachable anchor", "action", action.Name, "reason", reason)
+
+				skipNext = append(skipNext, action.Name)
+
+				continue
+			}
+
 			// check a dependent "running" is not already terminated, as it will cause the scenario
 			// to loop forever
 			for _, dep := range deps.Running {
 				if r.view.IsSuccessful(dep) || r.view.IsFailed(dep) {
 					err := errors.Errorf("action '%s' has a Running dependency on completed job '%s'", action.Name, dep)
 
-					return nil, time.Now(), err
+					return nil, nil, r.Now(), err
+				}
+			}
+
+			// skipped jobs are regarded as completed. This allows actions to join the alternative branches.
+			var success, completed []string
+
+			for _, dep := range deps.Success {
+				if !structure.ContainsStrings(skipped, dep) {
+					success = append(success, dep)
 				}
 			}
 
-			if r.view.IsSuccessful(deps.Success...) && r.view.IsRunning(deps.Running...) && timeOK(deps) {
-				// conditions are met
-				runNext = append(runNext, action)
+			for _, dep := range deps.Completed {
+				if !structure.ContainsStrings(skipped, dep) {
+					completed = append(completed, dep)
+				}
+			}
+
+			if !r.view.IsSuccessful(success...) ||
+				!r.view.IsRunning(deps.Running...) ||
+				!r.view.IsFailed(deps.Failed...) ||
+				!isCompleted(r.view, completed...) ||
+				!timeOK(deps) {
+				// conditions are not yet met
+				continue
+			}
+		}
+
+		// conditions are met. Check if the action is guarded.
+		if !action.When.IsZero() {
+			eval := expressions.Condition{Expr: action.When, Env: r.GetEnvironment()}
+
+			if !eval.IsTrue(r.view, scenario) {
+				// inconclusive conditions (e.g, PromQL queries without data) are re-evaluated on the next cycle.
+				if eval.Inconclusive {
+					nextCycle = earliest(nextCycle, expressions.NextPolledEvaluation(r.Now(), action.When))
+
+					continue
+				}
+
+				r.Logger.Info("Skip action due to false condition", "action", action.Name, "when", action.When)
+
+				skipNext = append(skipNext, action.Name)
+
+				continue
 			}
 		}
+
+		runNext = append(runNext, action)
+	}
+
+	return runNext, skipNext, nextCycle, nil
+}
+
+// getAnchor returns the time from which the time offsets are measured. That is either the beginning of the
+// scenario (or of the running trial), or the recorded transition of the referenced action.
+// If the transition has not yet happened, it returns nil.
+func getAnchor(scenario *v1alpha1.Scenario, since *v1alpha1.TransitionRef) *metav1.Time {
+	if since == nil {
+		start := startOf(scenario)
+
+		return &start
+	}
+
+	transitions, exists := scenario.Status.Transitions[since.Action]
+	if !exists {
+		return nil
+	}
+
+	return transitions.Get(since.Phase)
+}
+
+// getUnreachableAnchor returns the reason for which the referenced transition will never happen. That is, if the
+// referenced action is skipped, or if it has completed in a phase other than the referenced one.
+// If the transition has happened, or it may still happen, it returns an empty string.
+func getUnreachableAnchor(view lifecycle.ClassifierReader, scenario *v1alpha1.Scenario, since *v1alpha1.TransitionRef) string {
+	if since == nil || getAnchor(scenario, since) != nil {
+		return ""
+	}
+
+	if structure.ContainsStrings(scenario.Status.SkippedJobs, since.Action) {
+		return fmt.Sprintf("action '%s' is skipped", since.Action)
+	}
+
+	switch {
+	case since.Phase.Is(v1alpha1.PhaseSuccess) && view.IsFailed(since.Action):
+		return fmt.Sprintf("action '%s' has failed", since.Action)
+	case since.Phase.Is(v1alpha1.PhaseFailed) && view.IsSuccessful(since.Action):
+		return fmt.Sprintf("action '%s' has succeeded", since.Action)
+	default:
+		return ""
+	}
+}
+
+// getGuardOf returns the name of the action that lists the given action in its Else branch.
+// If the action is not part of an alternative branch, it returns an empty string.
+func getGuardOf(scenario *v1alpha1.Scenario, actionName string) string {
+	for _, action := range scenario.Spec.Actions {
+		if structure.ContainsStrings(action.Else, actionName) {
+			return action.Name
+		}
+	}
+
+	return ""
+}
+
+// getSkippedDependency returns the first dependency that has been skipped.
+// If no dependency has been skipped, it returns an empty string.
+func getSkippedDependency(deps []string, skipped []string) string {
+	for _, dep := range deps {
+		if structure.ContainsStrings(skipped, dep) {
+			return dep
+		}
+	}
+
+	return ""
+}
+
+// getSuccessfulDependency returns the first dependency that is successfully completed.
+// If no dependency is successfully completed, it returns an empty string.
+func getSuccessfulDependency(view lifecycle.ClassifierReader, deps []string) string {
+	for _, dep := range deps {
+		if view.IsSuccessful(dep) {
+			return dep
+		}
+	}
+
+	return ""
+}
+
+// isCompleted returns true if all the given jobs are completed, either successfully or not.
+func isCompleted(view lifecycle.ClassifierReader, jobs ...string) bool {
+	for _, job := range jobs {
+		if !view.IsSuccessful(job) && !view.IsFailed(job) {
+			return false
+		}
 	}
 
-	return runNext, nextCycle, nil
+	return true
 }
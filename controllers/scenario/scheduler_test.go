@@ -0,0 +1,226 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+// fakeEnvironment returns the default environment, with the clock stopped at the given time.
+func fakeEnvironment(now time.Time) *common.Environment {
+	env := common.DefaultEnvironment()
+	env.Clock = clocktesting.NewFakePassiveClock(now)
+
+	return env
+}
+
+// newView classifies services with the given phases.
+func newView(phases map[string]v1alpha1.Phase) *lifecycle.Classifier {
+	var view lifecycle.Classifier
+
+	view.Reset()
+
+	for name, phase := range phases {
+		var job v1alpha1.Service
+
+		job.SetName(name)
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+
+		job.Status.Lifecycle.Phase = phase
+
+		view.Classify(name, &job)
+	}
+
+	return &view
+}
+
+func TestNextJobs(t *testing.T) {
+	isTrue := &v1alpha1.ConditionalExpr{State: "true"}
+	minute := &metav1.Duration{Duration: time.Minute}
+
+	tests := []struct {
+		name      string
+		actions   []v1alpha1.Action
+		scheduled []string
+		skipped   []string
+		phases    map[string]v1alpha1.Phase
+		wantRun   []string
+		wantSkip  []string
+		wantErr   bool
+	}{
+		{
+			name: "guard taken",
+			actions: []v1alpha1.Action{
+				{Name: "a", When: isTrue, Else: []string{"b"}},
+				{Name: "b"},
+				{Name: "c", DependsOn: &v1alpha1.WaitSpec{Success: []string{"a"}}},
+			},
+			scheduled: []string{"a"},
+			phases:    map[string]v1alpha1.Phase{"a": v1alpha1.PhaseRunning},
+			wantSkip:  []string{"b"},
+		},
+		{
+			name: "guard taken and completed",
+			actions: []v1alpha1.Action{
+				{Name: "a", When: isTrue, Else: []string{"b"}},
+				{Name: "b"},
+				{Name: "c", DependsOn: &v1alpha1.WaitSpec{Success: []string{"a"}}},
+			},
+			scheduled: []string{"a"},
+			phases:    map[string]v1alpha1.Phase{"a": v1alpha1.PhaseSuccess},
+			wantRun:   []string{"c"},
+			wantSkip:  []string{"b"},
+		},
+		{
+			name: "guard skipped",
+			actions: []v1alpha1.Action{
+				{Name: "a", When: isTrue, Else: []string{"b"}},
+				{Name: "b"},
+				{Name: "c", DependsOn: &v1alpha1.WaitSpec{Success: []string{"a"}}},
+				{Name: "d", DependsOn: &v1alpha1.WaitSpec{Completed: []string{"a"}}},
+				{Name: "e", DependsOn: &v1alpha1.WaitSpec{Running: []string{"a"}}},
+				{Name: "f", DependsOn: &v1alpha1.WaitSpec{Failed: []string{"a"}}},
+			},
+			skipped:  []string{"a"},
+			wantRun:  []string{"b", "c", "d"},
+			wantSkip: []string{"e", "f"},
+		},
+		{
+			name: "branches joined",
+			actions: []v1alpha1.Action{
+				{Name: "a", When: isTrue, Else: []string{"b"}},
+				{Name: "b"},
+				{Name: "c", DependsOn: &v1alpha1.WaitSpec{Success: []string{"a", "b"}}},
+			},
+			scheduled: []string{"a"},
+			skipped:   []string{"b"},
+			phases:    map[string]v1alpha1.Phase{"a": v1alpha1.PhaseSuccess},
+			wantRun:   []string{"c"},
+		},
+		{
+			name: "failure-triggered dependencies",
+			actions: []v1alpha1.Action{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c"},
+				{Name: "on-a", DependsOn: &v1alpha1.WaitSpec{Failed: []string{"a"}}},
+				{Name: "on-b", DependsOn: &v1alpha1.WaitSpec{Failed: []string{"b"}}},
+				{Name: "on-c", DependsOn: &v1alpha1.WaitSpec{Failed: []string{"c"}}},
+			},
+			scheduled: []string{"a", "b", "c"},
+			phases: map[string]v1alpha1.Phase{
+				"a": v1alpha1.PhaseRunning,
+				"b": v1alpha1.PhaseFailed,
+				"c": v1alpha1.PhaseSuccess,
+			},
+			wantRun:  []string{"on-b"},
+			wantSkip: []string{"on-c"},
+		},
+		{
+			name: "completion-triggered dependencies",
+			actions: []v1alpha1.Action{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c"},
+				{Name: "on-a", DependsOn: &v1alpha1.WaitSpec{Completed: []string{"a"}}},
+				{Name: "on-b", DependsOn: &v1alpha1.WaitSpec{Completed: []string{"b"}}},
+				{Name: "on-c", DependsOn: &v1alpha1.WaitSpec{Completed: []string{"c"}}},
+				{Name: "on-all", DependsOn: &v1alpha1.WaitSpec{Completed: []string{"a", "b", "c"}}},
+			},
+			scheduled: []string{"a", "b", "c"},
+			phases: map[string]v1alpha1.Phase{
+				"a": v1alpha1.PhaseRunning,
+				"b": v1alpha1.PhaseFailed,
+				"c": v1alpha1.PhaseSuccess,
+			},
+			wantRun: []string{"on-b", "on-c"},
+		},
+		{
+			name: "running dependency on completed job",
+			actions: []v1alpha1.Action{
+				{Name: "a"},
+				{Name: "b", DependsOn: &v1alpha1.WaitSpec{Running: []string{"a"}}},
+			},
+			scheduled: []string{"a"},
+			phases:    map[string]v1alpha1.Phase{"a": v1alpha1.PhaseFailed},
+			wantErr:   true,
+		},
+		{
+			name: "unreachable anchors",
+			actions: []v1alpha1.Action{
+				{Name: "a", When: isTrue},
+				{Name: "b"},
+				{Name: "c"},
+				{Name: "d"},
+				{Name: "after-a", DependsOn: &v1alpha1.WaitSpec{After: minute, Since: &v1alpha1.TransitionRef{Action: "a", Phase: v1alpha1.PhaseRunning}}},
+				{Name: "after-b", DependsOn: &v1alpha1.WaitSpec{After: minute, Since: &v1alpha1.TransitionRef{Action: "b", Phase: v1alpha1.PhaseSuccess}}},
+				{Name: "after-c", DependsOn: &v1alpha1.WaitSpec{After: minute, Since: &v1alpha1.TransitionRef{Action: "c", Phase: v1alpha1.PhaseFailed}}},
+				{Name: "after-d", DependsOn: &v1alpha1.WaitSpec{After: minute, Since: &v1alpha1.TransitionRef{Action: "d", Phase: v1alpha1.PhaseSuccess}}},
+			},
+			scheduled: []string{"b", "c", "d"},
+			skipped:   []string{"a"},
+			phases: map[string]v1alpha1.Phase{
+				"b": v1alpha1.PhaseFailed,
+				"c": v1alpha1.PhaseSuccess,
+				"d": v1alpha1.PhaseRunning,
+			},
+			wantSkip: []string{"after-a", "after-b", "after-c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scenario v1alpha1.Scenario
+
+			scenario.Spec.Actions = tt.actions
+			scenario.Status.ScheduledJobs = tt.scheduled
+			scenario.Status.SkippedJobs = tt.skipped
+
+			r := &Controller{Logger: logr.Discard(), Environment: common.DefaultEnvironment(), view: newView(tt.phases)}
+
+			runNext, skipNext, _, err := r.NextJobs(&scenario)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NextJobs() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			var run []string
+
+			for _, action := range runNext {
+				run = append(run, action.Name)
+			}
+
+			if !reflect.DeepEqual(run, tt.wantRun) {
+				t.Errorf("NextJobs() runNext = %v, want %v", run, tt.wantRun)
+			}
+
+			if !reflect.DeepEqual(skipNext, tt.wantSkip) {
+				t.Errorf("NextJobs() skipNext = %v, want %v", skipNext, tt.wantSkip)
+			}
+		})
+	}
+}
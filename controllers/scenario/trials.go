@@ -0,0 +1,266 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// startOf returns the beginning of the running trial. If the scenario is not repeated, that is the creation
+// of the scenario.
+func startOf(scenario *v1alpha1.Scenario) metav1.Time {
+	if scenario.Status.Trials != nil {
+		return scenario.Status.Trials.StartTime
+	}
+
+	return scenario.GetCreationTimestamp()
+}
+
+// initializeTrials starts the first trial of a repeated scenario.
+func (r *Controller) initializeTrials(scenario *v1alpha1.Scenario) {
+	if scenario.Spec.Repeat == nil {
+		return
+	}
+
+	scenario.Status.Trials = &v1alpha1.TrialsStatus{
+		Current:   1,
+		StartTime: r.MetaNow(),
+	}
+}
+
+// trialsInProgress returns true if the running trial has reached a terminal phase, and either its result
+// is not yet recorded, or more trials are pending.
+func trialsInProgress(scenario *v1alpha1.Scenario) bool {
+	trials := scenario.Status.Trials
+
+	if scenario.Spec.Repeat == nil || trials == nil {
+		return false
+	}
+
+	if !scenario.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		return false
+	}
+
+	return len(trials.Results) < trials.Current || trials.Current < scenario.Spec.Repeat.Trials
+}
+
+// NextTrial records the result of the completed trial, removes its jobs, and once the cooldown has expired,
+// resets the status of the scenario for the next trial to begin. Once the last trial is recorded, the scenario
+// proceeds to its finally actions and cleanup.
+func (r *Controller) NextTrial(ctx context.Context, req ctrl.Request, scenario *v1alpha1.Scenario) (ctrl.Result, error) {
+	trials := scenario.Status.Trials
+	repeat := scenario.Spec.Repeat
+
+	/*---------------------------------------------------
+	 * Record the result of the completed trial
+	 *---------------------------------------------------*/
+	if len(trials.Results) < trials.Current {
+		result := r.collectTrial(ctx, scenario)
+
+		trials.Results = append(trials.Results, result)
+		trials.Summarize(repeat.Metrics)
+
+		eventType := corev1.EventTypeNormal
+		if result.Phase.Is(v1alpha1.PhaseFailed) {
+			eventType = corev1.EventTypeWarning
+		}
+
+		r.GetEventRecorderFor(scenario.GetName()).Event(scenario, eventType, "Trial",
+			fmt.Sprintf("Trial '%d/%d' completed with phase '%s'", result.Trial, repeat.Trials, result.Phase))
+
+		// The scenario succeeds only if every trial has succeeded.
+		if trials.Current == repeat.Trials && trials.Summary.SucceededTrials < trials.Summary.CompletedTrials {
+			scenario.Status.Lifecycle.Phase = v1alpha1.PhaseFailed
+			scenario.Status.Lifecycle.Reason = "TrialsFailed"
+			scenario.Status.Lifecycle.Message = fmt.Sprintf("'%d/%d' trials have failed",
+				trials.Summary.CompletedTrials-trials.Summary.SucceededTrials, trials.Summary.CompletedTrials)
+		}
+
+		if err := common.UpdateStatus(ctx, r, scenario); err != nil {
+			return common.RequeueAfter(r, req, time.Second)
+		}
+
+		return common.Stop(r, req)
+	}
+
+	/*---------------------------------------------------
+	 * Remove the jobs of the completed trial
+	 *---------------------------------------------------*/
+	var jobs []client.Object
+
+	jobs = append(jobs, r.view.GetPendingJobs()...)
+	jobs = append(jobs, r.view.GetRunningJobs()...)
+	jobs = append(jobs, r.view.GetSuccessfulJobs()...)
+	jobs = append(jobs, r.view.GetFailedJobs()...)
+
+	var removing bool
+
+	for _, job := range jobs {
+		if v1alpha1.GetComponentLabel(job) == v1alpha1.ComponentSys {
+			continue
+		}
+
+		expressions.UnsetAlert(ctx, job)
+		common.Delete(ctx, r, job)
+
+		removing = true
+	}
+
+	if removing {
+		// wait for the deletion events to trigger the next cycle.
+		return common.Stop(r, req)
+	}
+
+	/*---------------------------------------------------
+	 * Wait for the cooldown to expire
+	 *---------------------------------------------------*/
+	if repeat.Cooldown != nil {
+		lastResult := trials.Results[len(trials.Results)-1]
+
+		if wakeup := lastResult.EndTime.Add(repeat.Cooldown.Duration); r.Now().Before(wakeup) {
+			return common.RequeueAfter(r, req, r.Until(wakeup))
+		}
+	}
+
+	/*---------------------------------------------------
+	 * Reset the scenario for the next trial
+	 *---------------------------------------------------*/
+
+	// Alerts fired during the completed trial must not be accounted to the next one.
+	if expressions.ResetAlert(scenario) {
+		if err := common.Update(ctx, r, scenario); err != nil {
+			return common.RequeueAfter(r, req, time.Second)
+		}
+	}
+
+	trials = scenario.Status.Trials
+	trials.Current++
+	trials.StartTime = r.MetaNow()
+
+	scenario.Status.ScheduledJobs = nil
+	scenario.Status.SkippedJobs = nil
+	scenario.Status.Transitions = nil
+	scenario.Status.Timeline = nil
+	scenario.Status.Verdict = nil
+	scenario.Status.PostConditions = nil
+	scenario.Status.Variables = nil
+
+	for _, condition := range []v1alpha1.ConditionType{
+		v1alpha1.ConditionAllJobsAreScheduled,
+		v1alpha1.ConditionAllJobsAreCompleted,
+		v1alpha1.ConditionJobUnexpectedTermination,
+		v1alpha1.ConditionAssertionError,
+		v1alpha1.ConditionDeadlineExceeded,
+	} {
+		meta.RemoveStatusCondition(&scenario.Status.Conditions, condition.String())
+	}
+
+	scenario.Status.Lifecycle.Phase = v1alpha1.PhasePending
+	scenario.Status.Lifecycle.Reason = "NextTrial"
+	scenario.Status.Lifecycle.Message = fmt.Sprintf("Starting trial '%d/%d'", trials.Current, repeat.Trials)
+
+	r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "NextTrial",
+		scenario.Status.Lifecycle.Message)
+
+	if err := common.UpdateStatus(ctx, r, scenario); err != nil {
+		return common.RequeueAfter(r, req, time.Second)
+	}
+
+	return common.Stop(r, req)
+}
+
+// collectTrial returns the result of the completed trial, including the values of the trial metrics.
+// Metrics that cannot be collected are omitted from the result, and the errors are reported in the message.
+func (r *Controller) collectTrial(ctx context.Context, scenario *v1alpha1.Scenario) v1alpha1.TrialResult {
+	trials := scenario.Status.Trials
+
+	result := v1alpha1.TrialResult{
+		Trial:            trials.Current,
+		Phase:            scenario.Status.Phase,
+		Reason:           scenario.Status.Reason,
+		Message:          scenario.Status.Message,
+		StartTime:        trials.StartTime,
+		EndTime:          r.MetaNow(),
+		AssertionsPassed: !meta.IsStatusConditionTrue(scenario.Status.Conditions, v1alpha1.ConditionAssertionError.String()),
+	}
+
+	if len(scenario.Spec.Repeat.Metrics) == 0 {
+		return result
+	}
+
+	if scenario.Status.GrafanaEndpoint == "" {
+		result.Message += "; metrics are not collected because telemetry is disabled"
+
+		return result
+	}
+
+	if err := r.connectToGrafana(ctx, scenario, r.alertingProxy); err != nil {
+		result.Message += "; " + errors.Wrapf(err, "connect to grafana").Error()
+
+		return result
+	}
+
+	errs := []string{result.Message}
+
+	for _, metric := range scenario.Spec.Repeat.Metrics {
+		value, err := r.queryValue(ctx, scenario, metric.Value, result.StartTime.Time, result.EndTime.Time)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "metric '%s'", metric.Name).Error())
+
+			continue
+		}
+
+		if result.Values == nil {
+			result.Values = make(map[string]float64)
+		}
+
+		result.Values[metric.Name] = value
+	}
+
+	result.Message = strings.Join(errs, "; ")
+
+	return result
+}
+
+// queryValue reduces the values of the expression, over the given time range, into a single number.
+func (r *Controller) queryValue(ctx context.Context, scenario *v1alpha1.Scenario, expr v1alpha1.ExprValue,
+	from time.Time, to time.Time,
+) (float64, error) {
+	query, err := grafana.ParseValueExpr(expr)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid value expression")
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, grafana.Timeout)
+	defer cancel()
+
+	return grafana.GetClientFor(scenario).QueryValue(ctxTimeout, query, from, to)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetManifestSpec returns the manifests of the referenced template, expanded with the given inputs.
+func GetManifestSpec(ctx context.Context, cli client.Client, parent metav1.Object, fromTemplate v1alpha1.GenerateObjectFromTemplate) (v1alpha1.ManifestSpec, error) {
+	var template v1alpha1.Template
+
+	key := client.ObjectKey{
+		Namespace: parent.GetNamespace(),
+		Name:      fromTemplate.TemplateRef,
+	}
+
+	if err := cli.Get(ctx, key, &template); err != nil {
+		return v1alpha1.ManifestSpec{}, errors.Wrapf(err, "cannot find template '%s'", key.String())
+	}
+
+	if template.Spec.Manifests == nil {
+		return v1alpha1.ManifestSpec{}, errors.Errorf("template '%s' has no manifests", key.String())
+	}
+
+	/*
+		Convert Manifests to JSON and expand inputs
+	*/
+	body, err := json.Marshal(template.Spec.Manifests)
+	if err != nil {
+		return v1alpha1.ManifestSpec{}, errors.Errorf("cannot marshal manifests of %s", fromTemplate.TemplateRef)
+	}
+
+	// add extra fields in the template
+	if template.Spec.Inputs == nil {
+		var inputs v1alpha1.TemplateInputs
+		template.Spec.Inputs = &inputs
+	}
+
+	template.Spec.Inputs.Scenario = v1alpha1.GetScenarioLabel(parent)
+	template.Spec.Inputs.Namespace = parent.GetNamespace()
+
+	var spec v1alpha1.ManifestSpec
+
+	if err := fromTemplate.Generate(&spec, 0, template.Spec, body); err != nil {
+		return v1alpha1.ManifestSpec{}, errors.Wrapf(err, "evaluation of template '%s' has failed", fromTemplate.TemplateRef)
+	}
+
+	return spec, nil
+}
@@ -24,6 +24,7 @@ import (
 	serviceutils "github.com/carv-ics-forth/frisbee/controllers/service/utils"
 	"github.com/carv-ics-forth/frisbee/pkg/infrastructure"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -39,71 +40,100 @@ func LoadTemplates(ctx context.Context, cli client.Client, scenario *v1alpha1.Sc
 
 	// LoadTemplates Reference Graph
 	for i := 0; i < len(scenario.Spec.Actions); i++ {
-		action := &scenario.Spec.Actions[i]
+		if err := loadAction(ctx, cli, scenario, &scenario.Spec.Actions[i], readyNodes, allocatableResources); err != nil {
+			return err
+		}
+	}
 
-		switch action.ActionType {
-		case v1alpha1.ActionService:
-			if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Service.Inputs); err != nil {
-				return errors.Wrapf(err, "input error")
-			}
+	for i := 0; i < len(scenario.Spec.Finally); i++ {
+		if err := loadAction(ctx, cli, scenario, &scenario.Spec.Finally[i], readyNodes, allocatableResources); err != nil {
+			return err
+		}
+	}
 
-			if _, err := serviceutils.GetServiceSpec(ctx, cli, scenario, *action.Service); err != nil {
-				return errors.Wrapf(err, "service '%s' error", action.Name)
-			}
+	return nil
+}
 
-		case v1alpha1.ActionCluster:
-			if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Cluster.Inputs); err != nil {
-				return errors.Wrapf(err, "input error")
-			}
+// loadAction expands the macros of the action, and ensures that the referenced templates exist.
+func loadAction(ctx context.Context, cli client.Client, scenario *v1alpha1.Scenario, action *v1alpha1.Action,
+	readyNodes []corev1.Node, allocatableResources corev1.ResourceList,
+) error {
+	switch action.ActionType {
+	case v1alpha1.ActionService:
+		if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Service.Inputs); err != nil {
+			return errors.Wrapf(err, "input error")
+		}
 
-			if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, action.Cluster.GenerateObjectFromTemplate); err != nil {
-				return errors.Wrapf(err, "cluster '%s' error", action.Name)
-			}
+		if _, err := serviceutils.GetServiceSpec(ctx, cli, scenario, *action.Service); err != nil {
+			return errors.Wrapf(err, "service '%s' error", action.Name)
+		}
 
-			// LoadTemplates Placement Policies
-			if action.Cluster.Placement != nil {
-				// ensure there are at least two physical nodes for placement to make sense
-				if len(readyNodes) < 2 {
-					return errors.Errorf("Placement requires at least two ready nodes. Found: %v", readyNodes)
-				}
-			}
+	case v1alpha1.ActionCluster:
+		if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Cluster.Inputs); err != nil {
+			return errors.Wrapf(err, "input error")
+		}
 
-			// LoadTemplates Resource Policies
-			if action.Cluster.Resources != nil {
-				if err := infrastructure.RequestIsWithinLimits(action.Cluster.Resources.TotalResources, allocatableResources); err != nil {
-					return errors.Wrapf(err, "Overprovisioning error for Cluster '%s'", action.Name)
-				}
-			}
+		if _, err := serviceutils.GetServiceSpecList(ctx, cli, scenario, action.Cluster.GenerateObjectFromTemplate); err != nil {
+			return errors.Wrapf(err, "cluster '%s' error", action.Name)
+		}
 
-		case v1alpha1.ActionChaos:
-			if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Chaos.Inputs); err != nil {
-				return errors.Wrapf(err, "input error")
+		// LoadTemplates Placement Policies
+		if action.Cluster.Placement != nil {
+			// ensure there are at least two physical nodes for placement to make sense
+			if len(readyNodes) < 2 {
+				return errors.Errorf("Placement requires at least two ready nodes. Found: %v", readyNodes)
 			}
+		}
 
-			if _, err := chaosutils.GetChaosSpec(ctx, cli, scenario, *action.Chaos); err != nil {
-				return errors.Wrapf(err, "chaos '%s' error", action.Name)
+		// LoadTemplates Resource Policies
+		if action.Cluster.Resources != nil {
+			if err := infrastructure.RequestIsWithinLimits(action.Cluster.Resources.TotalResources, allocatableResources); err != nil {
+				return errors.Wrapf(err, "Overprovisioning error for Cluster '%s'", action.Name)
 			}
+		}
 
-		case v1alpha1.ActionCascade:
-			if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Cascade.Inputs); err != nil {
-				return errors.Wrapf(err, "input error")
-			}
+	case v1alpha1.ActionChaos:
+		if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Chaos.Inputs); err != nil {
+			return errors.Wrapf(err, "input error")
+		}
 
-			if _, err := chaosutils.GetChaosSpecList(ctx, cli, scenario, action.Cascade.GenerateObjectFromTemplate); err != nil {
-				return errors.Wrapf(err, "cascade '%s' error", action.Name)
-			}
+		if _, err := chaosutils.GetChaosSpec(ctx, cli, scenario, *action.Chaos); err != nil {
+			return errors.Wrapf(err, "chaos '%s' error", action.Name)
+		}
 
-		case v1alpha1.ActionCall:
-			if err := ExpandSliceInputs(ctx, cli, scenario.GetNamespace(), &action.Call.Services); err != nil {
-				return errors.Wrapf(err, "input error")
-			}
+	case v1alpha1.ActionCascade:
+		if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Cascade.Inputs); err != nil {
+			return errors.Wrapf(err, "input error")
+		}
+
+		if _, err := chaosutils.GetChaosSpecList(ctx, cli, scenario, action.Cascade.GenerateObjectFromTemplate); err != nil {
+			return errors.Wrapf(err, "cascade '%s' error", action.Name)
+		}
+
+	case v1alpha1.ActionApply:
+		if err := ExpandMacros(ctx, cli, scenario.GetNamespace(), &action.Apply.Inputs); err != nil {
+			return errors.Wrapf(err, "input error")
+		}
+
+		spec, err := GetManifestSpec(ctx, cli, scenario, *action.Apply)
+		if err != nil {
+			return errors.Wrapf(err, "apply '%s' error", action.Name)
+		}
 
-			// TODO: now that the templates are loaded, ensure that the referenced callables exist.
+		if _, err := spec.Objects(); err != nil {
+			return errors.Wrapf(err, "apply '%s' error", action.Name)
+		}
 
-		case v1alpha1.ActionDelete:
-			// calls and deletes do not involve templates.
-			return nil
+	case v1alpha1.ActionCall:
+		if err := ExpandSliceInputs(ctx, cli, scenario.GetNamespace(), &action.Call.Services); err != nil {
+			return errors.Wrapf(err, "input error")
 		}
+
+		// TODO: now that the templates are loaded, ensure that the referenced callables exist.
+
+	case v1alpha1.ActionDelete, v1alpha1.ActionScenario, v1alpha1.ActionWait, v1alpha1.ActionScale, v1alpha1.ActionHelm:
+		// deletes, includes, waits, scales, and helm releases do not involve templates. The actions of includes are loaded after the expansion.
+		return nil
 	}
 
 	return nil
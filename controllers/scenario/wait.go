@@ -0,0 +1,163 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/structure"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// wait creates a virtual job that represents the wait. The job remains running until the wait is over.
+// Metrics-based conditions are set as alerts on the virtual job.
+func (r *Controller) wait(ctx context.Context, scenario *v1alpha1.Scenario, action v1alpha1.Action) error {
+	job := r.placeholder(scenario, action)
+
+	if err := common.Create(ctx, r, scenario, job); err != nil {
+		return errors.Wrapf(err, "cannot create wait")
+	}
+
+	if err := expressions.SetAlerts(ctx, job, action.Wait.Until); err != nil {
+		return errors.Wrapf(err, "cannot set condition")
+	}
+
+	return nil
+}
+
+// updateWaits resolves the lifecycle of the virtual jobs of the scheduled waits.
+// It returns true if any virtual job is updated.
+func (r *Controller) updateWaits(ctx context.Context, scenario *v1alpha1.Scenario) (bool, error) {
+	updated := false
+
+	for _, actionName := range scenario.Status.ScheduledJobs {
+		action := getActionOrDie(scenario, actionName)
+
+		if action.ActionType != v1alpha1.ActionWait ||
+			r.view.IsSuccessful(actionName) || r.view.IsFailed(actionName) {
+			continue
+		}
+
+		var job v1alpha1.VirtualObject
+
+		key := client.ObjectKey{Namespace: scenario.GetNamespace(), Name: actionName}
+
+		if err := r.GetClient().Get(ctx, key, &job); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+
+		phase, msg := r.waitLifecycle(scenario, action.Wait, &job)
+		if job.Status.Phase == phase {
+			continue
+		}
+
+		job.Status.Lifecycle.Phase = phase
+		job.Status.Lifecycle.Reason = "Wait"
+		job.Status.Lifecycle.Message = msg
+
+		if err := common.UpdateStatus(ctx, r, &job); err != nil {
+			return false, errors.Wrapf(err, "cannot update wait '%s'", actionName)
+		}
+
+		if phase.Is(v1alpha1.PhaseSuccess) {
+			expressions.UnsetAlert(ctx, &job)
+
+			r.GetEventRecorderFor(scenario.GetName()).Event(scenario, corev1.EventTypeNormal, "WaitSuccess", msg)
+		}
+
+		updated = true
+	}
+
+	return updated, nil
+}
+
+// waitLifecycle returns the phase of a wait. The wait is running until the duration has elapsed, and the condition is met.
+func (r *Controller) waitLifecycle(scenario *v1alpha1.Scenario, wait *v1alpha1.BarrierSpec, job *v1alpha1.VirtualObject) (v1alpha1.Phase, string) {
+	if wait.Duration != nil {
+		if expiration := job.GetCreationTimestamp().Add(wait.Duration.Duration); r.Now().Before(expiration) {
+			return v1alpha1.PhaseRunning, fmt.Sprintf("waiting until '%s'", expiration.Format(time.RFC3339))
+		}
+	}
+
+	switch {
+	case wait.Until.HasStateExpr():
+		eval := expressions.Condition{Expr: wait.Until, Env: r.GetEnvironment()}
+
+		if !eval.IsTrue(r.view, scenario) {
+			return v1alpha1.PhaseRunning, fmt.Sprintf("waiting for state '%s'", wait.Until.State)
+		}
+
+		return v1alpha1.PhaseSuccess, fmt.Sprintf("state '%s' is met", wait.Until.State)
+
+	case wait.Until.HasMetricsExpr():
+		if _, info, fired := expressions.AlertIsFired(job); !fired {
+			return v1alpha1.PhaseRunning, fmt.Sprintf("waiting for metrics '%s'. Alert is %s", wait.Until.Metrics, info)
+		}
+
+		return v1alpha1.PhaseSuccess, fmt.Sprintf("metrics '%s' are met", wait.Until.Metrics)
+
+	case wait.Until.HasPromQLExpr(), wait.Until.IsCompound():
+		// alerts of metrics sub-expressions are set on the virtual job, and are met once fired.
+		// state sub-expressions reference the variables captured by the scenario.
+		eval := expressions.Condition{
+			Expr:       wait.Until,
+			Env:        r.GetEnvironment(),
+			MetOnAlert: true,
+			Variables:  scenario.GetVariables(),
+		}
+
+		if !eval.IsTrue(r.view, job) {
+			return v1alpha1.PhaseRunning, fmt.Sprintf("waiting for condition. %s", eval.Info)
+		}
+
+		return v1alpha1.PhaseSuccess, eval.Info
+
+	default:
+		return v1alpha1.PhaseSuccess, fmt.Sprintf("waited for '%s'", wait.Duration.Duration)
+	}
+}
+
+// nextWait returns the nearest expiration of the running waits, so that the controller can wake up on time.
+// If there are no running waits with a duration, it returns zero.
+func (r *Controller) nextWait(scenario *v1alpha1.Scenario) time.Time {
+	var next time.Time
+
+	for _, job := range r.view.GetRunningJobs() {
+		if !structure.ContainsStrings(scenario.Status.ScheduledJobs, job.GetName()) {
+			continue
+		}
+
+		action := getActionOrDie(scenario, job.GetName())
+
+		if action.ActionType != v1alpha1.ActionWait || action.Wait.Duration == nil {
+			continue
+		}
+
+		if expiration := job.GetCreationTimestamp().Add(action.Wait.Duration.Duration); r.Now().Before(expiration) {
+			next = earliest(next, expiration)
+		}
+	}
+
+	return next
+}
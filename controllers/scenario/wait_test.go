@@ -0,0 +1,87 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/go-logr/logr"
+)
+
+func TestWaitLifecycle_CompoundVariables(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	metrics := v1alpha1.ConditionalExpr{Metrics: "A2EjFbsMk/86/Average"}
+
+	// the state leaves reference the variables of the scenario, whereas the alert of the metrics leaf is on the job.
+	tests := []struct {
+		name  string
+		until *v1alpha1.ConditionalExpr
+	}{
+		{
+			name: "template",
+			until: &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{
+				{State: `{{.IsRunning "{{.variables.leader}}"}} == true`},
+				metrics,
+			}},
+		},
+		{
+			name: "cel",
+			until: &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{
+				{State: `isRunning(variables.leader)`, Syntax: v1alpha1.ExprSyntaxCEL},
+				metrics,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Controller{
+				Logger:      logr.Discard(),
+				Environment: fakeEnvironment(now),
+				view:        newView(map[string]v1alpha1.Phase{"server": v1alpha1.PhaseRunning}),
+			}
+
+			var (
+				scenario v1alpha1.Scenario
+				job      v1alpha1.VirtualObject
+			)
+
+			wait := &v1alpha1.BarrierSpec{Until: tt.until}
+
+			job.SetAnnotations(map[string]string{
+				"alert.frisbee.dev/name":      "default/VirtualObject/wait",
+				"alert.frisbee.dev/state":     "alerting",
+				"alert.frisbee.dev/timestamp": now.Format(time.RFC3339),
+				"alert.frisbee.dev/details":   "{}",
+			})
+
+			// the variable is not yet captured.
+			if phase, msg := r.waitLifecycle(&scenario, wait, &job); phase != v1alpha1.PhaseRunning {
+				t.Fatalf("waitLifecycle() = %s, want %s. Message: %s", phase, v1alpha1.PhaseRunning, msg)
+			}
+
+			scenario.Status.Variables = map[string]string{"leader": "server"}
+
+			if phase, msg := r.waitLifecycle(&scenario, wait, &job); phase != v1alpha1.PhaseSuccess {
+				t.Errorf("waitLifecycle() = %s, want %s. Message: %s", phase, v1alpha1.PhaseSuccess, msg)
+			}
+		})
+	}
+}
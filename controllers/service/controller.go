@@ -60,6 +60,7 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	*common.Environment
 
 	view *lifecycle.Classifier
 }
@@ -135,7 +136,7 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		// Update the scheduling information
-		service.Status.LastScheduleTime = &metav1.Time{Time: time.Now()}
+		service.Status.LastScheduleTime = &metav1.Time{Time: r.Now()}
 
 		return lifecycle.Pending(ctx, r, &service, "Submit pod create request")
 
@@ -230,10 +231,13 @@ func (r *Controller) Finalize(obj client.Object) error {
 */
 
 func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+	env := common.DefaultEnvironment()
+
 	reconciler := &Controller{
-		Manager: mgr,
-		Logger:  logger.WithName("service"),
-		view:    &lifecycle.Classifier{},
+		Manager:     mgr,
+		Logger:      logger.WithName("service"),
+		Environment: env,
+		view:        lifecycle.NewClassifier(env.Clock),
 	}
 
 	gvk := v1alpha1.GroupVersion.WithKind("Service")
@@ -32,7 +32,12 @@ func (r *Controller) updateLifecycle(service *v1alpha1.Service) bool {
 		return false
 	}
 
-	return lifecycle.SingleJob(r.view, &service.Status.Lifecycle)
+	// The service is backed by a single Pod, and therefore the restarts of the Pod are the restarts of the service.
+	restarts := int32(r.view.MaxRestarts())
+	restartsChanged := restarts != service.Status.Restarts
+	service.Status.Restarts = restarts
+
+	return lifecycle.SingleJob(r.view, &service.Status.Lifecycle) || restartsChanged
 }
 
 // convertPodLifecycle translates the Pod's Lifecycle to Frisbee Lifecycle.
@@ -0,0 +1,321 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=frisbee.dev,resources=sweeps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=frisbee.dev,resources=sweeps/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=frisbee.dev,resources=sweeps/finalizers,verbs=update
+
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create;delete
+
+// Controller reconciles a Sweep object.
+type Controller struct {
+	ctrl.Manager
+	logr.Logger
+	*common.Environment
+
+	view *lifecycle.Classifier
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current view of the sweep closer to the desired view.
+func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	/*
+		1: Load CR by name and extract the Desired State
+		------------------------------------------------------------------
+	*/
+	var sweep v1alpha1.Sweep
+
+	var requeue bool
+	result, err := common.Reconcile(ctx, r, req, &sweep, &requeue)
+
+	if requeue {
+		return result, err
+	}
+
+	r.Logger.Info("-> Reconcile",
+		"obj", client.ObjectKeyFromObject(&sweep),
+		"phase", sweep.Status.Phase,
+		"version", sweep.GetResourceVersion(),
+	)
+
+	defer func() {
+		r.Logger.Info("<- Reconciler",
+			"obj", client.ObjectKeyFromObject(&sweep),
+			"phase", sweep.Status.Phase,
+			"version", sweep.GetResourceVersion(),
+		)
+	}()
+
+	/*
+		2: Load CR's children and classify their current state (view)
+		------------------------------------------------------------------
+	*/
+	if err := r.PopulateView(ctx, &sweep); err != nil {
+		return lifecycle.Failed(ctx, r, &sweep, errors.Wrapf(err, "cannot populate view for '%s'", req))
+	}
+
+	/*
+		3: Use the view to update the CR's lifecycle.
+		------------------------------------------------------------------
+		The Update serves as "journaling" for the upcoming operations,
+		and as a roadblock for stall (queued) requests.
+	*/
+	if r.updateLifecycle(&sweep) {
+		if err := common.UpdateStatus(ctx, r, &sweep); err != nil {
+			// due to the multiple updates, it is possible for this function to
+			// be in conflict. We fix this issue by re-queueing the request.
+			return common.RequeueAfter(r, req, time.Second)
+		}
+	}
+
+	/*
+		4: Make the world matching what we want in our spec.
+		------------------------------------------------------------------
+	*/
+
+	if sweep.Spec.Suspend != nil && *sweep.Spec.Suspend {
+		// If this object is suspended, we don't want to run any scenarios, so we'll stop now.
+		r.Logger.Info("Sweep has been suspend. Nothing else it scheduled.")
+
+		return common.Stop(r, req)
+	}
+
+	switch sweep.Status.Phase {
+	case v1alpha1.PhaseUninitialized:
+		// Every combination of the axes becomes a run, that is scheduled once the concurrency limits allow it.
+		sweep.Status.Runs = sweep.GenerateRuns()
+		sweep.Status.ScheduledRuns = 0
+
+		return lifecycle.Pending(ctx, r, &sweep, "ready to start creating scenarios.")
+
+	case v1alpha1.PhasePending:
+		if sweep.Status.ScheduledRuns >= len(sweep.Status.Runs) {
+			r.Logger.Info("All runs have been scheduled. Nothing else to do. ")
+
+			return common.Stop(r, req)
+		}
+
+		// Respect the concurrency limit. Completed runs will trigger the next reconciliation cycle.
+		active := sweep.Status.ScheduledRuns - (r.view.NumSuccessfulJobs() + r.view.NumFailedJobs())
+		if active >= sweep.Spec.Parallelism {
+			return common.Stop(r, req)
+		}
+
+		if err := r.runJob(ctx, &sweep, sweep.Status.ScheduledRuns); err != nil {
+			return lifecycle.Failed(ctx, r, &sweep, errors.Wrapf(err, "cannot create scenario"))
+		}
+
+		sweep.Status.ScheduledRuns++
+
+		return lifecycle.Pending(ctx, r, &sweep, fmt.Sprintf("Scheduled runs: '%d/%d'",
+			sweep.Status.ScheduledRuns, len(sweep.Status.Runs)))
+
+	case v1alpha1.PhaseRunning:
+		// Nothing to do. Just wait for something to happen.
+		return common.Stop(r, req)
+
+	case v1alpha1.PhaseSuccess:
+		r.HasSucceed(&sweep)
+
+		return common.Stop(r, req)
+
+	case v1alpha1.PhaseFailed:
+		if err := r.HasFailed(ctx, &sweep); err != nil {
+			return common.RequeueAfter(r, req, time.Second)
+		}
+
+		return common.Stop(r, req)
+	}
+
+	panic(errors.New("This should never happen"))
+}
+
+/*
+PopulateView list the scenarios that are created by this sweep, and split them into
+active, successful, and failed runs. Unlike other controllers, children are not bound to the namespace of the sweep.
+*/
+func (r *Controller) PopulateView(ctx context.Context, sweep *v1alpha1.Sweep) error {
+	r.view.Reset()
+
+	var scenarios v1alpha1.ScenarioList
+
+	if err := r.GetClient().List(ctx, &scenarios, client.MatchingLabels(v1alpha1.GetSweepLabels(sweep))); err != nil {
+		return errors.Wrapf(err, "cannot list child scenarios")
+	}
+
+	for i, scenario := range scenarios.Items {
+		r.view.Classify(scenario.GetName(), &scenarios.Items[i])
+	}
+
+	return nil
+}
+
+func (r *Controller) HasSucceed(sweep *v1alpha1.Sweep) {
+	r.Logger.Info("CleanOnSuccess",
+		"obj", client.ObjectKeyFromObject(sweep).String(),
+		"successfulJobs", r.view.ListSuccessfulJobs(),
+	)
+
+	// The scenarios are maintained for getting back the test results.
+	// They are removed by deleting the Sweep.
+	if sweep.GetDeletionTimestamp().IsZero() {
+		r.GetEventRecorderFor(sweep.GetName()).Event(sweep, corev1.EventTypeNormal, "Completed", sweep.Status.Lifecycle.Message)
+	}
+}
+
+func (r *Controller) HasFailed(ctx context.Context, sweep *v1alpha1.Sweep) error {
+	r.Logger.Info("!! JobError",
+		"obj", client.ObjectKeyFromObject(sweep).String(),
+		"reason ", sweep.Status.Reason,
+		"message", sweep.Status.Message,
+	)
+
+	// Remove the non-failed scenarios. Leave the failed scenarios for postmortem analysis.
+	for _, job := range r.view.GetPendingJobs() {
+		common.Delete(ctx, r, job)
+	}
+
+	for _, job := range r.view.GetRunningJobs() {
+		common.Delete(ctx, r, job)
+	}
+
+	// Block from creating further scenarios
+	suspend := true
+	sweep.Spec.Suspend = &suspend
+
+	r.Logger.Info("Suspended",
+		"obj", client.ObjectKeyFromObject(sweep),
+		"reason", sweep.Status.Reason,
+		"message", sweep.Status.Message,
+	)
+
+	if sweep.GetDeletionTimestamp().IsZero() {
+		r.GetEventRecorderFor(sweep.GetName()).Event(sweep, corev1.EventTypeWarning,
+			"Suspended", sweep.Status.Lifecycle.Message)
+	}
+
+	// Update is needed since we modify the spec.suspend
+	return common.Update(ctx, r, sweep)
+}
+
+/*
+### Finalizers
+
+*/
+
+func (r *Controller) Finalizer() string {
+	return "sweeps.frisbee.dev/finalizer"
+}
+
+// Finalize removes the namespaces of the runs. Because they are located in different namespaces,
+// the scenarios cannot be garbage-collected through owner references.
+func (r *Controller) Finalize(obj client.Object) error {
+	r.Logger.Info("XX Finalize",
+		"kind", reflect.TypeOf(obj),
+		"name", obj.GetName(),
+		"version", obj.GetResourceVersion(),
+	)
+
+	ctx := context.Background()
+
+	var namespaces corev1.NamespaceList
+
+	if err := r.GetClient().List(ctx, &namespaces, client.MatchingLabels(v1alpha1.GetSweepLabels(obj))); err != nil {
+		return errors.Wrapf(err, "cannot list namespaces")
+	}
+
+	for i := range namespaces.Items {
+		common.Delete(ctx, r, &namespaces.Items[i])
+	}
+
+	return nil
+}
+
+/*
+### Setup
+	Finally, we'll update our setup.
+
+	The scenarios of a sweep are not owned by the sweep, because they are located in different namespaces.
+	Instead, we map the scenarios to their sweep using the sweep labels.
+*/
+
+func NewController(mgr ctrl.Manager, logger logr.Logger) error {
+	env := common.DefaultEnvironment()
+
+	controller := &Controller{
+		Manager:     mgr,
+		Logger:      logger.WithName("sweep"),
+		Environment: env,
+		view:        lifecycle.NewClassifier(env.Clock),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Sweep{}).
+		Named("sweep").
+		Watches(&v1alpha1.Scenario{},
+			handler.EnqueueRequestsFromMapFunc(sweepOf),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc: func(event.CreateEvent) bool { return false },
+				DeleteFunc: func(event.DeleteEvent) bool { return true },
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					prev, prevOK := e.ObjectOld.(*v1alpha1.Scenario)
+					latest, latestOK := e.ObjectNew.(*v1alpha1.Scenario)
+
+					// a sweep is only interested in the phase changes of its runs.
+					return prevOK && latestOK && prev.Status.Phase != latest.Status.Phase
+				},
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}),
+		).
+		Complete(controller)
+}
+
+// sweepOf returns the sweep that created the scenario, if any.
+func sweepOf(_ context.Context, obj client.Object) []reconcile.Request {
+	name, exists := obj.GetLabels()[v1alpha1.LabelSweep]
+	if !exists {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: obj.GetLabels()[v1alpha1.LabelSweepNamespace],
+		Name:      name,
+	}}}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sweep
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// runJob creates the scenario of the given run. Every scenario runs on a dedicated namespace, since the jobs
+// of the scenarios are named after the actions and would otherwise conflict with each other.
+func (r *Controller) runJob(ctx context.Context, sweep *v1alpha1.Sweep, runIndex int) error {
+	run := sweep.Status.Runs[runIndex]
+
+	scenario, err := sweep.GenerateScenario(run)
+	if err != nil {
+		return errors.Wrapf(err, "cannot generate scenario")
+	}
+
+	// Create the namespace of the run. The namespace is managed by Frisbee, so that the run is
+	// handled as a regular test (e.g, by the kubectl-frisbee).
+	var namespace corev1.Namespace
+
+	namespace.SetName(run.Name)
+	namespace.SetLabels(map[string]string{v1alpha1.LabelManagedBy: "Frisbee"})
+	v1alpha1.SetSweepLabels(&namespace, sweep)
+
+	if err := r.create(ctx, sweep, &namespace); err != nil {
+		return errors.Wrapf(err, "cannot create namespace for run '%s'", run.Name)
+	}
+
+	// Templates are resolved within the namespace of the scenario.
+	// Therefore, we copy the templates of the sweep to the namespace of the run.
+	var templates v1alpha1.TemplateList
+
+	if err := r.GetClient().List(ctx, &templates, client.InNamespace(sweep.GetNamespace())); err != nil {
+		return errors.Wrapf(err, "cannot list templates")
+	}
+
+	for _, template := range templates.Items {
+		var copied v1alpha1.Template
+
+		copied.SetName(template.GetName())
+		copied.SetNamespace(run.Name)
+		copied.SetLabels(template.GetLabels())
+		v1alpha1.SetSweepLabels(&copied, sweep)
+		template.Spec.DeepCopyInto(&copied.Spec)
+
+		if err := r.create(ctx, sweep, &copied); err != nil {
+			return errors.Wrapf(err, "cannot copy template '%s'", template.GetName())
+		}
+	}
+
+	// Create the scenario.
+	v1alpha1.SetSweepLabels(scenario, sweep)
+	v1alpha1.SetComponentLabel(&scenario.ObjectMeta, v1alpha1.ComponentSUT)
+
+	if err := r.create(ctx, sweep, scenario); err != nil {
+		return errors.Wrapf(err, "cannot create scenario for run '%s'", run.Name)
+	}
+
+	r.GetEventRecorderFor(sweep.GetName()).Event(sweep, corev1.EventTypeNormal, "Scheduled", run.Name)
+
+	return nil
+}
+
+// create adopts existing objects, as long as they are created by the given sweep (e.g, on a retry).
+// Unlike common.Create, it does not set an owner reference, since the children of a sweep are located in
+// different namespaces. Instead, the ownership is recorded by the sweep labels.
+func (r *Controller) create(ctx context.Context, sweep *v1alpha1.Sweep, obj client.Object) error {
+	r.Info("++ Create",
+		"kind", reflect.TypeOf(obj),
+		"obj", client.ObjectKeyFromObject(obj),
+	)
+
+	err := r.GetClient().Create(ctx, obj)
+	if err == nil {
+		return nil
+	}
+
+	if !k8errors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "creation error")
+	}
+
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return errors.Errorf("unexpected object '%s'", obj.GetName())
+	}
+
+	if err := r.GetClient().Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		return errors.Wrapf(err, "cannot get existing object")
+	}
+
+	if !v1alpha1.IsSweepChild(existing, sweep) {
+		return errors.Errorf("'%s' already exists, and it is not owned by sweep '%s'", obj.GetName(), sweep.GetName())
+	}
+
+	return nil
+}
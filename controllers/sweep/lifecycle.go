@@ -0,0 +1,67 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sweep
+
+import (
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+)
+
+// updateLifecycle returns the update lifecycle of the sweep.
+func (r *Controller) updateLifecycle(cr *v1alpha1.Sweep) bool {
+	// Step 1. Skip any CR which are already completed, or uninitialized.
+	if cr.Status.Phase.Is(v1alpha1.PhaseUninitialized, v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		return false
+	}
+
+	// Step 2. Update the pass/fail table of the runs.
+	runsChanged := r.updateRuns(cr)
+
+	// Step 3. Check if scheduling goes as expected.
+	totalJobs := len(cr.Status.Runs)
+
+	return lifecycle.GroupedJobs(totalJobs, r.view, &cr.Status.Lifecycle, cr.Spec.Tolerate) || runsChanged
+}
+
+// updateRuns mirrors the phase of the scenarios to the runs of the sweep.
+func (r *Controller) updateRuns(cr *v1alpha1.Sweep) bool {
+	var changed bool
+
+	for i := 0; i < cr.Status.ScheduledRuns && i < len(cr.Status.Runs); i++ {
+		run := &cr.Status.Runs[i]
+
+		jobs := r.view.GetPendingJobs(run.Name)
+		jobs = append(jobs, r.view.GetRunningJobs(run.Name)...)
+		jobs = append(jobs, r.view.GetSuccessfulJobs(run.Name)...)
+		jobs = append(jobs, r.view.GetFailedJobs(run.Name)...)
+
+		if len(jobs) == 0 {
+			continue
+		}
+
+		status := jobs[0].(v1alpha1.ReconcileStatusAware).GetReconcileStatus()
+
+		if run.Phase != status.Phase || run.Reason != status.Reason {
+			run.Phase = status.Phase
+			run.Reason = status.Reason
+
+			changed = true
+		}
+	}
+
+	return changed
+}
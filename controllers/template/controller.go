@@ -37,6 +37,7 @@ import (
 type Controller struct {
 	ctrl.Manager
 	logr.Logger
+	*common.Environment
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -147,7 +148,8 @@ func NewController(mgr ctrl.Manager, logger logr.Logger) error {
 		For(&template).
 		Named("template").
 		Complete(&Controller{
-			Manager: mgr,
-			Logger:  logger.WithName("template"),
+			Manager:     mgr,
+			Logger:      logger.WithName("template"),
+			Environment: common.DefaultEnvironment(),
 		})
 }
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -122,6 +123,36 @@ func (c TestManagementClient) ListScenarios(ctx context.Context, selector string
 	return scenarios, nil
 }
 
+// ListSweeps list all sweeps.
+func (c TestManagementClient) ListSweeps(ctx context.Context, selector string) (sweeps v1alpha1.SweepList, err error) {
+	set, err := labels.ConvertSelectorToLabelsMap(selector)
+	if err != nil {
+		return sweeps, errors.Wrapf(err, "invalid selector")
+	}
+
+	// find namespaces where sweeps may be running
+	filters := &client.ListOptions{LabelSelector: labels.SelectorFromValidatedSet(set)}
+
+	var namespaces corev1.NamespaceList
+
+	if err := c.client.List(ctx, &namespaces, filters); err != nil {
+		return sweeps, errors.Wrapf(err, "cannot list resource")
+	}
+
+	// extract sweeps from the namespaces
+	for _, namespace := range namespaces.Items {
+		var localList v1alpha1.SweepList
+
+		if err := c.client.List(ctx, &localList, &client.ListOptions{Namespace: namespace.GetName()}); err != nil {
+			return sweeps, errors.Wrapf(err, "cannot list resources")
+		}
+
+		sweeps.Items = append(sweeps.Items, localList.Items...)
+	}
+
+	return sweeps, nil
+}
+
 // ListVirtualObjects list all virtual objects.
 func (c TestManagementClient) ListVirtualObjects(ctx context.Context, namespace string, selectors ...string) (list v1alpha1.VirtualObjectList, err error) {
 	var filter client.ListOptions
@@ -165,3 +196,13 @@ func (c TestManagementClient) ListServices(ctx context.Context, namespace string
 
 	return list, err
 }
+
+// ListJobs lists the jobs of the scenario, as classified by the scenario controller.
+func (c TestManagementClient) ListJobs(ctx context.Context, scenario *v1alpha1.Scenario) ([]client.Object, error) {
+	jobs, err := common.ListJobs(ctx, c.client, client.ObjectKeyFromObject(scenario))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot list jobs")
+	}
+
+	return jobs, nil
+}
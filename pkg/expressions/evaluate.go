@@ -17,22 +17,54 @@ limitations under the License.
 package expressions
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Condition struct {
 	Expr *v1alpha1.ConditionalExpr
+
+	// Env is the environment that PromQL expressions are evaluated in. If nil, the default environment is used.
+	Env *common.Environment
+
+	// MetOnAlert makes metrics expressions true once their alert is fired, as in verdicts and waits.
+	// Otherwise, metrics expressions are true for as long as their alert is not fired, as in assertions.
+	// The meaning applies to the metrics sub-expressions of compositions as well.
+	MetOnAlert bool
+
+	// Variables are the variables that state expressions reference. If nil, the variables of the evaluated job
+	// are used, if it holds any. It is set when the job is not the holder of the variables, e.g, for a wait whose
+	// job carries the alerts of the sub-expressions, but whose variables are captured by the scenario.
+	Variables map[string]string
+
 	Info string
+
+	// Inconclusive is set if the expression cannot be evaluated yet, e.g, if Prometheus has no data for the query.
+	Inconclusive bool
 }
 
-func (c Condition) IsTrue(state lifecycle.ClassifierReader, job metav1.Object) bool {
+func (c *Condition) IsTrue(state lifecycle.ClassifierReader, job metav1.Object) bool {
+	// Check for compositions of sub-expressions
+	if c.Expr.IsCompound() {
+		return c.isCompoundTrue(state, job)
+	}
+
 	// Check for state expressions
 	if c.Expr.HasStateExpr() {
-		pass, err := c.Expr.State.GoValuate(state)
+		// The given variables, or those captured by the job (e.g, the scenario), if any.
+		variables := c.Variables
+
+		if vars, ok := job.(v1alpha1.VariablesAware); ok && variables == nil {
+			variables = vars.GetVariables()
+		}
+
+		pass, err := c.Expr.State.Valuate(c.Expr.Syntax, state, variables)
 		if err != nil {
 			c.Info = fmt.Sprintf("Err: '%s'. DebugInfo: '%s'", err, state.ListAll())
 
@@ -49,14 +81,118 @@ func (c Condition) IsTrue(state lifecycle.ClassifierReader, job metav1.Object) b
 
 		c.Info = fmt.Sprintf("Alert '%s' is %s", c.Expr.Metrics, info)
 
+		if c.MetOnAlert {
+			return fired
+		}
+
 		// non-fired mean that the condition is still true.
 		// fired means that the condition is violated, and should return false
 		return !fired
 	}
 
+	if c.Expr.HasPromQLExpr() {
+		ctx, cancel := context.WithTimeout(context.Background(), promQLTimeout)
+		defer cancel()
+
+		result, err := EvaluatePromQL(ctx, c.environment(), job, c.Expr.PromQL)
+		if err != nil {
+			c.Info = fmt.Sprintf("PromQL '%s' is inconclusive. Err: '%s'", c.Expr.PromQL, err)
+			c.Inconclusive = true
+
+			return false
+		}
+
+		c.Info = result.String()
+
+		// a comparison that holds, but not yet for the required duration, is pending.
+		c.Inconclusive = result.Holds && !result.Met()
+
+		return result.Met()
+	}
+
 	return false
 }
 
-func (c Condition) GetInfo() string {
+// isCompoundTrue evaluates the sub-expressions of allOf, anyOf, or not. All the sub-expressions are evaluated,
+// without short-circuiting, so that the result of every sub-expression is reported in the info.
+// A composition is inconclusive if its outcome depends on inconclusive sub-expressions.
+func (c *Condition) isCompoundTrue(state lifecycle.ClassifierReader, job metav1.Object) bool {
+	var (
+		operator string
+		subExprs []*v1alpha1.ConditionalExpr
+	)
+
+	switch {
+	case len(c.Expr.AllOf) > 0:
+		operator = "allOf"
+
+		for i := range c.Expr.AllOf {
+			subExprs = append(subExprs, &c.Expr.AllOf[i])
+		}
+
+	case len(c.Expr.AnyOf) > 0:
+		operator = "anyOf"
+
+		for i := range c.Expr.AnyOf {
+			subExprs = append(subExprs, &c.Expr.AnyOf[i])
+		}
+
+	default:
+		operator = "not"
+		subExprs = append(subExprs, c.Expr.Not)
+	}
+
+	var numTrue, numFalse, numInconclusive int
+
+	results := make([]string, 0, len(subExprs))
+
+	for i, subExpr := range subExprs {
+		eval := Condition{Expr: subExpr, Env: c.Env, MetOnAlert: c.MetOnAlert, Variables: c.Variables}
+
+		var outcome string
+
+		switch pass := eval.IsTrue(state, job); {
+		case pass:
+			numTrue++
+			outcome = "true"
+		case eval.Inconclusive:
+			numInconclusive++
+			outcome = "inconclusive"
+		default:
+			numFalse++
+			outcome = "false"
+		}
+
+		results = append(results, fmt.Sprintf("[%d] %s (%s)", i, outcome, eval.Info))
+	}
+
+	var pass bool
+
+	switch operator {
+	case "allOf":
+		pass = numTrue == len(subExprs)
+		c.Inconclusive = !pass && numFalse == 0
+	case "anyOf":
+		pass = numTrue > 0
+		c.Inconclusive = !pass && numInconclusive > 0
+	case "not":
+		pass = numFalse == 1
+		c.Inconclusive = numInconclusive == 1
+	}
+
+	c.Info = fmt.Sprintf("%s is %t: {%s}", operator, pass, strings.Join(results, "; "))
+
+	return pass
+}
+
+func (c *Condition) environment() *common.Environment {
+	if c.Env == nil {
+		return common.DefaultEnvironment()
+	}
+
+	return c.Env
+}
+
+func (c *Condition) GetInfo() string {
 	return c.Info
 }
@@ -0,0 +1,172 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCondition_IsTrue_Compound(t *testing.T) {
+	state := new(lifecycle.Classifier)
+	state.Reset()
+
+	{
+		var job v1alpha1.Service
+
+		job.SetName("server")
+		v1alpha1.SetComponentLabel(&job.ObjectMeta, v1alpha1.ComponentSUT)
+		job.Status.Lifecycle.Phase = v1alpha1.PhaseRunning
+
+		state.Classify(job.GetName(), &job)
+	}
+
+	// PromQL sub-expressions have no data, and are therefore inconclusive.
+	env := common.DefaultEnvironment()
+
+	env.QueryPrometheus = func(context.Context, metav1.Object, string) (model.Value, error) {
+		return nil, errors.New("no data")
+	}
+
+	running := v1alpha1.ConditionalExpr{State: `{{.IsRunning "server"}} == true`}
+	failed := v1alpha1.ConditionalExpr{State: `{{.IsFailed "server"}} == true`}
+	noData := v1alpha1.ConditionalExpr{PromQL: `up == 1`}
+
+	tests := []struct {
+		name             string
+		expr             *v1alpha1.ConditionalExpr
+		wantTrue         bool
+		wantInconclusive bool
+		wantInfo         string
+	}{
+		{
+			name:     "allOf is true",
+			expr:     &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{running, {Not: &failed}}},
+			wantTrue: true,
+			wantInfo: "allOf is true",
+		},
+		{
+			name:     "allOf is false",
+			expr:     &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{running, failed, noData}},
+			wantTrue: false,
+			wantInfo: "[1] false (State '{{.IsFailed \"server\"}} == true' is false)",
+		},
+		{
+			name:             "allOf is inconclusive",
+			expr:             &v1alpha1.ConditionalExpr{AllOf: []v1alpha1.ConditionalExpr{running, noData}},
+			wantTrue:         false,
+			wantInconclusive: true,
+			wantInfo:         "[1] inconclusive",
+		},
+		{
+			name:     "anyOf is true",
+			expr:     &v1alpha1.ConditionalExpr{AnyOf: []v1alpha1.ConditionalExpr{failed, noData, running}},
+			wantTrue: true,
+			wantInfo: "anyOf is true",
+		},
+		{
+			name:             "anyOf is inconclusive",
+			expr:             &v1alpha1.ConditionalExpr{AnyOf: []v1alpha1.ConditionalExpr{failed, noData}},
+			wantTrue:         false,
+			wantInconclusive: true,
+			wantInfo:         "anyOf is false",
+		},
+		{
+			name:             "not of inconclusive is inconclusive",
+			expr:             &v1alpha1.ConditionalExpr{Not: &noData},
+			wantTrue:         false,
+			wantInconclusive: true,
+			wantInfo:         "not is false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval := expressions.Condition{Expr: tt.expr, Env: env}
+
+			if got := eval.IsTrue(state, &v1alpha1.Scenario{}); got != tt.wantTrue {
+				t.Errorf("IsTrue() = %v, want %v. Info: %s", got, tt.wantTrue, eval.Info)
+			}
+
+			if eval.Inconclusive != tt.wantInconclusive {
+				t.Errorf("Inconclusive = %v, want %v. Info: %s", eval.Inconclusive, tt.wantInconclusive, eval.Info)
+			}
+
+			if !strings.Contains(eval.Info, tt.wantInfo) {
+				t.Errorf("Info = %s, want to contain %s", eval.Info, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestCondition_IsTrue_MetricsLeaf(t *testing.T) {
+	state := new(lifecycle.Classifier)
+	state.Reset()
+
+	var fired v1alpha1.Scenario
+
+	fired.SetAnnotations(map[string]string{
+		"alert.frisbee.dev/name":      "default/Scenario/scenario",
+		"alert.frisbee.dev/state":     "alerting",
+		"alert.frisbee.dev/timestamp": time.Now().Format(time.RFC3339),
+		"alert.frisbee.dev/details":   "{}",
+	})
+
+	metrics := v1alpha1.ConditionalExpr{Metrics: "A2EjFbsMk/86/Average"}
+
+	// a metrics leaf within a composition must have the same meaning as the bare metrics expression.
+	exprs := map[string]*v1alpha1.ConditionalExpr{
+		"bare":    &metrics,
+		"allOf":   {AllOf: []v1alpha1.ConditionalExpr{metrics}},
+		"anyOf":   {AnyOf: []v1alpha1.ConditionalExpr{metrics}},
+		"not-not": {Not: &v1alpha1.ConditionalExpr{Not: &metrics}},
+	}
+
+	tests := []struct {
+		name       string
+		job        *v1alpha1.Scenario
+		metOnAlert bool
+		wantTrue   bool
+	}{
+		{name: "fired, met on alert", job: &fired, metOnAlert: true, wantTrue: true},
+		{name: "not fired, met on alert", job: &v1alpha1.Scenario{}, metOnAlert: true, wantTrue: false},
+		{name: "fired, held while not fired", job: &fired, metOnAlert: false, wantTrue: false},
+		{name: "not fired, held while not fired", job: &v1alpha1.Scenario{}, metOnAlert: false, wantTrue: true},
+	}
+
+	for _, tt := range tests {
+		for form, expr := range exprs {
+			t.Run(tt.name+"/"+form, func(t *testing.T) {
+				eval := expressions.Condition{Expr: expr, MetOnAlert: tt.metOnAlert}
+
+				if got := eval.IsTrue(state, tt.job); got != tt.wantTrue {
+					t.Errorf("IsTrue() = %v, want %v. Info: %s", got, tt.wantTrue, eval.Info)
+				}
+			})
+		}
+	}
+}
@@ -100,6 +100,19 @@ func SetAlert(ctx context.Context, job client.Object, expr v1alpha1.ExprMetrics)
 	return grafana.GetClientFor(job).SetAlert(ctx, alert, name, msg)
 }
 
+// SetAlerts sets the metrics expressions of the condition, including those of its sub-expressions, as alerts on the job.
+func SetAlerts(ctx context.Context, job client.Object, expr *v1alpha1.ConditionalExpr) error {
+	for _, leaf := range expr.Leaves() {
+		if leaf.HasMetricsExpr() {
+			if err := SetAlert(ctx, job, leaf.Metrics); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // DispatchAlert informs an object about the fired alert by updating the metadata of that object.
 func DispatchAlert(ctx context.Context, r common.Reconciler, alertBody *notifier.Body) error {
 	if alertBody == nil {
@@ -233,6 +246,17 @@ func AlertIsFired(job metav1.Object) (*time.Time, string, bool) {
 	panic("Should never reach this point")
 }
 
+// AlertIsDispatched returns true if an alert has been dispatched to the object since its previous version.
+func AlertIsDispatched(prev, latest metav1.Object) bool {
+	for _, key := range []string{alertName, alertState, alertTimestamp} {
+		if prev.GetAnnotations()[key] != latest.GetAnnotations()[key] {
+			return true
+		}
+	}
+
+	return false
+}
+
 // UnsetAlert removes the annotations from the target object, and removes the Alert from Grafana.
 func UnsetAlert(_ context.Context, obj metav1.Object) {
 	alertID, exists := obj.GetAnnotations()[alertName]
@@ -240,3 +264,21 @@ func UnsetAlert(_ context.Context, obj metav1.Object) {
 		grafana.GetClientFor(obj).UnsetAlert(alertID)
 	}
 }
+
+// ResetAlert removes the annotations of a dispatched alert from the target object, so that a subsequent alert
+// can be told apart from the previous one. The alert remains in Grafana. It returns true if the object is modified.
+func ResetAlert(obj metav1.Object) bool {
+	annotations := obj.GetAnnotations()
+
+	if _, exists := annotations[alertName]; !exists {
+		return false
+	}
+
+	for _, key := range []string{alertName, alertState, alertDetails, alertTimestamp} {
+		delete(annotations, key)
+	}
+
+	obj.SetAnnotations(annotations)
+
+	return true
+}
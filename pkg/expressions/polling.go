@@ -0,0 +1,42 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions
+
+import (
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+)
+
+// PollingInterval is the period for re-evaluating the expressions that are not triggered by events.
+// Unlike Grafana alerts, which are pushed to the controller, PromQL expressions must be polled. Likewise, state
+// expressions that depend on time or on restarts change without a phase transition, and must be polled.
+var PollingInterval = 15 * time.Second
+
+// NextPolledEvaluation returns the time, after now, that the polled expressions must be re-evaluated.
+// If none of the expressions, or of their sub-expressions, is polled, it returns zero.
+func NextPolledEvaluation(now time.Time, exprs ...*v1alpha1.ConditionalExpr) time.Time {
+	for _, expr := range exprs {
+		for _, leaf := range expr.Leaves() {
+			if leaf.HasPromQLExpr() || (leaf.HasStateExpr() && leaf.State.IsPolled()) {
+				return now.Add(PollingInterval)
+			}
+		}
+	}
+
+	return time.Time{}
+}
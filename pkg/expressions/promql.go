@@ -0,0 +1,175 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// promQLTimeout bounds every query, so that an unreachable Prometheus does not stall the reconciliation.
+const promQLTimeout = 5 * time.Second
+
+// queryPrometheus runs an instant query against the Prometheus of the scenario that the job belongs to,
+// unless the environment provides its own query function.
+func queryPrometheus(ctx context.Context, env *common.Environment, job metav1.Object, query string) (model.Value, error) {
+	if env.QueryPrometheus != nil {
+		return env.QueryPrometheus(ctx, job, query)
+	}
+
+	var endpoint string
+
+	if configuration.Global.DeveloperMode {
+		/* If in developer mode, the operator runs outside the cluster, and will reach Prometheus via the ingress */
+		endpoint = common.ExternalEndpoint(common.DefaultPrometheusName, job.GetNamespace())
+	} else {
+		/* If the operator runs within the cluster, it will reach Prometheus via the service */
+		endpoint = common.InternalEndpoint(common.DefaultPrometheusName, job.GetNamespace(), common.DefaultPrometheusPort)
+	}
+
+	conn, err := api.NewClient(api.Config{Address: fmt.Sprintf("http://%s", endpoint)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "client error")
+	}
+
+	value, _, err := promv1.NewAPI(conn).Query(ctx, query, time.Time{})
+
+	return value, err
+}
+
+// promQLPending records the time that the comparison of an expression was first observed to hold, per job.
+// Like the 'for' clause of the Prometheus alerting rules, it is kept in memory, and is reset if the controller restarts.
+var promQLPending sync.Map
+
+// PromQLResult is the outcome of the evaluation of a PromQL expression.
+type PromQLResult struct {
+	Rule *v1alpha1.PromQLRule
+
+	// Observed are the samples returned by the query.
+	Observed []string
+
+	// Holds is true if the comparison holds for all the samples.
+	Holds bool
+
+	// Since is the time that the comparison was first observed to hold.
+	Since time.Time
+
+	// Elapsed is the time that the comparison has been holding for, at the time of the evaluation.
+	Elapsed time.Duration
+}
+
+// Met returns true if the comparison has been holding for the duration of the rule.
+func (r *PromQLResult) Met() bool {
+	return r.Holds && r.Elapsed >= r.Rule.For
+}
+
+func (r *PromQLResult) String() string {
+	observed := strings.Join(r.Observed, ", ")
+
+	switch {
+	case r.Met():
+		return fmt.Sprintf("PromQL '%s' is met. Observed: [%s]", r.Rule, observed)
+	case r.Holds:
+		return fmt.Sprintf("PromQL '%s' holds since '%s'. Observed: [%s]", r.Rule, r.Since.Format(time.RFC3339), observed)
+	default:
+		return fmt.Sprintf("PromQL '%s' is not met. Observed: [%s]", r.Rule, observed)
+	}
+}
+
+// EvaluatePromQL queries the Prometheus of the scenario, and compares the returned samples against the threshold.
+// A query without samples cannot be evaluated, and returns an error. The duration of the comparison is measured
+// by the clock of the environment.
+func EvaluatePromQL(ctx context.Context, env *common.Environment, job metav1.Object, expr v1alpha1.ExprPromQL) (*PromQLResult, error) {
+	rule, err := expr.Parse()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid promQL expression")
+	}
+
+	value, err := queryPrometheus(ctx, env, job, rule.Query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "query error")
+	}
+
+	var samples []float64
+
+	result := &PromQLResult{Rule: rule}
+
+	switch v := value.(type) {
+	case *model.Scalar:
+		samples = append(samples, float64(v.Value))
+		result.Observed = append(result.Observed, formatSample(v.Value))
+
+	case model.Vector:
+		for _, sample := range v {
+			samples = append(samples, float64(sample.Value))
+
+			if len(v) == 1 && len(sample.Metric) == 0 {
+				result.Observed = append(result.Observed, formatSample(sample.Value))
+			} else {
+				result.Observed = append(result.Observed, fmt.Sprintf("%s=%s", sample.Metric, formatSample(sample.Value)))
+			}
+		}
+
+	default:
+		return nil, errors.Errorf("expected scalar or instant vector, but got '%s'", value.Type())
+	}
+
+	if len(samples) == 0 {
+		return nil, errors.Errorf("no data for query '%s'", rule.Query)
+	}
+
+	result.Holds = true
+
+	for _, sample := range samples {
+		if !rule.Compare(sample) {
+			result.Holds = false
+		}
+	}
+
+	key := fmt.Sprintf("%s/%s", job.GetUID(), expr)
+
+	if !result.Holds {
+		promQLPending.Delete(key)
+
+		return result, nil
+	}
+
+	now := env.Now()
+
+	since, _ := promQLPending.LoadOrStore(key, now)
+	result.Since = since.(time.Time)
+	result.Elapsed = now.Sub(result.Since)
+
+	return result, nil
+}
+
+func formatSample(value model.SampleValue) string {
+	return strconv.FormatFloat(float64(value), 'g', -1, 64)
+}
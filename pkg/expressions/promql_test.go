@@ -0,0 +1,144 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/pkg/expressions"
+	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestCondition_IsTrue_PromQL(t *testing.T) {
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	fakeClock := clocktesting.NewFakeClock(start)
+
+	env := common.DefaultEnvironment()
+	env.Clock = fakeClock
+
+	// observations are returned in order, one per evaluation.
+	type observation struct {
+		value model.Value
+		err   error
+	}
+
+	scalar := func(v float64) observation {
+		return observation{value: &model.Scalar{Value: model.SampleValue(v)}}
+	}
+
+	tests := []struct {
+		name             string
+		expr             v1alpha1.ExprPromQL
+		observations     []observation
+		wantTrue         []bool
+		wantInconclusive []bool
+	}{
+		{
+			name:             "comparison holds",
+			expr:             `latency < 0.02`,
+			observations:     []observation{scalar(0.01)},
+			wantTrue:         []bool{true},
+			wantInconclusive: []bool{false},
+		},
+		{
+			name:             "comparison does not hold",
+			expr:             `latency < 0.02`,
+			observations:     []observation{scalar(0.03)},
+			wantTrue:         []bool{false},
+			wantInconclusive: []bool{false},
+		},
+		{
+			name: "comparison must hold for every sample",
+			expr: `latency < 0.02`,
+			observations: []observation{{value: model.Vector{
+				{Metric: model.Metric{"instance": "server-1"}, Value: 0.01},
+				{Metric: model.Metric{"instance": "server-2"}, Value: 0.03},
+			}}},
+			wantTrue:         []bool{false},
+			wantInconclusive: []bool{false},
+		},
+		{
+			name:             "no data",
+			expr:             `latency < 0.02`,
+			observations:     []observation{{value: model.Vector{}}},
+			wantTrue:         []bool{false},
+			wantInconclusive: []bool{true},
+		},
+		{
+			name:             "unreachable prometheus",
+			expr:             `latency < 0.02`,
+			observations:     []observation{{err: errors.New("connection refused")}},
+			wantTrue:         []bool{false},
+			wantInconclusive: []bool{true},
+		},
+		{
+			name:             "comparison holds for the duration",
+			expr:             `latency < 0.02 for 1m`,
+			observations:     []observation{scalar(0.01), scalar(0.01), scalar(0.01)},
+			wantTrue:         []bool{false, false, true},
+			wantInconclusive: []bool{true, true, false},
+		},
+		{
+			name:             "comparison is interrupted within the duration",
+			expr:             `latency < 0.02 for 1m`,
+			observations:     []observation{scalar(0.01), scalar(0.03), scalar(0.01), scalar(0.01)},
+			wantTrue:         []bool{false, false, false, false},
+			wantInconclusive: []bool{true, false, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClock.SetTime(start)
+
+			job := &v1alpha1.Scenario{ObjectMeta: metav1.ObjectMeta{Name: "test", UID: types.UID(tt.name)}}
+
+			next := 0
+
+			env.QueryPrometheus = func(context.Context, metav1.Object, string) (model.Value, error) {
+				obs := tt.observations[next]
+				next++
+
+				return obs.value, obs.err
+			}
+
+			for i := range tt.observations {
+				eval := expressions.Condition{Expr: &v1alpha1.ConditionalExpr{PromQL: tt.expr}, Env: env}
+
+				if got := eval.IsTrue(new(lifecycle.Classifier), job); got != tt.wantTrue[i] {
+					t.Errorf("evaluation %d: IsTrue() = %v, want %v. Info: %s", i, got, tt.wantTrue[i], eval.Info)
+				}
+
+				if eval.Inconclusive != tt.wantInconclusive[i] {
+					t.Errorf("evaluation %d: Inconclusive = %v, want %v. Info: %s", i, eval.Inconclusive, tt.wantInconclusive[i], eval.Info)
+				}
+
+				fakeClock.Step(30 * time.Second)
+			}
+		})
+	}
+}
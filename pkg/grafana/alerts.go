@@ -17,7 +17,9 @@ limitations under the License.
 package grafana
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"strconv"
 	"strings"
 
@@ -205,6 +207,64 @@ func ParseAlertExpr(query v1alpha1.ExprMetrics) (*AlertRule, error) {
 	return &alert, nil
 }
 
+// PanelAlert returns the legacy alert of a panel that evaluates the rule.
+func (alert *AlertRule) PanelAlert(name string, msg string) *sdk.Alert {
+	return &sdk.Alert{
+		Name:          name,
+		AlertRuleTags: map[string]string{"my-alert": "yeeha"},
+		Conditions: []sdk.AlertCondition{
+			{
+				Evaluator: alert.Evaluator,
+				Operator: sdk.AlertOperator{
+					Type: "and",
+				},
+				Query: sdk.AlertQuery{
+					Params: []string{alert.Metric.MetricName, alert.FromTime, alert.ToTime},
+				},
+				Reducer: alert.Reducer,
+				Type:    "query",
+			},
+		},
+
+		ExecutionErrorState: string(ErrError),
+		NoDataState:         string(NoData),
+
+		// Frequency specifies how often the scheduler should evaluate the alert rule.
+		// This is referred to as the evaluation interval. Because in Frisbee we use alerts as
+		// assertions, we only need to run them once. Default: 1m
+		Frequency: alert.Frequency,
+
+		// For specifies how long the query needs to violate the configured thresholds before the alert notification
+		// triggers. Default: 5m
+		For: alert.Duration,
+
+		Notifications: nil,
+		Message:       msg,
+		// Handler: 1, // Send to default notification channel (should be the controller)
+	}
+}
+
+// IsSameRule returns true if the alerts have the same name, and evaluate the same rule. The rule consists of the
+// conditions (query, reducer, and evaluator), the evaluation frequency, and the pending duration.
+func IsSameRule(stored, desired *sdk.Alert) bool {
+	if stored == nil || desired == nil {
+		return stored == desired
+	}
+
+	// the stored conditions are decoded from the dashboard, and omitted empty fields are decoded as nil.
+	storedConditions, errStored := json.Marshal(stored.Conditions)
+	desiredConditions, errDesired := json.Marshal(desired.Conditions)
+
+	if errStored != nil || errDesired != nil {
+		return false
+	}
+
+	return stored.Name == desired.Name &&
+		bytes.Equal(storedConditions, desiredConditions) &&
+		stored.Frequency == desired.Frequency &&
+		stored.For == desired.For
+}
+
 // SetAlert adds a new alert to Grafana using the Legacy API.
 func (c *Client) SetAlert(ctx context.Context, alert *AlertRule, name string, msg string) error {
 	if c == nil {
@@ -229,6 +289,8 @@ func (c *Client) SetAlert(ctx context.Context, alert *AlertRule, name string, ms
 	/*---------------------------------------------------*
 	 * Set Alert to the appropriate Panel
 	 *---------------------------------------------------*/
+	desired := alert.PanelAlert(name, msg)
+
 	var panelExists bool
 
 	for _, panel := range board.Panels {
@@ -238,43 +300,19 @@ func (c *Client) SetAlert(ctx context.Context, alert *AlertRule, name string, ms
 		}
 
 		if panel.Alert != nil {
-			return errors.Errorf("alert [%s] has already been set for this panel", panel.Alert.Name)
-		}
-
-		panel.CommonPanel.Alert = &sdk.Alert{
-			Name:          name,
-			AlertRuleTags: map[string]string{"my-alert": "yeeha"},
-			Conditions: []sdk.AlertCondition{
-				{
-					Evaluator: alert.Evaluator,
-					Operator: sdk.AlertOperator{
-						Type: "and",
-					},
-					Query: sdk.AlertQuery{
-						Params: []string{alert.Metric.MetricName, alert.FromTime, alert.ToTime},
-					},
-					Reducer: alert.Reducer,
-					Type:    "query",
-				},
-			},
-
-			ExecutionErrorState: string(ErrError),
-			NoDataState:         string(NoData),
-
-			// Frequency specifies how often the scheduler should evaluate the alert rule.
-			// This is referred to as the evaluation interval. Because in Frisbee we use alerts as
-			// assertions, we only need to run them once. Default: 1m
-			Frequency: alert.Frequency,
+			// Repeated scenarios re-arm their alerts at the beginning of every trial.
+			// A different rule of the same object is a conflict, and is not silently dropped.
+			if IsSameRule(panel.Alert, desired) {
+				c.logger.Info("Alert is already set", "alertName", name)
 
-			// For specifies how long the query needs to violate the configured thresholds before the alert notification
-			// triggers. Default: 5m
-			For: alert.Duration,
+				return nil
+			}
 
-			Notifications: nil,
-			Message:       msg,
-			// Handler: 1, // Send to default notification channel (should be the controller)
+			return errors.Errorf("alert [%s] has already been set for this panel", panel.Alert.Name)
 		}
 
+		panel.CommonPanel.Alert = desired
+
 		panelExists = true
 	}
 
@@ -1,6 +1,7 @@
 package grafana_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -121,3 +122,74 @@ func TestParseAlert(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSameRule(t *testing.T) {
+	const name = "default/Scenario/test"
+
+	panelAlert := func(query v1alpha1.ExprMetrics, name string) *sdk.Alert {
+		rule, err := grafana.ParseAlertExpr(query)
+		if err != nil {
+			t.Fatalf("ParseAlertExpr() error = %v", err)
+		}
+
+		return rule.PanelAlert(name, "msg")
+	}
+
+	// the alert is stored in the dashboard, and is decoded when the dashboard is retrieved.
+	stored := func(alert *sdk.Alert) *sdk.Alert {
+		raw, err := json.Marshal(alert)
+		if err != nil {
+			t.Fatalf("cannot encode alert: %v", err)
+		}
+
+		var decoded sdk.Alert
+
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			t.Fatalf("cannot decode alert: %v", err)
+		}
+
+		return &decoded
+	}
+
+	below := panelAlert("avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is below(14)", name)
+
+	tests := []struct {
+		name    string
+		desired *sdk.Alert
+		want    bool
+	}{
+		{
+			name:    "re-armed",
+			desired: panelAlert("avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is below(14)", name),
+			want:    true,
+		},
+		{
+			name:    "different threshold",
+			desired: panelAlert("avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is below(20)", name),
+			want:    false,
+		},
+		{
+			name:    "different range",
+			desired: panelAlert("avg() of query(wpFnYRwGk/2/bitrate, 5m, now) is below(14)", name),
+			want:    false,
+		},
+		{
+			name:    "different evaluator",
+			desired: panelAlert("avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is above(14)", name),
+			want:    false,
+		},
+		{
+			name:    "different object",
+			desired: panelAlert("avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is below(14)", "default/Scenario/other"),
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grafana.IsSameRule(stored(below), tt.desired); got != tt.want {
+				t.Errorf("IsSameRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+)
+
+// TimeRange returns the time range of the alert rule, relative to the given time.
+// The range is given in the relative format of Grafana, e.g., from '15m' (ago) to 'now'.
+func (alert *AlertRule) TimeRange(now time.Time) (from time.Time, to time.Time, err error) {
+	relative := func(ago string) (time.Time, error) {
+		if ago == "now" {
+			return now, nil
+		}
+
+		duration, err := model.ParseDuration(ago)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "invalid relative time '%s'", ago)
+		}
+
+		return now.Add(-time.Duration(duration)), nil
+	}
+
+	if from, err = relative(alert.FromTime); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if to, err = relative(alert.ToTime); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return from, to, nil
+}
+
+// Fires returns true if the reduced value meets the evaluator of the alert rule.
+// If the query has returned no values, only the 'no_value' evaluator fires.
+func (alert *AlertRule) Fires(value float64, hasValue bool) (bool, error) {
+	params := alert.Evaluator.Params
+
+	expectParams := func(num int) error {
+		if len(params) != num {
+			return errors.Errorf("evaluator '%s' expects %d parameters. Got %v", alert.Evaluator.Type, num, params)
+		}
+
+		return nil
+	}
+
+	switch alert.Evaluator.Type {
+	case "no_value":
+		return !hasValue, nil
+
+	case "gt":
+		if err := expectParams(1); err != nil {
+			return false, err
+		}
+
+		return hasValue && value > params[0], nil
+
+	case "lt":
+		if err := expectParams(1); err != nil {
+			return false, err
+		}
+
+		return hasValue && value < params[0], nil
+
+	case "within_range", "withinrange":
+		if err := expectParams(2); err != nil {
+			return false, err
+		}
+
+		return hasValue && value > params[0] && value < params[1], nil
+
+	case "outside_range", "outsiderange":
+		if err := expectParams(2); err != nil {
+			return false, err
+		}
+
+		return hasValue && (value < params[0] || value > params[1]), nil
+
+	default:
+		return false, errors.Errorf("unsupported evaluator '%s'", alert.Evaluator.Type)
+	}
+}
+
+// AlertEvaluation is the outcome of an instant evaluation of an alert rule.
+type AlertEvaluation struct {
+	// From and To are the absolute time range of the query.
+	From, To time.Time
+
+	// Value is the reduced value of the query. It is meaningful only if HasValue is true.
+	Value float64
+
+	// HasValue is false if the query has returned no values.
+	HasValue bool
+
+	// Fires is true if the reduced value meets the evaluator of the alert rule.
+	Fires bool
+}
+
+// EvaluateAlert runs the query of the alert rule once, and checks whether the alert fires.
+// Unlike Grafana, the evaluation is instant: the pending duration ('for') and the frequency ('every') are ignored.
+func (c *Client) EvaluateAlert(ctx context.Context, alert *AlertRule, now time.Time) (*AlertEvaluation, error) {
+	var eval AlertEvaluation
+
+	from, to, err := alert.TimeRange(now)
+	if err != nil {
+		return nil, err
+	}
+
+	eval.From, eval.To = from, to
+
+	value, err := c.QueryValue(ctx, &ValueQuery{Metric: alert.Metric, Reducer: alert.Reducer.Type}, from, to)
+
+	switch {
+	case errors.Is(err, ErrNoValues):
+		eval.HasValue = false
+	case err != nil:
+		return nil, errors.Wrapf(err, "query has failed")
+	default:
+		eval.Value, eval.HasValue = value, true
+	}
+
+	fires, err := alert.Fires(eval.Value, eval.HasValue)
+	if err != nil {
+		return nil, err
+	}
+
+	eval.Fires = fires
+
+	return &eval, nil
+}
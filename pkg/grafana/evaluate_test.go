@@ -0,0 +1,122 @@
+package grafana_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/grafana"
+)
+
+func TestAlertRule_Fires(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    v1alpha1.ExprMetrics
+		value    float64
+		hasValue bool
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name:     "below",
+			query:    "avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is below(14)",
+			value:    10,
+			hasValue: true,
+			want:     true,
+		},
+		{
+			name:     "not above",
+			query:    "avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is above(14)",
+			value:    10,
+			hasValue: true,
+			want:     false,
+		},
+		{
+			name:     "within range",
+			query:    "avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is withinrange(4,88)",
+			value:    10,
+			hasValue: true,
+			want:     true,
+		},
+		{
+			name:     "outside range",
+			query:    "avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is outside_range(4,88)",
+			value:    10,
+			hasValue: true,
+			want:     false,
+		},
+		{
+			name:     "no value",
+			query:    "avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is novalue()",
+			hasValue: false,
+			want:     true,
+		},
+		{
+			name:     "no value for threshold",
+			query:    "avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is below(14)",
+			hasValue: false,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert, err := grafana.ParseAlertExpr(tt.query)
+			if err != nil {
+				t.Fatalf("ParseAlertExpr() error = %v", err)
+			}
+
+			got, err := alert.Fires(tt.value, tt.hasValue)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Fires() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Fires() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertRule_TimeRange(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    v1alpha1.ExprMetrics
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{
+			name:     "relative to now",
+			query:    "avg() of query(wpFnYRwGk/2/bitrate, 15m, now) is below(14)",
+			wantFrom: now.Add(-15 * time.Minute),
+			wantTo:   now,
+		},
+		{
+			name:     "relative to the past",
+			query:    "avg() of query(wpFnYRwGk/2/bitrate, 1d, 1h) is below(14)",
+			wantFrom: now.Add(-24 * time.Hour),
+			wantTo:   now.Add(-time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert, err := grafana.ParseAlertExpr(tt.query)
+			if err != nil {
+				t.Fatalf("ParseAlertExpr() error = %v", err)
+			}
+
+			from, to, err := alert.TimeRange(now)
+			if err != nil {
+				t.Fatalf("TimeRange() error = %v", err)
+			}
+
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("TimeRange() = [%v, %v], want [%v, %v]", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
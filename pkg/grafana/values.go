@@ -0,0 +1,253 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+)
+
+// ValueQuery reduces the values of a Grafana metric into a single number.
+type ValueQuery struct {
+	Metric
+
+	// Reducer is one of avg, min, max, sum, count, last, median.
+	Reducer string
+}
+
+func ParseValueExpr(query v1alpha1.ExprValue) (*ValueQuery, error) {
+	matches, err := query.Parse()
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing error")
+	}
+
+	var value ValueQuery
+
+	for _, field := range v1alpha1.ExprValueValidator.SubexpNames() {
+		if field == "" { // Evaluate only existing fields.
+			continue
+		}
+
+		match := matches[v1alpha1.ExprValueValidator.SubexpIndex(field)]
+
+		switch field {
+		case "reducer":
+			value.Reducer = match
+
+		case "dashboardUID":
+			value.Metric.DashboardUID = match
+
+		case "panelID":
+			panelID, err := strconv.ParseUint(match, 10, 32)
+			if err != nil {
+				return nil, errors.Wrapf(err, "erroneous panelID")
+			}
+
+			value.Metric.PanelID = uint(panelID)
+
+		case "metric":
+			value.Metric.MetricName = match
+
+		default:
+			panic(errors.Errorf("invalid field %s", field))
+		}
+	}
+
+	return &value, nil
+}
+
+// dataResponse is the subset of the Grafana DataFrame response that holds the values of the queries.
+type dataResponse struct {
+	Results map[string]struct {
+		Error string `json:"error"`
+
+		Frames []struct {
+			Data struct {
+				Values [][]interface{} `json:"values"`
+			} `json:"data"`
+		} `json:"frames"`
+	} `json:"results"`
+}
+
+// QueryValue runs the query of the referenced panel over the given time range, and reduces the returned values
+// into a single number. The metric name refers to the refId of the panel's query.
+func (c *Client) QueryValue(ctx context.Context, query *ValueQuery, from time.Time, to time.Time) (float64, error) {
+	if c == nil {
+		panic("empty client was given")
+	}
+
+	/*---------------------------------------------------*
+	 * Find the query of the panel
+	 *---------------------------------------------------*/
+	board, _, err := c.Conn.GetDashboardByUID(ctx, query.DashboardUID)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot retrieve dashboard %s", query.DashboardUID)
+	}
+
+	var target interface{}
+
+	for _, panel := range board.Panels {
+		if panel.ID != query.PanelID || panel.GetTargets() == nil {
+			continue
+		}
+
+		for _, candidate := range *panel.GetTargets() {
+			if candidate.RefID == query.MetricName {
+				evaluateDashboardVariable(&candidate.Expr)
+
+				target = candidate
+			}
+		}
+	}
+
+	if target == nil {
+		return 0, errors.Errorf("no query '%s' in panel '%d' of dashboard '%s'",
+			query.MetricName, query.PanelID, query.DashboardUID)
+	}
+
+	/*---------------------------------------------------*
+	 * Fetch the values of the query
+	 *---------------------------------------------------*/
+	dataReq := &DataRequest{
+		Queries: []interface{}{target},
+		Range: TimeRange{
+			From: from.UTC(),
+			To:   to.UTC(),
+			Raw: &RawTimeRange{
+				From: from.UTC(),
+				To:   to.UTC(),
+			},
+		},
+		From: fmt.Sprint(from.UnixMilli()),
+		To:   fmt.Sprint(to.UnixMilli()),
+	}
+
+	var dataResp dataResponse
+
+	resp, err := req.NewClient().R().
+		SetContext(ctx).
+		SetBodyJsonMarshal(dataReq).
+		SetSuccessResult(&dataResp).
+		Post(c.BaseURL + "/api/ds/query")
+	if err != nil {
+		return 0, errors.Wrapf(err, "POST has failed")
+	}
+
+	if !resp.IsSuccessState() {
+		return 0, errors.Errorf("unsuccessful response: %s", resp)
+	}
+
+	/*---------------------------------------------------*
+	 * Reduce the values
+	 *---------------------------------------------------*/
+	var values []float64
+
+	for refID, result := range dataResp.Results {
+		if result.Error != "" {
+			return 0, errors.Errorf("query '%s' has failed: %s", refID, result.Error)
+		}
+
+		for _, frame := range result.Frames {
+			// The first field of the frame is the timestamp.
+			for i := 1; i < len(frame.Data.Values); i++ {
+				for _, value := range frame.Data.Values[i] {
+					if number, ok := value.(float64); ok {
+						values = append(values, number)
+					}
+				}
+			}
+		}
+	}
+
+	return Reduce(query.Reducer, values)
+}
+
+// ErrNoValues is returned when there are no values to reduce.
+var ErrNoValues = errors.New("no values")
+
+// Reduce reduces the values into a single number.
+func Reduce(reducer string, values []float64) (float64, error) {
+	if reducer == "count" {
+		return float64(len(values)), nil
+	}
+
+	if len(values) == 0 {
+		return 0, ErrNoValues
+	}
+
+	switch reducer {
+	case "avg", "sum":
+		var sum float64
+
+		for _, value := range values {
+			sum += value
+		}
+
+		if reducer == "sum" {
+			return sum, nil
+		}
+
+		return sum / float64(len(values)), nil
+
+	case "min":
+		minValue := values[0]
+
+		for _, value := range values[1:] {
+			if value < minValue {
+				minValue = value
+			}
+		}
+
+		return minValue, nil
+
+	case "max":
+		maxValue := values[0]
+
+		for _, value := range values[1:] {
+			if value > maxValue {
+				maxValue = value
+			}
+		}
+
+		return maxValue, nil
+
+	case "last":
+		return values[len(values)-1], nil
+
+	case "median":
+		sorted := append([]float64(nil), values...)
+		sort.Float64s(sorted)
+
+		mid := len(sorted) / 2
+
+		if len(sorted)%2 == 1 {
+			return sorted[mid], nil
+		}
+
+		return (sorted[mid-1] + sorted[mid]) / 2, nil
+
+	default:
+		return 0, errors.Errorf("unsupported reducer '%s'", reducer)
+	}
+}
@@ -30,6 +30,11 @@ import (
 	"k8s.io/client-go/tools/remotecommand"
 )
 
+// Interface runs commands into the containers of pods.
+type Interface interface {
+	Exec(ctx context.Context, pod types.NamespacedName, containerID string, command []string, blocking bool) (Result, error)
+}
+
 // Executor implements the remote execution in pods.
 type Executor struct {
 	KubeClient *kubernetes.Clientset
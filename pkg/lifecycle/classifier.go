@@ -19,9 +19,12 @@ package lifecycle
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	utilclock "k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -45,6 +48,9 @@ type ClassifierReader interface {
 	GetRunningJobs(jobName ...string) []client.Object
 	GetSuccessfulJobs(jobName ...string) []client.Object
 	GetFailedJobs(jobName ...string) []client.Object
+
+	// Now returns the time that the classified jobs are observed at.
+	Now() time.Time
 }
 
 var _ ClassifierReader = (*Classifier)(nil)
@@ -57,6 +63,33 @@ type Classifier struct {
 	successfulJobs map[string]client.Object
 	failedJobs     map[string]client.Object
 	systemJobs     map[string]client.Object
+
+	// lifecycles holds the lifecycle of every classified job, as returned by the convertor.
+	lifecycles map[string]v1alpha1.Lifecycle
+
+	// transitions, if set, holds the recorded phase transitions of the jobs.
+	transitions map[string]v1alpha1.ActionTransitions
+
+	// clock is the source of time for the time-aware functions. If nil, the wall clock is used.
+	clock utilclock.PassiveClock
+}
+
+// NewClassifier returns a classifier that uses the given source of time.
+func NewClassifier(clock utilclock.PassiveClock) *Classifier {
+	return &Classifier{clock: clock}
+}
+
+// SetClock sets the source of time for the time-aware functions. Unlike the classified jobs, it is kept by Reset.
+func (in *Classifier) SetClock(clock utilclock.PassiveClock) {
+	in.clock = clock
+}
+
+func (in *Classifier) Now() time.Time {
+	if in.clock == nil {
+		return time.Now()
+	}
+
+	return in.clock.Now()
 }
 
 func (in *Classifier) Reset() {
@@ -65,6 +98,14 @@ func (in *Classifier) Reset() {
 	in.successfulJobs = make(map[string]client.Object)
 	in.failedJobs = make(map[string]client.Object)
 	in.systemJobs = make(map[string]client.Object)
+	in.lifecycles = make(map[string]v1alpha1.Lifecycle)
+	in.transitions = nil
+}
+
+// SetTransitions sets the recorded phase transitions of the jobs. They are used for measuring the time in phase
+// of jobs whose lifecycle does not record the transition (e.g, services). The transitions are cleared by Reset.
+func (in *Classifier) SetTransitions(transitions map[string]v1alpha1.ActionTransitions) {
+	in.transitions = transitions
 }
 
 type Convertor func(object client.Object) v1alpha1.Lifecycle
@@ -73,6 +114,10 @@ type Convertor func(object client.Object) v1alpha1.Lifecycle
 func (in *Classifier) ClassifyExternal(name string, obj client.Object, conv Convertor) {
 	status := conv(obj)
 
+	if status.Phase != v1alpha1.PhaseUninitialized {
+		in.lifecycles[name] = status
+	}
+
 	switch status.Phase {
 	case v1alpha1.PhaseUninitialized:
 		// Ignore uninitialized/unscheduled jobs
@@ -99,6 +144,10 @@ func (in *Classifier) Classify(name string, obj client.Object) {
 	if statusAware, getStatus := obj.(v1alpha1.ReconcileStatusAware); getStatus {
 		status := statusAware.GetReconcileStatus()
 
+		if status.Phase != v1alpha1.PhaseUninitialized {
+			in.lifecycles[name] = status
+		}
+
 		// == Handle System resources. ==
 		// Resources of this type have the following rules:
 		// 1) Are ignored by Pending(), Running(), and Successful() calls, as well as from Count().
@@ -382,3 +431,104 @@ func (in *Classifier) GetFailedJobs(jobNames ...string) []client.Object {
 
 	return list
 }
+
+func (in *Classifier) Phase(job string) string {
+	return in.lifecycles[job].Phase.String()
+}
+
+func (in *Classifier) Reason(job string) string {
+	return in.lifecycles[job].Reason
+}
+
+func (in *Classifier) TimeInPhase(job string) time.Duration {
+	status, ok := in.lifecycles[job]
+	if !ok {
+		return 0
+	}
+
+	// Pending jobs are pending since their creation.
+	if status.Phase.Is(v1alpha1.PhasePending) {
+		obj := in.getJob(job)
+		if obj == nil {
+			return 0
+		}
+
+		created := obj.GetCreationTimestamp()
+		if created.IsZero() {
+			return 0
+		}
+
+		return in.Now().Sub(created.Time)
+	}
+
+	since := TransitionTime(status, status.Phase)
+	if since == nil {
+		transitions := in.transitions[job]
+		since = transitions.Get(status.Phase)
+	}
+
+	// an unrecorded transition means that the job has just been observed in this phase.
+	if since == nil {
+		return 0
+	}
+
+	return in.Now().Sub(since.Time)
+}
+
+func (in *Classifier) NumRestarts(job string) int {
+	switch obj := in.getJob(job).(type) {
+	case *v1alpha1.Service:
+		return int(obj.Status.Restarts)
+
+	case *v1alpha1.Cluster:
+		return int(obj.Status.Restarts)
+
+	case *corev1.Pod:
+		restarts := 0
+
+		for _, container := range obj.Status.ContainerStatuses {
+			restarts += int(container.RestartCount)
+		}
+
+		return restarts
+
+	default:
+		return 0
+	}
+}
+
+func (in *Classifier) MaxRestarts() int {
+	max := 0
+
+	for job := range in.lifecycles {
+		if restarts := in.NumRestarts(job); restarts > max {
+			max = restarts
+		}
+	}
+
+	return max
+}
+
+// TotalRestarts returns the sum of container restarts among the jobs.
+func (in *Classifier) TotalRestarts() int {
+	total := 0
+
+	for job := range in.lifecycles {
+		total += in.NumRestarts(job)
+	}
+
+	return total
+}
+
+// getJob returns the classified job with the given name, or nil if the job is not classified.
+func (in *Classifier) getJob(job string) client.Object {
+	for _, jobs := range []map[string]client.Object{
+		in.pendingJobs, in.runningJobs, in.successfulJobs, in.failedJobs, in.systemJobs,
+	} {
+		if obj, exists := jobs[job]; exists {
+			return obj
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	Convertors map the status of external (non-Frisbee) objects to a Frisbee Lifecycle.
+	They are registered per GroupKind, and are used along with ClassifyExternal.
+*/
+
+var (
+	convertorsMu sync.RWMutex
+	convertors   = map[schema.GroupKind]Convertor{}
+)
+
+func init() {
+	RegisterConvertor(schema.GroupKind{Group: corev1.GroupName, Kind: "Pod"}, convertPodLifecycle)
+	RegisterConvertor(schema.GroupKind{Group: batchv1.GroupName, Kind: "Job"}, convertJobLifecycle)
+	RegisterConvertor(schema.GroupKind{Group: appsv1.GroupName, Kind: "Deployment"}, convertDeploymentLifecycle)
+	RegisterConvertor(schema.GroupKind{Group: appsv1.GroupName, Kind: "StatefulSet"}, convertStatefulSetLifecycle)
+}
+
+// RegisterConvertor sets the convertor for the objects of the given GroupKind. Existing convertors are replaced.
+func RegisterConvertor(gk schema.GroupKind, conv Convertor) {
+	convertorsMu.Lock()
+	defer convertorsMu.Unlock()
+
+	convertors[gk] = conv
+}
+
+// GetConvertor returns the convertor for the objects of the given GroupKind. Kinds without a registered
+// convertor have no progress to track (e.g, ConfigMaps), and are regarded as successful once they are created.
+func GetConvertor(gk schema.GroupKind) Convertor {
+	convertorsMu.RLock()
+	defer convertorsMu.RUnlock()
+
+	if conv, exists := convertors[gk]; exists {
+		return conv
+	}
+
+	return convertCreatedLifecycle
+}
+
+// ConvertExternal returns the lifecycle of the object, using the convertor of its kind.
+func ConvertExternal(obj client.Object) v1alpha1.Lifecycle {
+	return GetConvertor(obj.GetObjectKind().GroupVersionKind().GroupKind())(obj)
+}
+
+func convertCreatedLifecycle(client.Object) v1alpha1.Lifecycle {
+	return v1alpha1.Lifecycle{
+		Phase:   v1alpha1.PhaseSuccess,
+		Reason:  "Created",
+		Message: "object is created",
+	}
+}
+
+// fromUnstructured decodes the unstructured object into the given typed object.
+func fromUnstructured(obj client.Object, typed interface{}) *v1alpha1.Lifecycle {
+	unstructuredObj, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return &v1alpha1.Lifecycle{
+			Phase:   v1alpha1.PhaseFailed,
+			Reason:  "Interoperability",
+			Message: fmt.Sprintf("expected unstructured object but got '%T'", obj),
+		}
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, typed); err != nil {
+		return &v1alpha1.Lifecycle{
+			Phase:   v1alpha1.PhaseFailed,
+			Reason:  "Interoperability",
+			Message: fmt.Sprintf("cannot parse '%s': %s", obj.GetName(), err),
+		}
+	}
+
+	return nil
+}
+
+// convertPodLifecycle maps the phase of a standalone Pod to a Frisbee Lifecycle. Unlike the Pods of services,
+// the containers of an applied Pod have no main/sidecar roles, and therefore the Pod phase is followed as is.
+// Containers that keep restarting (e.g, CrashLoopBackOff) are reported in the message of the running Pod.
+func convertPodLifecycle(obj client.Object) v1alpha1.Lifecycle {
+	var pod corev1.Pod
+
+	if lf := fromUnstructured(obj, &pod); lf != nil {
+		return *lf
+	}
+
+	switch pod.Status.Phase {
+	case corev1.PodRunning:
+		for _, container := range pod.Status.ContainerStatuses {
+			if waiting := container.State.Waiting; waiting != nil {
+				return v1alpha1.Lifecycle{
+					Phase:   v1alpha1.PhaseRunning,
+					Reason:  waiting.Reason,
+					Message: fmt.Sprintf("container '%s' is waiting: %s", container.Name, waiting.Message),
+				}
+			}
+		}
+
+		return v1alpha1.Lifecycle{Phase: v1alpha1.PhaseRunning, Reason: "PodRunning", Message: pod.Status.Message}
+
+	case corev1.PodSucceeded:
+		return v1alpha1.Lifecycle{Phase: v1alpha1.PhaseSuccess, Reason: "PodSucceeded", Message: pod.Status.Message}
+
+	case corev1.PodFailed:
+		return v1alpha1.Lifecycle{Phase: v1alpha1.PhaseFailed, Reason: pod.Status.Reason, Message: pod.Status.Message}
+
+	default:
+		// pending, or unknown (e.g, the node is unreachable).
+		return v1alpha1.Lifecycle{Phase: v1alpha1.PhasePending, Reason: "PodPending", Message: pod.Status.Message}
+	}
+}
+
+func convertJobLifecycle(obj client.Object) v1alpha1.Lifecycle {
+	var job batchv1.Job
+
+	if lf := fromUnstructured(obj, &job); lf != nil {
+		return *lf
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch cond.Type {
+		case batchv1.JobFailed:
+			return v1alpha1.Lifecycle{Phase: v1alpha1.PhaseFailed, Reason: cond.Reason, Message: cond.Message}
+
+		case batchv1.JobComplete:
+			return v1alpha1.Lifecycle{Phase: v1alpha1.PhaseSuccess, Reason: "JobComplete", Message: "job is completed"}
+		}
+	}
+
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+
+	switch {
+	case job.Status.Succeeded >= completions:
+		return v1alpha1.Lifecycle{
+			Phase:   v1alpha1.PhaseSuccess,
+			Reason:  "JobComplete",
+			Message: fmt.Sprintf("succeeded: %d/%d", job.Status.Succeeded, completions),
+		}
+
+	case job.Status.Active > 0:
+		return v1alpha1.Lifecycle{
+			Phase:   v1alpha1.PhaseRunning,
+			Reason:  "JobActive",
+			Message: fmt.Sprintf("active: %d. succeeded: %d/%d", job.Status.Active, job.Status.Succeeded, completions),
+		}
+
+	default:
+		return v1alpha1.Lifecycle{Phase: v1alpha1.PhasePending, Reason: "JobPending", Message: "job is not yet active"}
+	}
+}
+
+func convertDeploymentLifecycle(obj client.Object) v1alpha1.Lifecycle {
+	var deployment appsv1.Deployment
+
+	if lf := fromUnstructured(obj, &deployment); lf != nil {
+		return *lf
+	}
+
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse &&
+			cond.Reason == "ProgressDeadlineExceeded" {
+			return v1alpha1.Lifecycle{Phase: v1alpha1.PhaseFailed, Reason: cond.Reason, Message: cond.Message}
+		}
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	status := deployment.Status
+
+	if status.ObservedGeneration >= deployment.GetGeneration() &&
+		status.UpdatedReplicas == replicas && status.AvailableReplicas == replicas && status.Replicas == replicas {
+		return v1alpha1.Lifecycle{
+			Phase:   v1alpha1.PhaseRunning,
+			Reason:  "DeploymentAvailable",
+			Message: fmt.Sprintf("available: %d/%d", status.AvailableReplicas, replicas),
+		}
+	}
+
+	return v1alpha1.Lifecycle{
+		Phase:   v1alpha1.PhasePending,
+		Reason:  "DeploymentProgressing",
+		Message: fmt.Sprintf("updated: %d. available: %d/%d", status.UpdatedReplicas, status.AvailableReplicas, replicas),
+	}
+}
+
+func convertStatefulSetLifecycle(obj client.Object) v1alpha1.Lifecycle {
+	var statefulSet appsv1.StatefulSet
+
+	if lf := fromUnstructured(obj, &statefulSet); lf != nil {
+		return *lf
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	status := statefulSet.Status
+
+	if status.ObservedGeneration >= statefulSet.GetGeneration() &&
+		status.ReadyReplicas == replicas && status.UpdatedReplicas == replicas {
+		return v1alpha1.Lifecycle{
+			Phase:   v1alpha1.PhaseRunning,
+			Reason:  "StatefulSetReady",
+			Message: fmt.Sprintf("ready: %d/%d", status.ReadyReplicas, replicas),
+		}
+	}
+
+	return v1alpha1.Lifecycle{
+		Phase:   v1alpha1.PhasePending,
+		Reason:  "StatefulSetProgressing",
+		Message: fmt.Sprintf("updated: %d. ready: %d/%d", status.UpdatedReplicas, status.ReadyReplicas, replicas),
+	}
+}
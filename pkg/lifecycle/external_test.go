@@ -0,0 +1,214 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+// toUnstructured converts the typed object into an unstructured one, as returned by the client for arbitrary kinds.
+func toUnstructured(t *testing.T, obj runtime.Object) *unstructured.Unstructured {
+	t.Helper()
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("cannot convert '%T': %v", obj, err)
+	}
+
+	return &unstructured.Unstructured{Object: content}
+}
+
+func TestConvertExternal(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  runtime.Object
+		want v1alpha1.Phase
+	}{
+		{
+			name: "job pending",
+			obj:  &batchv1.Job{TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"}},
+			want: v1alpha1.PhasePending,
+		},
+		{
+			name: "job active",
+			obj: &batchv1.Job{
+				TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+				Spec:     batchv1.JobSpec{Completions: pointer.Int32(3)},
+				Status:   batchv1.JobStatus{Active: 2, Succeeded: 1},
+			},
+			want: v1alpha1.PhaseRunning,
+		},
+		{
+			name: "job completions",
+			obj: &batchv1.Job{
+				TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+				Spec:     batchv1.JobSpec{Completions: pointer.Int32(3)},
+				Status:   batchv1.JobStatus{Succeeded: 3},
+			},
+			want: v1alpha1.PhaseSuccess,
+		},
+		{
+			name: "job complete",
+			obj: &batchv1.Job{
+				TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+				Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+				}},
+			},
+			want: v1alpha1.PhaseSuccess,
+		},
+		{
+			name: "job failed",
+			obj: &batchv1.Job{
+				TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+				Status: batchv1.JobStatus{Active: 1, Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+				}},
+			},
+			want: v1alpha1.PhaseFailed,
+		},
+		{
+			name: "deployment progressing",
+			obj: &appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				Spec:     appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+				Status:   appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1},
+			},
+			want: v1alpha1.PhasePending,
+		},
+		{
+			name: "deployment available",
+			obj: &appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				Spec:     appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+				Status:   appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			},
+			want: v1alpha1.PhaseRunning,
+		},
+		{
+			name: "deployment outdated generation",
+			obj: &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: appsv1.DeploymentStatus{
+					ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1,
+				},
+			},
+			want: v1alpha1.PhasePending,
+		},
+		{
+			name: "deployment deadline exceeded",
+			obj: &appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+				}},
+			},
+			want: v1alpha1.PhaseFailed,
+		},
+		{
+			name: "statefulset progressing",
+			obj: &appsv1.StatefulSet{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+				Spec:     appsv1.StatefulSetSpec{Replicas: pointer.Int32(3)},
+				Status:   appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 3},
+			},
+			want: v1alpha1.PhasePending,
+		},
+		{
+			name: "statefulset ready",
+			obj: &appsv1.StatefulSet{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+				Spec:     appsv1.StatefulSetSpec{Replicas: pointer.Int32(3)},
+				Status:   appsv1.StatefulSetStatus{ReadyReplicas: 3, UpdatedReplicas: 3},
+			},
+			want: v1alpha1.PhaseRunning,
+		},
+		{
+			name: "pod pending",
+			obj:  &corev1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}},
+			want: v1alpha1.PhasePending,
+		},
+		{
+			name: "pod running",
+			obj: &corev1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			want: v1alpha1.PhaseRunning,
+		},
+		{
+			name: "pod crash loop",
+			obj: &corev1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:         "main",
+						RestartCount: 3,
+						State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					}},
+				},
+			},
+			want: v1alpha1.PhaseRunning,
+		},
+		{
+			name: "pod succeeded",
+			obj: &corev1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+			want: v1alpha1.PhaseSuccess,
+		},
+		{
+			name: "pod failed",
+			obj: &corev1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"},
+			},
+			want: v1alpha1.PhaseFailed,
+		},
+		{
+			name: "configmap created",
+			obj:  &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}},
+			want: v1alpha1.PhaseSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertExternal(toUnstructured(t, tt.obj))
+
+			if got.Phase != tt.want {
+				t.Errorf("ConvertExternal() = %v (%s), want %v", got.Phase, got.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertExternal_Typed(t *testing.T) {
+	job := &batchv1.Job{TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"}}
+
+	if got := ConvertExternal(job); got.Phase != v1alpha1.PhaseFailed || got.Reason != "Interoperability" {
+		t.Errorf("ConvertExternal() = %v (%s), want an interoperability failure", got.Phase, got.Reason)
+	}
+}
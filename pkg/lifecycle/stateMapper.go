@@ -197,6 +197,8 @@ func GroupedJobs(totalJobs int, state ClassifierReader, lf *v1alpha1.Lifecycle,
 		*lf = *updatedLF
 
 		if updatedCond != nil {
+			updatedCond.LastTransitionTime = metav1.NewTime(state.Now())
+
 			meta.SetStatusCondition(&lf.Conditions, *updatedCond)
 		}
 
@@ -296,6 +298,8 @@ func SingleJob(state ClassifierReader, lf *v1alpha1.Lifecycle) bool {
 				*lf = testcase.lifecycle
 
 				if testcase.condition != (metav1.Condition{}) {
+					testcase.condition.LastTransitionTime = metav1.NewTime(state.Now())
+
 					meta.SetStatusCondition(&lf.Conditions, testcase.condition)
 				}
 
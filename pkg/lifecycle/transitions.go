@@ -0,0 +1,57 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// phaseConditions are the conditions that the lifecycle setters raise on the transition to a phase.
+var phaseConditions = map[v1alpha1.Phase][]v1alpha1.ConditionType{
+	v1alpha1.PhasePending: {v1alpha1.ConditionCRInitialized},
+	v1alpha1.PhaseRunning: {v1alpha1.ConditionAllJobsAreScheduled},
+	v1alpha1.PhaseSuccess: {v1alpha1.ConditionAllJobsAreCompleted},
+	v1alpha1.PhaseFailed: {
+		v1alpha1.ConditionJobUnexpectedTermination,
+		v1alpha1.ConditionAssertionError,
+		v1alpha1.ConditionDeadlineExceeded,
+		v1alpha1.ConditionInvalidStateTransition,
+	},
+}
+
+// TransitionTime returns the time the lifecycle has transitioned to the given phase, as recorded by the
+// LastTransitionTime of the conditions. If there are more matching conditions, the earliest is returned.
+// If the transition is not recorded, it returns nil.
+func TransitionTime(lf v1alpha1.Lifecycle, phase v1alpha1.Phase) *metav1.Time {
+	var earliest *metav1.Time
+
+	for _, condType := range phaseConditions[phase] {
+		for _, cond := range lf.Conditions {
+			if cond.Type != condType.String() || cond.Status != metav1.ConditionTrue || cond.LastTransitionTime.IsZero() {
+				continue
+			}
+
+			if earliest == nil || cond.LastTransitionTime.Before(earliest) {
+				at := cond.LastTransitionTime
+				earliest = &at
+			}
+		}
+	}
+
+	return earliest
+}
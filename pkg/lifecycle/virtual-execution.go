@@ -35,6 +35,7 @@ import (
 // If the callback function fails, it will be reflected in the created virtual jobs and should be captured
 // by the parent's lifecycle.
 // If this function cannot create a virtual object (e.g, cannot create a virtual object), it will return an error.
+// The callback is run by the environment of the reconciler, which normally runs it in the background.
 func CreateVirtualJob(ctx context.Context, reconciler common.Reconciler,
 	parent client.Object,
 	jobName string,
@@ -91,14 +92,14 @@ func CreateVirtualJob(ctx context.Context, reconciler common.Reconciler,
 		return true, nil
 	}
 
-	if err := wait.ExponentialBackoffWithContext(ctx, common.DefaultBackoffForServiceEndpoint, retryCond); err != nil {
+	if err := wait.ExponentialBackoffWithContext(ctx, reconciler.GetEnvironment().BackoffForServiceEndpoint, retryCond); err != nil {
 		return errors.Wrapf(err, "failed to retrieve virtual object '%s']", vObjKey)
 	}
 
 	/*---------------------------------------------------
 	 * Run the callback function asynchronously
 	 *---------------------------------------------------*/
-	go func() {
+	reconciler.GetEnvironment().RunAsync(func() {
 		callbackJobErr := callback(&vJob)
 
 		// resolve the status
@@ -127,7 +128,7 @@ func CreateVirtualJob(ctx context.Context, reconciler common.Reconciler,
 		if err := common.UpdateStatus(ctx, reconciler, &vJob); err != nil {
 			reconciler.GetEventRecorderFor(parent.GetName()).Event(parent, corev1.EventTypeWarning, "VExecUpdateError", err.Error())
 		}
-	}()
+	})
 
 	return nil
 }
@@ -2,6 +2,7 @@ package process
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -44,6 +45,33 @@ func ExecuteInDir(dir string, command string, arguments ...string) (out []byte,
 	return buffer.Bytes(), nil
 }
 
+// ExecuteContext runs system command and returns whole output also in case of error. The process is killed
+// if the context is done before the command completes.
+func ExecuteContext(ctx context.Context, command string, arguments ...string) (out []byte, err error) {
+	cmd := exec.CommandContext(ctx, command, arguments...)
+
+	cmd.Env = os.Environ()
+	cmd.Env = append(cmd.Env, GoEnviron...)
+
+	buffer := new(bytes.Buffer)
+	cmd.Stdout = buffer
+	cmd.Stderr = buffer
+
+	if err = cmd.Start(); err != nil {
+		return buffer.Bytes(), fmt.Errorf("could not start process: %w", err)
+	}
+
+	if err = cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return buffer.Bytes(), fmt.Errorf("process killed: %w\noutput: %s", ctx.Err(), buffer.String())
+		}
+
+		return buffer.Bytes(), fmt.Errorf("process error: %w\noutput: %s", err, buffer.String())
+	}
+
+	return buffer.Bytes(), nil
+}
+
 // LoggedExecuteInDir runs system command and returns whole output also in case of error in a specific directory with logging to writer
 func LoggedExecuteInDir(dir string, writer io.Writer, command string, arguments ...string) (out []byte, err error) {
 	cmd := exec.Command(command, arguments...)
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
 	"github.com/carv-ics-forth/frisbee/pkg/expressions"
 	"github.com/carv-ics-forth/frisbee/pkg/lifecycle"
 	"github.com/go-logr/logr"
@@ -31,9 +32,12 @@ import (
 )
 
 type Parameters struct {
-	// State is the real state of the system.
+	// State is the real state of the system. Its clock is the source of time for the scheduling.
 	State lifecycle.Classifier
 
+	// Env is the environment that event-based conditions are evaluated in.
+	Env *common.Environment
+
 	// LastScheduleTime is the time the controller last scheduled an object.
 	LastScheduleTime metav1.Time
 
@@ -107,9 +111,14 @@ func Schedule(log logr.Logger, obj client.Object, params Parameters) (goToNextJo
 
 	// Event-based scheduling
 	if !params.ScheduleSpec.Event.IsZero() {
-		eval := expressions.Condition{Expr: params.ScheduleSpec.Event}
+		eval := expressions.Condition{Expr: params.ScheduleSpec.Event, Env: params.Env}
+
+		if eval.IsTrue(&params.State, obj) {
+			return true, time.Time{}, nil
+		}
 
-		return eval.IsTrue(&params.State, obj), time.Time{}, nil
+		// unlike the phases of the jobs, PromQL and time-dependent expressions are not watched, and must be polled.
+		return false, expressions.NextPolledEvaluation(params.State.Now(), params.ScheduleSpec.Event), nil
 	}
 
 	panic("this should never happen")
@@ -137,7 +146,11 @@ func cronWithDeadline(_ logr.Logger, obj client.Object, params Parameters) (last
 }
 
 func timelineWithDeadline(_ logr.Logger, obj client.Object, params Parameters) (lastMissed time.Time, next time.Time, err error) {
-	timeline := params.ExpectedTimeline
+	now := params.State.Now()
+
+	timeline := TimelineFunc(func(ref time.Time) time.Time {
+		return params.ExpectedTimeline.NextAt(ref, now)
+	})
 
 	lastMissed, next, err = getNextScheduleTime(obj.GetCreationTimestamp().Time, timeline, params)
 	if err != nil {
@@ -161,6 +174,13 @@ type Timeline interface {
 	Next(time.Time) time.Time
 }
 
+// TimelineFunc is an adapter to allow the use of ordinary functions as timelines.
+type TimelineFunc func(time.Time) time.Time
+
+func (f TimelineFunc) Next(ref time.Time) time.Time {
+	return f(ref)
+}
+
 // getNextScheduleTime figure out the next times that we need to create jobs at (or anything we missed).
 //
 // We'll start calculating appropriate times from our last run, or the creation
@@ -172,7 +192,7 @@ type Timeline interface {
 // Otherwise, we'll just return the missed runs (of which we'll just use the latest),
 // and the next run, so that we can know when it's time to reconcile again.
 func getNextScheduleTime(earliest time.Time, timeline Timeline, params Parameters) (lastMissed time.Time, next time.Time, err error) {
-	now := time.Now()
+	now := params.State.Now()
 
 	var earliestTime time.Time
 
@@ -0,0 +1,145 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/scheduler"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestSchedule(t *testing.T) {
+	created := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	everyMinute := "*/1 * * * *"
+
+	timeline := v1alpha1.Timeline{
+		metav1.NewTime(created.Add(10 * time.Second)),
+		metav1.NewTime(created.Add(40 * time.Second)),
+	}
+
+	type args struct {
+		now    time.Time
+		params scheduler.Parameters
+	}
+
+	tests := []struct {
+		name        string
+		args        args
+		goToNextJob bool
+		nextTick    time.Time
+		wantErr     bool
+	}{
+		{
+			name: "no constraints",
+			args: args{
+				now:    created,
+				params: scheduler.Parameters{},
+			},
+			goToNextJob: true,
+			nextTick:    time.Time{},
+		},
+		{
+			name: "cron before the first tick",
+			args: args{
+				now: created.Add(30 * time.Second),
+				params: scheduler.Parameters{
+					ScheduleSpec: &v1alpha1.TaskSchedulerSpec{Cron: &everyMinute},
+				},
+			},
+			goToNextJob: false,
+			nextTick:    created.Add(time.Minute),
+		},
+		{
+			name: "cron after the first tick",
+			args: args{
+				now: created.Add(70 * time.Second),
+				params: scheduler.Parameters{
+					ScheduleSpec: &v1alpha1.TaskSchedulerSpec{Cron: &everyMinute},
+				},
+			},
+			goToNextJob: true,
+			nextTick:    created.Add(2 * time.Minute),
+		},
+		{
+			name: "cron after the last schedule",
+			args: args{
+				now: created.Add(90 * time.Second),
+				params: scheduler.Parameters{
+					ScheduleSpec:     &v1alpha1.TaskSchedulerSpec{Cron: &everyMinute},
+					LastScheduleTime: metav1.NewTime(created.Add(time.Minute)),
+				},
+			},
+			goToNextJob: false,
+			nextTick:    created.Add(2 * time.Minute),
+		},
+		{
+			name: "timeline at the first tick",
+			args: args{
+				now: created.Add(10 * time.Second),
+				params: scheduler.Parameters{
+					ScheduleSpec:     &v1alpha1.TaskSchedulerSpec{Timeline: &v1alpha1.TimelineDistributionSpec{}},
+					ExpectedTimeline: timeline,
+				},
+			},
+			goToNextJob: true,
+			nextTick:    created.Add(40 * time.Second),
+		},
+		{
+			name: "timeline after the last tick",
+			args: args{
+				now: created.Add(time.Minute),
+				params: scheduler.Parameters{
+					ScheduleSpec:     &v1alpha1.TaskSchedulerSpec{Timeline: &v1alpha1.TimelineDistributionSpec{}},
+					ExpectedTimeline: timeline,
+					LastScheduleTime: metav1.NewTime(created.Add(40 * time.Second)),
+				},
+			},
+			// without further ticks, the next tick is pushed far in the future.
+			goToNextJob: false,
+			nextTick:    created.Add(time.Minute + 12*time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.params.State.SetClock(clocktesting.NewFakePassiveClock(tt.args.now))
+
+			obj := &v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+
+			goToNextJob, nextTick, err := scheduler.Schedule(logr.Discard(), obj, tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Schedule() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if goToNextJob != tt.goToNextJob {
+				t.Errorf("Schedule() goToNextJob = %v, want %v", goToNextJob, tt.goToNextJob)
+			}
+
+			if !nextTick.Equal(tt.nextTick) {
+				t.Errorf("Schedule() nextTick = %v, want %v", nextTick, tt.nextTick)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/kubexec"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// manager provides the controllers with the fake client and the event recorder. The rest of the methods
+// of the manager are not used by the reconcilers, and are left unimplemented.
+type manager struct {
+	ctrl.Manager
+
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder *recorder
+}
+
+func (m *manager) GetClient() client.Client {
+	return m.client
+}
+
+func (m *manager) GetScheme() *runtime.Scheme {
+	return m.scheme
+}
+
+func (m *manager) GetEventRecorderFor(string) record.EventRecorder {
+	return m.recorder
+}
+
+// Event is an event emitted by the controllers during the simulation.
+type Event struct {
+	Time    metav1.Time `json:"time"`
+	Kind    string      `json:"kind"`
+	Object  string      `json:"object"`
+	Type    string      `json:"type"`
+	Reason  string      `json:"reason"`
+	Message string      `json:"message"`
+}
+
+// EventList is the list of events, in the order they are emitted.
+type EventList []Event
+
+func (in EventList) Table() (header []string, data [][]string) {
+	header = []string{"Time", "Kind", "Object", "Type", "Reason", "Message"}
+
+	if len(in) == 0 {
+		return header, nil
+	}
+
+	start := in[0].Time.Time
+
+	for _, event := range in {
+		data = append(data, []string{
+			fmt.Sprintf("+%s", event.Time.Sub(start)),
+			event.Kind,
+			event.Object,
+			event.Type,
+			event.Reason,
+			event.Message,
+		})
+	}
+
+	return header, data
+}
+
+// recorder records the events with the virtual time.
+type recorder struct {
+	scheme *runtime.Scheme
+	now    func() time.Time
+
+	lock   sync.Mutex
+	events EventList
+}
+
+func (r *recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	event := Event{
+		Time:    metav1.NewTime(r.now()),
+		Type:    eventtype,
+		Reason:  reason,
+		Message: message,
+	}
+
+	if gvk, err := apiutil.GVKForObject(object, r.scheme); err == nil {
+		event.Kind = gvk.Kind
+	}
+
+	if accessor, err := meta.Accessor(object); err == nil {
+		event.Object = accessor.GetName()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *recorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// list returns the recorded events, ordered by time.
+func (r *recorder) list() EventList {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	events := append(EventList{}, r.events...)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(&events[j].Time)
+	})
+
+	return events
+}
+
+// executor simulates the remote execution of calls. The call returns the mocked outputs immediately, but its
+// completion is deferred until the mocked duration has elapsed in virtual time.
+type executor struct {
+	sim *Simulator
+}
+
+var _ kubexec.Interface = (*executor)(nil)
+
+func (e *executor) Exec(_ context.Context, pod types.NamespacedName, _ string, command []string, _ bool) (kubexec.Result, error) {
+	mock := e.sim.mocks.ForCall(pod.Name)
+
+	if mock.Duration != nil {
+		e.sim.completion = mock.Duration.Duration
+	}
+
+	result := kubexec.Result{Stdout: mock.Stdout, Stderr: mock.Stderr}
+
+	if mock.outcome().Is(v1alpha1.PhaseFailed) {
+		return result, errors.Errorf("simulated failure of '%v'", command)
+	}
+
+	return result, nil
+}
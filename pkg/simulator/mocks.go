@@ -0,0 +1,162 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultJobDuration is the simulated duration of the jobs that are not matched by any mock.
+const DefaultJobDuration = time.Minute
+
+// Mocks define the simulated duration and outcome of the jobs, since no actual workload is running.
+//
+// Example:
+//
+//	default: { duration: 2m }
+//	jobs:
+//	  - { match: "server*" }                                 # runs until it is deleted
+//	  - { match: "clients-*", duration: 30s }
+//	  - { match: "faulty", duration: 10s, outcome: Failed }
+//	calls:
+//	  - { match: "server", duration: 5s, stdout: "leader=server-1" }
+type Mocks struct {
+	// Default applies to the Services and Chaos jobs that are not matched by any of the Jobs.
+	// If it is not defined, the jobs succeed after DefaultJobDuration.
+	// +optional
+	Default *MockJob `json:"default,omitempty"`
+
+	// Jobs are matched against the names of the Services and Chaos jobs, in the given order.
+	// +optional
+	Jobs []MockJob `json:"jobs,omitempty"`
+
+	// Calls are matched against the names of the services targeted by the calls, in the given order.
+	// Calls that are not matched by any of the mocks succeed immediately, with empty outputs.
+	// +optional
+	Calls []MockJob `json:"calls,omitempty"`
+}
+
+// MockJob defines the simulated behavior of the matching jobs.
+type MockJob struct {
+	// Match is a glob pattern for the name of the job (e.g, 'clients-*').
+	// +optional
+	Match string `json:"match,omitempty"`
+
+	// Duration is the time the job is running. If it is not defined, Services and Chaos jobs run until they are
+	// deleted, whereas calls complete immediately.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Outcome is the phase of the job once the duration has elapsed (Success or Failed). Defaults to Success.
+	// +optional
+	Outcome v1alpha1.Phase `json:"outcome,omitempty"`
+
+	// Stdout is the output of the simulated calls.
+	// +optional
+	Stdout string `json:"stdout,omitempty"`
+
+	// Stderr is the error output of the simulated calls.
+	// +optional
+	Stderr string `json:"stderr,omitempty"`
+}
+
+// LoadMocks reads the mocks from a YAML file.
+func LoadMocks(mocksFile string) (Mocks, error) {
+	var mocks Mocks
+
+	data, err := os.ReadFile(mocksFile)
+	if err != nil {
+		return mocks, errors.Wrapf(err, "cannot read mocks")
+	}
+
+	if err := yaml.UnmarshalStrict(data, &mocks); err != nil {
+		return mocks, errors.Wrapf(err, "cannot decode mocks")
+	}
+
+	return mocks, mocks.Validate()
+}
+
+// Validate ensures that the patterns and the outcomes of the mocks are valid.
+func (in *Mocks) Validate() error {
+	mocks := append(append([]MockJob{}, in.Jobs...), in.Calls...)
+
+	if in.Default != nil {
+		mocks = append(mocks, *in.Default)
+	}
+
+	for _, mock := range mocks {
+		if _, err := path.Match(mock.Match, ""); err != nil {
+			return errors.Wrapf(err, "invalid pattern '%s'", mock.Match)
+		}
+
+		if mock.Outcome != "" && !mock.Outcome.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+			return errors.Errorf("invalid outcome '%s' for '%s'. Expected Success or Failed", mock.Outcome, mock.Match)
+		}
+
+		if mock.Duration != nil && mock.Duration.Duration < 0 {
+			return errors.Errorf("negative duration for '%s'", mock.Match)
+		}
+	}
+
+	return nil
+}
+
+// ForJob returns the mock of the Service or Chaos job with the given name.
+func (in *Mocks) ForJob(name string) MockJob {
+	if mock, ok := match(in.Jobs, name); ok {
+		return mock
+	}
+
+	if in.Default != nil {
+		return *in.Default
+	}
+
+	return MockJob{Duration: &metav1.Duration{Duration: DefaultJobDuration}}
+}
+
+// ForCall returns the mock of the calls to the service with the given name.
+func (in *Mocks) ForCall(service string) MockJob {
+	mock, _ := match(in.Calls, service)
+
+	return mock
+}
+
+func match(mocks []MockJob, name string) (MockJob, bool) {
+	for _, mock := range mocks {
+		if matched, _ := path.Match(mock.Match, name); matched {
+			return mock, true
+		}
+	}
+
+	return MockJob{}, false
+}
+
+// outcome returns the phase of the job once the duration has elapsed.
+func (in MockJob) outcome() v1alpha1.Phase {
+	if in.Outcome == "" {
+		return v1alpha1.PhaseSuccess
+	}
+
+	return in.Outcome
+}
@@ -0,0 +1,809 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulator runs the scenario, cluster, cascade, and call controllers against a fake client, with a
+// virtual clock. Services and Chaos jobs are not deployed, but their duration and outcome are mocked. The outcome
+// is the predicted timeline of the actions, without requiring a Kubernetes cluster or waiting for the wall clock.
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/controllers/call"
+	"github.com/carv-ics-forth/frisbee/controllers/cascade"
+	"github.com/carv-ics-forth/frisbee/controllers/cluster"
+	"github.com/carv-ics-forth/frisbee/controllers/common"
+	"github.com/carv-ics-forth/frisbee/controllers/scenario"
+	"github.com/carv-ics-forth/frisbee/pkg/configuration"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// DefaultHorizon is the maximum virtual duration of a simulation.
+	DefaultHorizon = 24 * time.Hour
+
+	// maxSteps bounds the number of reconciliation cycles, in case the controllers never settle.
+	maxSteps = 100000
+
+	// retryDelay is the virtual delay before requeuing a request that has failed, or asked for an immediate requeue.
+	retryDelay = time.Second
+)
+
+// Options configure the simulation.
+type Options struct {
+	// Mocks define the duration and the outcome of the simulated jobs.
+	Mocks Mocks
+
+	// Horizon is the maximum virtual duration of the simulation. Defaults to DefaultHorizon.
+	Horizon time.Duration
+
+	// Logger receives the logs of the controllers. Defaults to a discarding logger.
+	Logger logr.Logger
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	// Scenario is the final state of the simulated scenario, including its timeline.
+	Scenario *v1alpha1.Scenario
+
+	// StartTime is the virtual time that the simulation has started.
+	StartTime time.Time
+
+	// EndTime is the virtual time that the simulation has ended.
+	EndTime time.Time
+
+	// Events are the events emitted by the controllers.
+	Events EventList
+
+	// Warnings report the features of the scenario that are not simulated, and simulations that did not complete.
+	Warnings []string
+}
+
+// Timeline returns the action timeline of the simulated scenario.
+func (r *Result) Timeline() Timeline {
+	return Timeline{Actions: r.Scenario.Status.Timeline, EndTime: r.EndTime}
+}
+
+// Timeline is the action timeline, as it was at the end of the simulation.
+type Timeline struct {
+	Actions v1alpha1.ActionTimeline `json:"actions"`
+	EndTime time.Time               `json:"endTime"`
+}
+
+func (in Timeline) Table() (header []string, data [][]string) {
+	// incomplete actions are measured until the end of the simulation, rather than until the wall clock.
+	return in.Actions.TableAt(in.EndTime)
+}
+
+// request is a reconciliation request for an object of the given kind.
+type request struct {
+	Kind string
+	ctrl.Request
+}
+
+// timer is a function that fires once the virtual time reaches the given time.
+type timer struct {
+	at   time.Time
+	seq  int
+	fire func()
+}
+
+// Simulator drives the reconciliation of the controllers. It emulates the parts of Kubernetes that the controllers
+// rely upon: the admission webhooks, the watches, the garbage collection, and the Services and Chaos controllers.
+// The controllers, the timers, and the callbacks of the virtual jobs run in a single goroutine, so that the
+// simulation is deterministic.
+type Simulator struct {
+	mocks  Mocks
+	logger logr.Logger
+
+	clock   *clocktesting.FakePassiveClock
+	env     *common.Environment
+	scheme  *runtime.Scheme
+	client  client.Client
+	manager *manager
+
+	reconcilers map[string]reconcile.Reconciler
+
+	queue  []request
+	queued map[request]bool
+
+	timers []timer
+	seq    int
+
+	// completion is the mocked duration of the running call. The completion of the call, i.e, the next update of
+	// the status of a virtual job, is deferred until the duration has elapsed in virtual time.
+	completion time.Duration
+
+	// snapshot is the last observed state of the objects, indexed by kind and key.
+	snapshot map[request]client.Object
+
+	warnings []string
+}
+
+// observedKinds are the kinds of the objects whose changes trigger reconciliation requests.
+var observedKinds = []struct {
+	kind    string
+	newList func() client.ObjectList
+}{
+	{"Scenario", func() client.ObjectList { return &v1alpha1.ScenarioList{} }},
+	{"Cluster", func() client.ObjectList { return &v1alpha1.ClusterList{} }},
+	{"Cascade", func() client.ObjectList { return &v1alpha1.CascadeList{} }},
+	{"Call", func() client.ObjectList { return &v1alpha1.CallList{} }},
+	{"Service", func() client.ObjectList { return &v1alpha1.ServiceList{} }},
+	{"Chaos", func() client.ObjectList { return &v1alpha1.ChaosList{} }},
+	{"VirtualObject", func() client.ObjectList { return &v1alpha1.VirtualObjectList{} }},
+}
+
+// mockedKinds are the kinds of jobs whose lifecycle is mocked, instead of being reconciled.
+var mockedKinds = map[string]bool{
+	"Service": true,
+	"Chaos":   true,
+}
+
+// Run simulates the execution of the scenario, using the given templates.
+func Run(ctx context.Context, scenarioSpec *v1alpha1.Scenario, templates []v1alpha1.Template, options Options) (*Result, error) {
+	if err := options.Mocks.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid mocks")
+	}
+
+	if options.Horizon <= 0 {
+		options.Horizon = DefaultHorizon
+	}
+
+	if options.Logger.GetSink() == nil {
+		options.Logger = logr.Discard()
+	}
+
+	sim := &Simulator{
+		mocks:    options.Mocks,
+		logger:   options.Logger,
+		clock:    clocktesting.NewFakePassiveClock(time.Now().Truncate(time.Second)),
+		scheme:   runtime.NewScheme(),
+		queued:   make(map[request]bool),
+		snapshot: make(map[request]client.Object),
+	}
+
+	utilruntime.Must(clientgoscheme.AddToScheme(sim.scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(sim.scheme))
+
+	sim.env = &common.Environment{
+		Clock: sim.clock,
+
+		// the callbacks of the virtual jobs run within the reconciliation cycle that creates them.
+		RunAsync: func(callback func()) {
+			callback()
+		},
+
+		// the fake client is consistent, so there is no reason to wait for the retries in real time.
+		BackoffForK8sEndpoint: wait.Backoff{
+			Duration: time.Millisecond,
+			Factor:   1,
+			Steps:    common.DefaultBackoffForK8sEndpoint.Steps,
+		},
+		BackoffForServiceEndpoint: wait.Backoff{
+			Duration: time.Millisecond,
+			Factor:   1,
+			Steps:    common.DefaultBackoffForServiceEndpoint.Steps,
+		},
+
+		// there is no Prometheus to query. PromQL expressions remain inconclusive.
+		QueryPrometheus: func(context.Context, metav1.Object, string) (model.Value, error) {
+			return nil, errors.New("prometheus is not simulated")
+		},
+	}
+
+	test := scenarioSpec.DeepCopy()
+
+	if test.GetNamespace() == "" {
+		test.SetNamespace(test.GetName())
+	}
+
+	if err := sim.setup(ctx, test, templates); err != nil {
+		return nil, errors.Wrapf(err, "setup error")
+	}
+
+	start := sim.clock.Now()
+
+	if err := sim.client.Create(ctx, test); err != nil {
+		return nil, errors.Wrapf(err, "cannot create scenario")
+	}
+
+	key := request{Kind: "Scenario", Request: ctrl.Request{NamespacedName: client.ObjectKeyFromObject(test)}}
+
+	if err := sim.run(ctx, key, start.Add(options.Horizon)); err != nil {
+		return nil, err
+	}
+
+	var final v1alpha1.Scenario
+
+	if err := sim.client.Get(ctx, key.NamespacedName, &final); err != nil {
+		return nil, errors.Wrapf(err, "cannot get the simulated scenario")
+	}
+
+	return &Result{
+		Scenario:  &final,
+		StartTime: start,
+		EndTime:   sim.clock.Now(),
+		Events:    sim.manager.recorder.list(),
+		Warnings:  sim.warnings,
+	}, nil
+}
+
+// setup creates the fake client, the controllers, and the objects that the controllers expect to find in the
+// cluster: the system configuration, the nodes, and the templates.
+func (s *Simulator) setup(ctx context.Context, test *v1alpha1.Scenario, templates []v1alpha1.Template) error {
+	s.client = fake.NewClientBuilder().
+		WithScheme(s.scheme).
+		WithStatusSubresource(&v1alpha1.Scenario{}, &v1alpha1.Cluster{}, &v1alpha1.Cascade{}, &v1alpha1.Call{},
+			&v1alpha1.Service{}, &v1alpha1.Chaos{}, &v1alpha1.VirtualObject{}, &v1alpha1.Template{}).
+		WithInterceptorFuncs(interceptor.Funcs{Create: s.admit, SubResourceUpdate: s.complete}).
+		Build()
+
+	s.manager = &manager{
+		client:   s.client,
+		scheme:   s.scheme,
+		recorder: &recorder{scheme: s.scheme, now: s.eventTime},
+	}
+
+	s.reconcilers = map[string]reconcile.Reconciler{
+		"Scenario": scenario.NewReconciler(s.manager, s.logger, s.env),
+		"Cluster":  cluster.NewReconciler(s.manager, s.logger, s.env),
+		"Cascade":  cascade.NewReconciler(s.manager, s.logger, s.env),
+		"Call":     call.NewReconciler(s.manager, s.logger, &executor{sim: s}, s.env),
+	}
+
+	// the telemetry stack and the test data are not simulated.
+	if test.Spec.TestData != nil {
+		test.Spec.TestData = nil
+
+		s.warn("testData is ignored")
+	}
+
+	if hasMetricsExpr(test) {
+		s.warn("metrics and promQL expressions are not simulated, since there is no telemetry. Use state expressions instead")
+	}
+
+	objects := []client.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "simulation-configuration",
+				Namespace: "frisbee",
+				Labels:    map[string]string{v1alpha1.ResourceDiscoveryLabel: configuration.PlatformConfigurationName},
+			},
+			Data: map[string]string{
+				"developerMode":    "false",
+				"namespace":        "frisbee",
+				"domainName":       "frisbee.simulation",
+				"ingressClassName": "simulation",
+				"controllerName":   "frisbee-simulator",
+			},
+		},
+	}
+
+	// placement policies require at least two nodes.
+	for i := 1; i <= 2; i++ {
+		objects = append(objects, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("simulated-node-%d", i)},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("64"),
+					corev1.ResourceMemory: resource.MustParse("256Gi"),
+				},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		})
+	}
+
+	telemetry := false
+
+	for i := range templates {
+		template := templates[i].DeepCopy()
+		template.SetNamespace(test.GetNamespace())
+
+		if template.Spec.EmbedSpecs != nil && template.Spec.Service != nil && len(template.Spec.Service.Decorators.Telemetry) > 0 {
+			template.Spec.Service.Decorators.Telemetry = nil
+			telemetry = true
+		}
+
+		objects = append(objects, template)
+	}
+
+	if telemetry {
+		s.warn("telemetry decorators are ignored")
+	}
+
+	for _, obj := range objects {
+		if err := s.client.Create(ctx, obj); err != nil {
+			return errors.Wrapf(err, "cannot create %T '%s'", obj, obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+// admit emulates the API server on the creation of objects. It sets the creation timestamp with the virtual time,
+// and runs the defaulting and validating webhooks.
+func (s *Simulator) admit(ctx context.Context, cli client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+	obj.SetCreationTimestamp(metav1.NewTime(s.clock.Now()))
+	obj.SetUID(uuid.NewUUID())
+
+	if defaulter, ok := obj.(admission.Defaulter); ok {
+		defaulter.Default()
+	}
+
+	if validator, ok := obj.(admission.Validator); ok {
+		if _, err := validator.ValidateCreate(); err != nil {
+			return k8errors.NewBadRequest(errors.Wrapf(err, "admission webhook denied %T '%s'", obj, obj.GetName()).Error())
+		}
+	}
+
+	return cli.Create(ctx, obj, opts...)
+}
+
+// complete emulates the API server on the update of the status of objects. If a call is running, the update of the
+// status of its virtual job is deferred until the mocked duration of the call has elapsed.
+func (s *Simulator) complete(ctx context.Context, cli client.Client, subResource string, obj client.Object,
+	opts ...client.SubResourceUpdateOption,
+) error {
+	vobj, ok := obj.(*v1alpha1.VirtualObject)
+	if !ok || s.completion == 0 {
+		return cli.SubResource(subResource).Update(ctx, obj, opts...)
+	}
+
+	key := client.ObjectKeyFromObject(vobj)
+	status := vobj.Status.DeepCopy()
+
+	s.schedule(s.clock.Now().Add(s.completion), func() {
+		var job v1alpha1.VirtualObject
+
+		// the job may have been deleted in the meantime.
+		if err := s.client.Get(context.Background(), key, &job); err != nil || !job.GetDeletionTimestamp().IsZero() {
+			return
+		}
+
+		job.Status = *status
+
+		if err := s.client.Status().Update(context.Background(), &job); err != nil {
+			s.logger.Info("Mock error", "kind", "VirtualObject", "obj", key, "err", err)
+		}
+	})
+
+	s.completion = 0
+
+	return nil
+}
+
+// eventTime returns the virtual time of the emitted events. The events of a running call are emitted at
+// its completion.
+func (s *Simulator) eventTime() time.Time {
+	return s.clock.Now().Add(s.completion)
+}
+
+// run reconciles the objects until the scenario is completed, the controllers settle, or the horizon is reached.
+func (s *Simulator) run(ctx context.Context, key request, horizon time.Time) error {
+	for step := 0; step < maxSteps; step++ {
+		if err := s.observe(ctx); err != nil {
+			return errors.Wrapf(err, "observe error")
+		}
+
+		var test v1alpha1.Scenario
+
+		if err := s.client.Get(ctx, key.NamespacedName, &test); err != nil {
+			return errors.Wrapf(err, "cannot get scenario")
+		}
+
+		if len(s.queue) == 0 && completed(&test) {
+			return nil
+		}
+
+		if len(s.queue) > 0 {
+			req := s.queue[0]
+			s.queue = s.queue[1:]
+			delete(s.queued, req)
+
+			if err := s.reconcile(ctx, req); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		next, exists := s.nextTimer()
+		if !exists {
+			s.warn(fmt.Sprintf("the simulation has stalled in phase '%s': %s", test.Status.Phase, test.Status.Message))
+
+			return nil
+		}
+
+		if next.After(horizon) {
+			s.warn(fmt.Sprintf("the simulation has reached the horizon in phase '%s'", test.Status.Phase))
+
+			return nil
+		}
+
+		if next.After(s.clock.Now()) {
+			s.clock.SetTime(next)
+		}
+
+		s.fireTimers()
+	}
+
+	return errors.Errorf("the controllers did not settle after %d reconciliation cycles", maxSteps)
+}
+
+// completed returns true if the scenario has reached a terminal phase, and nothing else is pending.
+func completed(test *v1alpha1.Scenario) bool {
+	if !test.Status.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed) {
+		return false
+	}
+
+	if len(test.Spec.Finally) > 0 && (test.Status.Finally == nil || !test.Status.Finally.Phase.Is(v1alpha1.PhaseSuccess, v1alpha1.PhaseFailed)) {
+		return false
+	}
+
+	if repeat := test.Spec.Repeat; repeat != nil && (test.Status.Trials == nil || len(test.Status.Trials.Results) < repeat.Trials) {
+		return false
+	}
+
+	return len(test.Spec.PostConditions) == 0 || test.Status.PostConditions != nil
+}
+
+// reconcile runs the reconciler of the request, and schedules the requeue, if any.
+func (s *Simulator) reconcile(ctx context.Context, req request) (err error) {
+	reconciler, exists := s.reconcilers[req.Kind]
+	if !exists {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%s controller has panicked on '%s': %v", req.Kind, req.NamespacedName, r)
+		}
+	}()
+
+	result, reconcileErr := reconciler.Reconcile(ctx, req.Request)
+
+	// a call whose virtual job is not updated does not defer the updates of the next cycles.
+	s.completion = 0
+
+	switch {
+	case reconcileErr != nil:
+		s.logger.Info("Reconcile error", "kind", req.Kind, "obj", req.NamespacedName, "err", reconcileErr)
+
+		s.requeueAfter(req, retryDelay)
+
+	case result.RequeueAfter > 0:
+		s.requeueAfter(req, result.RequeueAfter)
+
+	case result.Requeue:
+		s.requeueAfter(req, retryDelay)
+	}
+
+	return nil
+}
+
+func (s *Simulator) enqueue(req request) {
+	if _, exists := s.reconcilers[req.Kind]; !exists || s.queued[req] {
+		return
+	}
+
+	s.queued[req] = true
+	s.queue = append(s.queue, req)
+}
+
+func (s *Simulator) requeueAfter(req request, delay time.Duration) {
+	s.schedule(s.clock.Now().Add(delay), func() { s.enqueue(req) })
+}
+
+// schedule registers a function that fires once the virtual time reaches the given time.
+func (s *Simulator) schedule(at time.Time, fire func()) {
+	s.seq++
+	s.timers = append(s.timers, timer{at: at, seq: s.seq, fire: fire})
+}
+
+// nextTimer returns the time of the earliest timer.
+func (s *Simulator) nextTimer() (time.Time, bool) {
+	if len(s.timers) == 0 {
+		return time.Time{}, false
+	}
+
+	sort.SliceStable(s.timers, func(i, j int) bool {
+		if s.timers[i].at.Equal(s.timers[j].at) {
+			return s.timers[i].seq < s.timers[j].seq
+		}
+
+		return s.timers[i].at.Before(s.timers[j].at)
+	})
+
+	return s.timers[0].at, true
+}
+
+// fireTimers fires the timers whose time has come, in the order they were scheduled.
+func (s *Simulator) fireTimers() {
+	now := s.clock.Now()
+
+	var due, pending []timer
+
+	for _, t := range s.timers {
+		if t.at.After(now) {
+			pending = append(pending, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+
+	s.timers = pending
+
+	for _, t := range due {
+		t.fire()
+	}
+}
+
+// observe emulates the watches of the controllers. An object is reconciled on every change, and its owner is
+// reconciled when the phase of the object changes or the object is deleted. Orphaned objects are garbage collected,
+// and the lifecycle of new Services and Chaos jobs is mocked.
+func (s *Simulator) observe(ctx context.Context) error {
+	for {
+		current, err := s.list(ctx)
+		if err != nil {
+			return err
+		}
+
+		for key, obj := range current {
+			prev, exists := s.snapshot[key]
+
+			switch {
+			case !exists:
+				s.enqueue(key)
+
+				if mockedKinds[key.Kind] {
+					s.mock(key, obj)
+				}
+
+			case changed(prev, obj):
+				s.enqueue(key)
+
+				if phaseOf(prev) != phaseOf(obj) {
+					s.enqueueOwner(obj)
+				}
+			}
+		}
+
+		for key, prev := range s.snapshot {
+			if _, exists := current[key]; !exists {
+				s.enqueueOwner(prev)
+			}
+		}
+
+		s.snapshot = current
+
+		// garbage collection may delete further objects. Repeat until the state is stable.
+		if collected := s.collectGarbage(ctx, current); !collected {
+			return nil
+		}
+	}
+}
+
+// list returns the observed objects, indexed by kind and key.
+func (s *Simulator) list(ctx context.Context) (map[request]client.Object, error) {
+	objects := make(map[request]client.Object)
+
+	for _, observed := range observedKinds {
+		list := observed.newList()
+
+		if err := s.client.List(ctx, list); err != nil {
+			return nil, errors.Wrapf(err, "cannot list %s", observed.kind)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot extract %s", observed.kind)
+		}
+
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+
+			objects[request{Kind: observed.kind, Request: ctrl.Request{NamespacedName: client.ObjectKeyFromObject(obj)}}] = obj
+		}
+	}
+
+	return objects, nil
+}
+
+// collectGarbage deletes the objects whose controller no longer exists. It returns true if any object is deleted.
+func (s *Simulator) collectGarbage(ctx context.Context, objects map[request]client.Object) bool {
+	collected := false
+
+	for _, obj := range objects {
+		owner := metav1.GetControllerOf(obj)
+		if owner == nil {
+			continue
+		}
+
+		key := request{Kind: owner.Kind, Request: ctrl.Request{NamespacedName: client.ObjectKey{
+			Namespace: obj.GetNamespace(),
+			Name:      owner.Name,
+		}}}
+
+		if _, exists := objects[key]; exists || !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+
+		if err := s.client.Delete(ctx, obj); err == nil {
+			collected = true
+		}
+	}
+
+	return collected
+}
+
+func (s *Simulator) enqueueOwner(obj client.Object) {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return
+	}
+
+	s.enqueue(request{Kind: owner.Kind, Request: ctrl.Request{NamespacedName: client.ObjectKey{
+		Namespace: obj.GetNamespace(),
+		Name:      owner.Name,
+	}}})
+}
+
+// mock emulates the controller of a new Service or Chaos job. The job is running once it is created, and it is
+// completed with the mocked outcome once the mocked duration has elapsed.
+func (s *Simulator) mock(key request, obj client.Object) {
+	mock := s.mocks.ForJob(obj.GetName())
+
+	s.setPhase(key, obj.DeepCopyObject().(client.Object), v1alpha1.Lifecycle{
+		Phase:   v1alpha1.PhaseRunning,
+		Reason:  "Simulated",
+		Message: "the job is simulated",
+	})
+
+	if mock.Duration == nil {
+		return
+	}
+
+	s.schedule(s.clock.Now().Add(mock.Duration.Duration), func() {
+		job := obj.DeepCopyObject().(client.Object)
+
+		if err := s.client.Get(context.Background(), key.NamespacedName, job); err != nil || !job.GetDeletionTimestamp().IsZero() {
+			return
+		}
+
+		s.setPhase(key, job, v1alpha1.Lifecycle{
+			Phase:   mock.outcome(),
+			Reason:  "Simulated",
+			Message: fmt.Sprintf("the job has completed after %s", mock.Duration.Duration),
+		})
+	})
+}
+
+func (s *Simulator) setPhase(key request, job client.Object, status v1alpha1.Lifecycle) {
+	aware, ok := job.(v1alpha1.ReconcileStatusAware)
+	if !ok {
+		return
+	}
+
+	aware.SetReconcileStatus(status)
+
+	if err := s.client.Status().Update(context.Background(), job); err != nil {
+		s.logger.Info("Mock error", "kind", key.Kind, "obj", key.NamespacedName, "err", err)
+	}
+}
+
+func (s *Simulator) warn(msg string) {
+	s.warnings = append(s.warnings, msg)
+}
+
+// changed returns true if the objects differ in anything other than their resource version.
+func changed(prev, latest client.Object) bool {
+	a := prev.DeepCopyObject().(client.Object)
+	b := latest.DeepCopyObject().(client.Object)
+
+	a.SetResourceVersion("")
+	b.SetResourceVersion("")
+
+	return !equality.Semantic.DeepEqual(a, b)
+}
+
+func phaseOf(obj client.Object) v1alpha1.Phase {
+	if aware, ok := obj.(v1alpha1.ReconcileStatusAware); ok {
+		return aware.GetReconcileStatus().Phase
+	}
+
+	return ""
+}
+
+// hasMetricsExpr returns true if any expression of the scenario depends on the telemetry.
+func hasMetricsExpr(test *v1alpha1.Scenario) bool {
+	exprs := []*v1alpha1.ConditionalExpr{test.Spec.SuccessWhen, test.Spec.FailWhen}
+
+	for _, action := range append(append([]v1alpha1.Action{}, test.Spec.Actions...), test.Spec.Finally...) {
+		exprs = append(exprs, action.Assert, action.When)
+
+		if action.EmbedActions == nil {
+			continue
+		}
+
+		if action.Wait != nil {
+			exprs = append(exprs, action.Wait.Until)
+		}
+
+		if action.Cluster != nil {
+			exprs = append(exprs, action.Cluster.SuspendWhen)
+
+			if action.Cluster.Schedule != nil {
+				exprs = append(exprs, action.Cluster.Schedule.Event)
+			}
+		}
+
+		if action.Cascade != nil {
+			exprs = append(exprs, action.Cascade.SuspendWhen)
+
+			if action.Cascade.Schedule != nil {
+				exprs = append(exprs, action.Cascade.Schedule.Event)
+			}
+		}
+	}
+
+	for _, expr := range exprs {
+		for _, leaf := range expr.Leaves() {
+			if leaf.HasMetricsExpr() || leaf.HasPromQLExpr() {
+				return true
+			}
+		}
+	}
+
+	if test.Spec.Repeat != nil && len(test.Spec.Repeat.Metrics) > 0 {
+		return true
+	}
+
+	for _, postCondition := range test.Spec.PostConditions {
+		if postCondition.Value != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,420 @@
+/*
+Copyright 2021-2023 ICS-FORTH.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-ics-forth/frisbee/api/v1alpha1"
+	"github.com/carv-ics-forth/frisbee/pkg/simulator"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const testTemplate = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Template
+metadata:
+  name: whalesay
+spec:
+  service:
+    containers:
+      - name: main
+        image: docker/whalesay
+`
+
+const testScenario = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: simulation
+spec:
+  actions:
+    - action: Service
+      name: server
+      service:
+        templateRef: whalesay
+
+    - action: Cluster
+      name: clients
+      depends: { running: [ server ] }
+      cluster:
+        templateRef: whalesay
+        instances: 2
+
+    - action: Delete
+      name: teardown
+      depends: { running: [ server ], success: [ clients ] }
+      delete:
+        jobs: [ server ]
+`
+
+func TestRun(t *testing.T) {
+	var template v1alpha1.Template
+
+	if err := yaml.Unmarshal([]byte(testTemplate), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	tests := []struct {
+		name      string
+		mocks     simulator.Mocks
+		wantPhase v1alpha1.Phase
+		wantEnd   time.Duration
+	}{
+		{
+			name: "servers run until deleted",
+			mocks: simulator.Mocks{Jobs: []simulator.MockJob{
+				{Match: "server"},
+				{Match: "clients-*", Duration: duration(30 * time.Second)},
+			}},
+			wantPhase: v1alpha1.PhaseSuccess,
+			wantEnd:   30 * time.Second,
+		},
+		{
+			name: "failed client",
+			mocks: simulator.Mocks{Jobs: []simulator.MockJob{
+				{Match: "server"},
+				{Match: "clients-2", Duration: duration(10 * time.Second), Outcome: v1alpha1.PhaseFailed},
+				{Match: "clients-*", Duration: duration(30 * time.Second)},
+			}},
+			wantPhase: v1alpha1.PhaseFailed,
+			wantEnd:   10 * time.Second,
+		},
+		{
+			name:      "server completes before the teardown",
+			mocks:     simulator.Mocks{},
+			wantPhase: v1alpha1.PhaseFailed,
+			wantEnd:   simulator.DefaultJobDuration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scenario v1alpha1.Scenario
+
+			if err := yaml.Unmarshal([]byte(testScenario), &scenario); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := simulator.Run(context.Background(), &scenario, []v1alpha1.Template{template}, simulator.Options{
+				Mocks: tt.mocks,
+			})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if phase := result.Scenario.Status.Phase; phase != tt.wantPhase {
+				t.Errorf("Run() phase = %v, want %v (%s)", phase, tt.wantPhase, result.Scenario.Status.Message)
+			}
+
+			if end := result.EndTime.Sub(result.StartTime); end != tt.wantEnd {
+				t.Errorf("Run() duration = %v, want %v", end, tt.wantEnd)
+			}
+
+			if len(result.Warnings) > 0 {
+				t.Errorf("Run() warnings = %v", result.Warnings)
+			}
+		})
+	}
+}
+
+func TestRun_Timeline(t *testing.T) {
+	var template v1alpha1.Template
+
+	if err := yaml.Unmarshal([]byte(testTemplate), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	var scenario v1alpha1.Scenario
+
+	if err := yaml.Unmarshal([]byte(testScenario), &scenario); err != nil {
+		t.Fatal(err)
+	}
+
+	mocks := simulator.Mocks{Jobs: []simulator.MockJob{
+		{Match: "server"},
+		{Match: "clients-*", Duration: &metav1.Duration{Duration: 2 * time.Minute}},
+	}}
+
+	result, err := simulator.Run(context.Background(), &scenario, []v1alpha1.Template{template}, simulator.Options{Mocks: mocks})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string]struct {
+		phase     v1alpha1.Phase
+		children  int
+		completed time.Duration
+	}{
+		"server":   {phase: v1alpha1.PhaseRunning, children: 1, completed: -1},
+		"clients":  {phase: v1alpha1.PhaseSuccess, children: 2, completed: 2 * time.Minute},
+		"teardown": {phase: v1alpha1.PhaseSuccess, children: 0, completed: 2 * time.Minute},
+	}
+
+	timeline := result.Scenario.Status.Timeline
+
+	if len(timeline) != len(want) {
+		t.Fatalf("Timeline = %v, want %d records", timeline, len(want))
+	}
+
+	for _, record := range timeline {
+		expected, ok := want[record.Name]
+		if !ok {
+			t.Errorf("unexpected record '%s'", record.Name)
+
+			continue
+		}
+
+		if record.Phase != expected.phase {
+			t.Errorf("'%s' phase = %v, want %v", record.Name, record.Phase, expected.phase)
+		}
+
+		if record.Children != expected.children {
+			t.Errorf("'%s' children = %d, want %d", record.Name, record.Children, expected.children)
+		}
+
+		switch {
+		case expected.completed < 0 && record.CompletedAt != nil:
+			t.Errorf("'%s' is completed, but it should not", record.Name)
+		case expected.completed >= 0 && record.CompletedAt == nil:
+			t.Errorf("'%s' is not completed", record.Name)
+		case expected.completed >= 0 && record.CompletedAt.Sub(result.StartTime) != expected.completed:
+			t.Errorf("'%s' completed at +%v, want +%v", record.Name, record.CompletedAt.Sub(result.StartTime), expected.completed)
+		}
+	}
+}
+
+func TestMocks_ForJob(t *testing.T) {
+	mocks := simulator.Mocks{
+		Default: &simulator.MockJob{Duration: &metav1.Duration{Duration: 5 * time.Minute}},
+		Jobs: []simulator.MockJob{
+			{Match: "server"},
+			{Match: "clients-*", Outcome: v1alpha1.PhaseFailed},
+		},
+	}
+
+	if err := mocks.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		job          string
+		wantDuration *metav1.Duration
+		wantOutcome  v1alpha1.Phase
+	}{
+		{job: "server", wantDuration: nil},
+		{job: "clients-1", wantDuration: nil, wantOutcome: v1alpha1.PhaseFailed},
+		{job: "other", wantDuration: &metav1.Duration{Duration: 5 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.job, func(t *testing.T) {
+			mock := mocks.ForJob(tt.job)
+
+			if !reflect.DeepEqual(mock.Duration, tt.wantDuration) {
+				t.Errorf("ForJob() duration = %v, want %v", mock.Duration, tt.wantDuration)
+			}
+
+			if mock.Outcome != tt.wantOutcome {
+				t.Errorf("ForJob() outcome = %v, want %v", mock.Outcome, tt.wantOutcome)
+			}
+		})
+	}
+}
+
+const testCallTemplate = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Template
+metadata:
+  name: echo
+spec:
+  service:
+    containers:
+      - name: main
+        image: busybox
+    callables:
+      hello:
+        container: main
+        command: [ "echo", "hello" ]
+`
+
+const testCallScenario = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: simulation
+spec:
+  actions:
+    - action: Service
+      name: server
+      service:
+        templateRef: echo
+
+    - action: Call
+      name: hello
+      depends: { running: [ server ] }
+      call:
+        callable: hello
+        services: [ server ]
+
+    - action: Delete
+      name: teardown
+      depends: { running: [ server ], success: [ hello ] }
+      delete:
+        jobs: [ server ]
+`
+
+func TestRun_Call(t *testing.T) {
+	var template v1alpha1.Template
+
+	if err := yaml.Unmarshal([]byte(testCallTemplate), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	var scenario v1alpha1.Scenario
+
+	if err := yaml.Unmarshal([]byte(testCallScenario), &scenario); err != nil {
+		t.Fatal(err)
+	}
+
+	mocks := simulator.Mocks{
+		Jobs:  []simulator.MockJob{{Match: "server"}},
+		Calls: []simulator.MockJob{{Match: "server", Duration: &metav1.Duration{Duration: 40 * time.Second}, Stdout: "hello"}},
+	}
+
+	result, err := simulator.Run(context.Background(), &scenario, []v1alpha1.Template{template}, simulator.Options{Mocks: mocks})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if phase := result.Scenario.Status.Phase; phase != v1alpha1.PhaseSuccess {
+		t.Fatalf("Run() phase = %v, want %v (%s)", phase, v1alpha1.PhaseSuccess, result.Scenario.Status.Message)
+	}
+
+	// the call completes once its mocked duration has elapsed, and so do the actions that depend on it.
+	want := 40 * time.Second
+
+	if end := result.EndTime.Sub(result.StartTime); end != want {
+		t.Errorf("Run() duration = %v, want %v", end, want)
+	}
+
+	for _, record := range result.Scenario.Status.Timeline {
+		if record.Name != "hello" {
+			continue
+		}
+
+		if record.CompletedAt == nil || record.CompletedAt.Sub(result.StartTime) != want {
+			t.Errorf("'hello' completed at %v, want +%v", record.CompletedAt, want)
+		}
+	}
+
+	for _, event := range result.Events {
+		if event.Reason == "VExecSuccess" && event.Kind == "Call" && event.Time.Sub(result.StartTime) != want {
+			t.Errorf("'%s' event at +%v, want +%v", event.Reason, event.Time.Sub(result.StartTime), want)
+		}
+	}
+}
+
+const testScaleScenario = `
+apiVersion: frisbee.dev/v1alpha1
+kind: Scenario
+metadata:
+  name: simulation
+spec:
+  actions:
+    - action: Service
+      name: server
+      service:
+        templateRef: whalesay
+
+    - action: Cluster
+      name: clients
+      cluster:
+        templateRef: whalesay
+        instances: 2
+
+    - action: Scale
+      name: grow
+      depends: { running: [ clients ] }
+      scale:
+        cluster: clients
+        by: 1
+
+    - action: Delete
+      name: teardown
+      depends: { running: [ server ], success: [ clients, grow ] }
+      delete:
+        jobs: [ server ]
+`
+
+func TestRun_Scale(t *testing.T) {
+	var template v1alpha1.Template
+
+	if err := yaml.Unmarshal([]byte(testTemplate), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	var scenario v1alpha1.Scenario
+
+	if err := yaml.Unmarshal([]byte(testScaleScenario), &scenario); err != nil {
+		t.Fatal(err)
+	}
+
+	mocks := simulator.Mocks{Jobs: []simulator.MockJob{
+		{Match: "server"},
+		{Match: "clients-*", Duration: &metav1.Duration{Duration: time.Minute}},
+	}}
+
+	result, err := simulator.Run(context.Background(), &scenario, []v1alpha1.Template{template}, simulator.Options{Mocks: mocks})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if phase := result.Scenario.Status.Phase; phase != v1alpha1.PhaseSuccess {
+		t.Fatalf("Run() phase = %v, want %v (%s)", phase, v1alpha1.PhaseSuccess, result.Scenario.Status.Message)
+	}
+
+	// the scale is completed once the cluster has scheduled the added instance.
+	want := map[string]struct {
+		phase    v1alpha1.Phase
+		children int
+	}{
+		"clients": {phase: v1alpha1.PhaseSuccess, children: 3},
+		"grow":    {phase: v1alpha1.PhaseSuccess, children: 0},
+	}
+
+	for _, record := range result.Scenario.Status.Timeline {
+		expected, ok := want[record.Name]
+		if !ok {
+			continue
+		}
+
+		if record.Phase != expected.phase {
+			t.Errorf("'%s' phase = %v, want %v", record.Name, record.Phase, expected.phase)
+		}
+
+		if record.Children != expected.children {
+			t.Errorf("'%s' children = %d, want %d", record.Name, record.Children, expected.children)
+		}
+	}
+}